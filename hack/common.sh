@@ -52,6 +52,7 @@ readonly OS_ALL_BINARIES=("${OS_ALL_TARGETS[@]##*/}")
 #If you update this list, be sure to get the images/origin/Dockerfile
 readonly OPENSHIFT_BINARY_SYMLINKS=(
   openshift-router
+  openshift-f5-router
   openshift-deploy
   openshift-sti-build
   openshift-docker-build
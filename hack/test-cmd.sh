@@ -188,6 +188,7 @@ echo "templates: ok"
 [ "$(openshift cli config 2>&1)" ]
 [ "$(openshift ex tokens)" ]
 [ "$(openshift admin policy  2>&1)" ]
+[ "$(openshift admin prune images --help 2>&1)" ]
 [ "$(openshift kubectl 2>&1)" ]
 [ "$(openshift kube 2>&1)" ]
 [ "$(openshift admin 2>&1)" ]
@@ -297,6 +298,11 @@ osc get images
 osc get imageStreams
 osc get imageStreamTag test:sometag
 osc get imageStreamImage test@sha256:4986bf8c15363d1c5d15512d5266f8777bfba4974ac56e3270e7760f6f0a8125
+osc tag test:sometag test:promoted
+osc get imageStreamTag test:promoted
+osc tag --alias test:sometag test:alias
+osc tag --source=docker mysql:latest test:external
+[ "$(osc get imageStreams test -t "{{range .spec.tags}}{{.name}} {{end}}" | grep alias)" ]
 osc delete imageStreams test
 echo "imageStreamMappings: ok"
 
@@ -364,6 +370,9 @@ osc get deploymentConfigs
 osc get dc
 osc create -f test/integration/fixtures/test-deployment-config.json
 osc describe deploymentConfigs test-deployment-config
+osc deploy test-deployment-config
+osc deploy history test-deployment-config
+osc scale dc/test-deployment-config --replicas=2
 osc delete deploymentConfigs test-deployment-config
 echo "deploymentConfigs: ok"
 
@@ -451,6 +460,9 @@ echo "ex registry: ok"
 # verify the image repository had its tags populated
 [ -n "$(osc get imageStreams wildfly-8-centos -t "{{.status.tags.latest}}")" ]
 [ -n "$(osc get imageStreams wildfly-8-centos -t "{{ index .metadata.annotations \"openshift.io/image.dockerRepositoryCheck\"}}")" ]
+osc import-image wildfly-8-centos
+[ -n "$(osc get imageStreams wildfly-8-centos -t "{{ index .metadata.annotations \"openshift.io/image.dockerRepositoryCheck\"}}")" ]
+echo "import-image: ok"
 
 # Test building a dependency tree
 [ "$(openshift ex build-chain --all -o dot | grep 'graph')" ]
@@ -69,9 +69,10 @@ func (c *ImageChangeController) HandleImageRepo(repo *imageapi.ImageStream) erro
 				continue
 			}
 
-			// (must be different) to trigger a build
+			// (must be different) to trigger a build. The image is pinned by its immutable
+			// ID where possible so that the build uses exactly the image that fired the trigger.
 			last := change.LastTriggeredImageID
-			next := latest.DockerImageReference
+			next := imageapi.PinnedImageReference(latest)
 
 			if len(last) == 0 || (len(next) > 0 && next != last) {
 				change.LastTriggeredImageID = next
@@ -416,3 +416,65 @@ func TestNewImageIDNoDockerRepo(t *testing.T) {
 		t.Error("BuildConfig was updated when no change happened!")
 	}
 }
+
+func TestNewImageIDOtherNamespace(t *testing.T) {
+	// the buildconfig references an image stream in another namespace, new build should be triggered.
+	buildcfg := mockBuildConfig("registry.com/namespace/imagename", "registry.com/namespace/imagename", "testImageStream", "testTag")
+	buildcfg.Triggers[0].ImageChange.From.Namespace = "othernamespace"
+	imageStream := mockImageStream("testImageStream", "registry.com/namespace/imagename", map[string]string{"testTag": "newImageID123"})
+	imageStream.Namespace = "othernamespace"
+	controller := mockImageChangeController(buildcfg, imageStream)
+	bcInstantiator := controller.BuildConfigInstantiator.(*buildConfigInstantiator)
+	bcUpdater := controller.BuildConfigUpdater.(*mockBuildConfigUpdater)
+
+	err := controller.HandleImageRepo(imageStream)
+	if err != nil {
+		t.Errorf("Unexpected error %v from HandleImageRepo", err)
+	}
+	if len(bcInstantiator.name) == 0 {
+		t.Error("Expected build generation when new image was created!")
+	}
+	if bcUpdater.buildcfg == nil {
+		t.Fatalf("Expected buildConfig update when new image was created!")
+	}
+}
+
+func TestNewImageIDPinnedByDigest(t *testing.T) {
+	// the tag points to a content addressable image, so the build should be pinned to it.
+	buildcfg := mockBuildConfig("registry.com/namespace/imagename", "registry.com/namespace/imagename", "testImageStream", "testTag")
+	imageStream := mockImageStream("testImageStream", "registry.com/namespace/imagename", map[string]string{})
+	imageStream.Status.Tags["testTag"] = imageapi.TagEventList{
+		Items: []imageapi.TagEvent{
+			{
+				Image:                "sha256:3c87c572822935df60f0f5d3665bd376841a7fcfeb806b5f212de6a00e9a7b25",
+				DockerImageReference: "registry.com/namespace/imagename:testTag",
+			},
+		},
+	}
+	expected := imageapi.DockerImageReference{
+		Registry:  "registry.com",
+		Namespace: "namespace",
+		Name:      "imagename",
+		ID:        "sha256:3c87c572822935df60f0f5d3665bd376841a7fcfeb806b5f212de6a00e9a7b25",
+	}.String()
+	controller := mockImageChangeController(buildcfg, imageStream)
+	bcInstantiator := controller.BuildConfigInstantiator.(*buildConfigInstantiator)
+	bcUpdater := controller.BuildConfigUpdater.(*mockBuildConfigUpdater)
+
+	err := controller.HandleImageRepo(imageStream)
+	if err != nil {
+		t.Errorf("Unexpected error %v from HandleImageRepo", err)
+	}
+	if len(bcInstantiator.name) == 0 {
+		t.Fatalf("Expected build generation when new image was created!")
+	}
+	if actual := bcInstantiator.newBuild.Parameters.Strategy.DockerStrategy.Image; actual != expected {
+		t.Errorf("Image substitutions not properly setup for new build. Expected %s, got %s |", expected, actual)
+	}
+	if bcUpdater.buildcfg == nil {
+		t.Fatalf("Expected buildConfig update when new image was created!")
+	}
+	if actual := bcUpdater.buildcfg.Triggers[0].ImageChange.LastTriggeredImageID; actual != expected {
+		t.Errorf("Expected last triggered image %q, got %q", expected, actual)
+	}
+}
@@ -154,8 +154,9 @@ func (g *BuildGenerator) generateBuildUsingObjectReference(ctx kapi.Context, con
 	glog.V(4).Infof("Found image repository %s", imageRepo.Name)
 	latest, err := imageapi.LatestTaggedImage(imageRepo, tag)
 	if err == nil {
-		glog.V(4).Infof("Using image %s for image repository %s in namespace %s", latest.DockerImageReference, from.Name, from.Namespace)
-		imageRepoSubstitutions[*from] = latest.DockerImageReference
+		image := imageapi.PinnedImageReference(latest)
+		glog.V(4).Infof("Using image %s for image repository %s in namespace %s", image, from.Name, from.Namespace)
+		imageRepoSubstitutions[*from] = image
 	} else {
 		return nil, fmt.Errorf("Docker Image Repository %s has no tag %s", from.Name, tag)
 	}
@@ -275,7 +276,7 @@ func (g *BuildGenerator) resolveImageRepoReference(ctx kapi.Context, from *kapi.
 	if err != nil {
 		return "", err
 	}
-	return latest.DockerImageReference, nil
+	return imageapi.PinnedImageReference(latest), nil
 }
 
 // getNextBuildName returns name of the next build and increments BuildConfig's LastVersion.
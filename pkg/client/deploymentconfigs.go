@@ -23,6 +23,8 @@ type DeploymentConfigInterface interface {
 	Watch(label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error)
 	Generate(name string) (*deployapi.DeploymentConfig, error)
 	Rollback(config *deployapi.DeploymentConfigRollback) (*deployapi.DeploymentConfig, error)
+	GetScale(name string) (*deployapi.DeploymentConfigScale, error)
+	UpdateScale(scale *deployapi.DeploymentConfigScale) (*deployapi.DeploymentConfigScale, error)
 }
 
 // deploymentConfigs implements DeploymentConfigsNamespacer interface
@@ -107,3 +109,17 @@ func (c *deploymentConfigs) Rollback(config *deployapi.DeploymentConfigRollback)
 		Into(result)
 	return
 }
+
+// GetScale returns the desired replica count of the deploymentConfig with the given name
+func (c *deploymentConfigs) GetScale(name string) (result *deployapi.DeploymentConfigScale, err error) {
+	result = &deployapi.DeploymentConfigScale{}
+	err = c.r.Get().Namespace(c.ns).Resource("deploymentConfigs").Name(name).SubResource("scale").Do().Into(result)
+	return
+}
+
+// UpdateScale scales a deploymentConfig and its active deployment
+func (c *deploymentConfigs) UpdateScale(scale *deployapi.DeploymentConfigScale) (result *deployapi.DeploymentConfigScale, err error) {
+	result = &deployapi.DeploymentConfigScale{}
+	err = c.r.Put().Namespace(c.ns).Resource("deploymentConfigs").Name(scale.Name).SubResource("scale").Body(scale).Do().Into(result)
+	return
+}
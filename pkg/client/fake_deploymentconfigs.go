@@ -54,3 +54,13 @@ func (c *FakeDeploymentConfigs) Rollback(config *deployapi.DeploymentConfigRollb
 	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "rollback"})
 	return nil, nil
 }
+
+func (c *FakeDeploymentConfigs) GetScale(name string) (*deployapi.DeploymentConfigScale, error) {
+	obj, err := c.Fake.Invokes(FakeAction{Action: "get-deploymentconfigscale", Value: name}, &deployapi.DeploymentConfigScale{})
+	return obj.(*deployapi.DeploymentConfigScale), err
+}
+
+func (c *FakeDeploymentConfigs) UpdateScale(scale *deployapi.DeploymentConfigScale) (*deployapi.DeploymentConfigScale, error) {
+	obj, err := c.Fake.Invokes(FakeAction{Action: "update-deploymentconfigscale", Value: scale}, &deployapi.DeploymentConfigScale{})
+	return obj.(*deployapi.DeploymentConfigScale), err
+}
@@ -34,7 +34,12 @@ func (c *FakeImageStreams) Create(repo *imageapi.ImageStream) (*imageapi.ImageSt
 }
 
 func (c *FakeImageStreams) Update(repo *imageapi.ImageStream) (*imageapi.ImageStream, error) {
-	obj, err := c.Fake.Invokes(FakeAction{Action: "update-imagestream"}, &imageapi.ImageStream{})
+	obj, err := c.Fake.Invokes(FakeAction{Action: "update-imagestream", Value: repo}, &imageapi.ImageStream{})
+	return obj.(*imageapi.ImageStream), err
+}
+
+func (c *FakeImageStreams) UpdateStatus(repo *imageapi.ImageStream) (*imageapi.ImageStream, error) {
+	obj, err := c.Fake.Invokes(FakeAction{Action: "update-status-imagestream", Value: repo}, &imageapi.ImageStream{})
 	return obj.(*imageapi.ImageStream), err
 }
 
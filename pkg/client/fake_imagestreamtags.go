@@ -17,8 +17,18 @@ type FakeImageStreamTags struct {
 var _ ImageStreamTagInterface = &FakeImageStreamTags{}
 
 func (c *FakeImageStreamTags) Get(name, tag string) (result *imageapi.ImageStreamTag, err error) {
-	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "get-imagestream-tag", Value: fmt.Sprintf("%s:%s", name, tag)})
-	return &imageapi.ImageStreamTag{}, nil
+	obj, err := c.Fake.Invokes(FakeAction{Action: "get-imagestream-tag", Value: fmt.Sprintf("%s:%s", name, tag)}, &imageapi.ImageStreamTag{})
+	return obj.(*imageapi.ImageStreamTag), err
+}
+
+func (c *FakeImageStreamTags) Create(tag *imageapi.ImageStreamTag) (*imageapi.ImageStreamTag, error) {
+	obj, err := c.Fake.Invokes(FakeAction{Action: "create-imagestream-tag", Value: tag}, &imageapi.ImageStreamTag{})
+	return obj.(*imageapi.ImageStreamTag), err
+}
+
+func (c *FakeImageStreamTags) Update(tag *imageapi.ImageStreamTag) (*imageapi.ImageStreamTag, error) {
+	obj, err := c.Fake.Invokes(FakeAction{Action: "update-imagestream-tag", Value: tag}, &imageapi.ImageStreamTag{})
+	return obj.(*imageapi.ImageStreamTag), err
 }
 
 func (c *FakeImageStreamTags) Delete(name, tag string) error {
@@ -35,6 +35,11 @@ func (c *FakeRoutes) Update(route *routeapi.Route) (*routeapi.Route, error) {
 	return obj.(*routeapi.Route), err
 }
 
+func (c *FakeRoutes) UpdateStatus(route *routeapi.Route) (*routeapi.Route, error) {
+	obj, err := c.Fake.Invokes(FakeAction{Action: "update-status-route", Value: route}, &routeapi.Route{})
+	return obj.(*routeapi.Route), err
+}
+
 func (c *FakeRoutes) Delete(name string) error {
 	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "delete-route"})
 	return nil
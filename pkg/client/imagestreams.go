@@ -19,6 +19,7 @@ type ImageStreamInterface interface {
 	Get(name string) (*imageapi.ImageStream, error)
 	Create(stream *imageapi.ImageStream) (*imageapi.ImageStream, error)
 	Update(stream *imageapi.ImageStream) (*imageapi.ImageStream, error)
+	UpdateStatus(stream *imageapi.ImageStream) (*imageapi.ImageStream, error)
 	Delete(name string) error
 	Watch(label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error)
 }
@@ -83,6 +84,13 @@ func (c *imageStreams) Update(stream *imageapi.ImageStream) (result *imageapi.Im
 	return
 }
 
+// UpdateStatus updates the tag history of an image stream, returns error if one occurs.
+func (c *imageStreams) UpdateStatus(stream *imageapi.ImageStream) (result *imageapi.ImageStream, err error) {
+	result = &imageapi.ImageStream{}
+	err = c.r.Put().Namespace(c.ns).Resource("imageStreams").Name(stream.Name).SubResource("status").Body(stream).Do().Into(result)
+	return
+}
+
 // Delete deletes an image stream, returns error if one occurs.
 func (c *imageStreams) Delete(name string) (err error) {
 	err = c.r.Delete().Namespace(c.ns).Resource("imageStreams").Name(name).Do().Error()
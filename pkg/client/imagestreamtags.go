@@ -14,6 +14,8 @@ type ImageStreamTagsNamespacer interface {
 // ImageStreamTagInterface exposes methods on ImageStreamTag resources.
 type ImageStreamTagInterface interface {
 	Get(name, tag string) (*api.ImageStreamTag, error)
+	Create(tag *api.ImageStreamTag) (*api.ImageStreamTag, error)
+	Update(tag *api.ImageStreamTag) (*api.ImageStreamTag, error)
 	Delete(name, tag string) error
 }
 
@@ -38,6 +40,20 @@ func (c *imageStreamTags) Get(name, tag string) (result *api.ImageStreamTag, err
 	return
 }
 
+// Create sets a new tag on an image stream. The name of tag is of the form <stream>:<tag>.
+func (c *imageStreamTags) Create(tag *api.ImageStreamTag) (result *api.ImageStreamTag, err error) {
+	result = &api.ImageStreamTag{}
+	err = c.r.Post().Namespace(c.ns).Resource("imageStreamTags").Body(tag).Do().Into(result)
+	return
+}
+
+// Update sets a tag on an image stream, creating the tag if it does not exist.
+func (c *imageStreamTags) Update(tag *api.ImageStreamTag) (result *api.ImageStreamTag, err error) {
+	result = &api.ImageStreamTag{}
+	err = c.r.Put().Namespace(c.ns).Resource("imageStreamTags").Name(tag.Name).Body(tag).Do().Into(result)
+	return
+}
+
 // Delete deletes the specified tag from the image stream.
 func (c *imageStreamTags) Delete(name, tag string) error {
 	return c.r.Delete().Namespace(c.ns).Resource("imageStreamTags").Name(fmt.Sprintf("%s:%s", name, tag)).Do().Error()
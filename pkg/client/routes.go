@@ -19,6 +19,7 @@ type RouteInterface interface {
 	Get(name string) (*routeapi.Route, error)
 	Create(route *routeapi.Route) (*routeapi.Route, error)
 	Update(route *routeapi.Route) (*routeapi.Route, error)
+	UpdateStatus(route *routeapi.Route) (*routeapi.Route, error)
 	Delete(name string) error
 	Watch(label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error)
 }
@@ -76,6 +77,14 @@ func (c *routes) Update(route *routeapi.Route) (result *routeapi.Route, err erro
 	return
 }
 
+// UpdateStatus updates the status of a route, which records the routers which admitted it.  Returns the server's
+// representation of the route, and an error, if it occurs
+func (c *routes) UpdateStatus(route *routeapi.Route) (result *routeapi.Route, err error) {
+	result = &routeapi.Route{}
+	err = c.r.Put().Namespace(c.ns).Resource("routes").Name(route.Name).SubResource("status").Body(route).Do().Into(result)
+	return
+}
+
 // Watch returns a watch.Interface that watches the requested routes.
 func (c *routes) Watch(label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error) {
 	return c.r.Get().
@@ -10,6 +10,7 @@ import (
 	"github.com/openshift/origin/pkg/cmd/experimental/buildchain"
 	"github.com/openshift/origin/pkg/cmd/experimental/policy"
 	"github.com/openshift/origin/pkg/cmd/experimental/project"
+	"github.com/openshift/origin/pkg/cmd/experimental/prune"
 	exregistry "github.com/openshift/origin/pkg/cmd/experimental/registry"
 	exrouter "github.com/openshift/origin/pkg/cmd/experimental/router"
 	"github.com/openshift/origin/pkg/cmd/server/admin"
@@ -46,6 +47,7 @@ func NewCommandAdmin(name, fullName string, out io.Writer) *cobra.Command {
 	cmds.AddCommand(exrouter.NewCmdRouter(f, fullName, "router", out))
 	cmds.AddCommand(exregistry.NewCmdRegistry(f, fullName, "registry", out))
 	cmds.AddCommand(buildchain.NewCmdBuildChain(f, fullName, "build-chain"))
+	cmds.AddCommand(prune.NewCmdPrune(f, fullName, "prune", out))
 	cmds.AddCommand(cmd.NewCmdConfig(fullName, "config"))
 
 	// TODO: these probably belong in a sub command
@@ -70,7 +70,11 @@ func NewCommandCLI(name, fullName string) *cobra.Command {
 	cmds.AddCommand(cmd.NewCmdStartBuild(fullName, f, out))
 	cmds.AddCommand(cmd.NewCmdCancelBuild(fullName, f, out))
 	cmds.AddCommand(cmd.NewCmdBuildLogs(fullName, f, out))
+	cmds.AddCommand(cmd.NewCmdDeploy(fullName, f, out))
 	cmds.AddCommand(cmd.NewCmdRollback(fullName, f, out))
+	cmds.AddCommand(cmd.NewCmdScale(fullName, f, out))
+	cmds.AddCommand(cmd.NewCmdTag(fullName, f, out))
+	cmds.AddCommand(cmd.NewCmdImportImage(fullName, f, out))
 	cmds.AddCommand(cmd.NewCmdGet(fullName, f, out))
 	cmds.AddCommand(cmd.NewCmdDescribe(fullName, f, out))
 	// Deprecate 'osc apply' with 'osc create' command.
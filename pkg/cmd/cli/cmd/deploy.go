@@ -0,0 +1,427 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	cmdutil "github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl/cmd/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/spf13/cobra"
+
+	latest "github.com/openshift/origin/pkg/api/latest"
+	"github.com/openshift/origin/pkg/client"
+	"github.com/openshift/origin/pkg/cmd/util/clientcmd"
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	deployutil "github.com/openshift/origin/pkg/deploy/util"
+)
+
+// DeployOptions holds all the options for the `deploy` command
+type DeployOptions struct {
+	out        io.Writer
+	osClient   client.Interface
+	kubeClient kclient.Interface
+	namespace  string
+
+	deploymentConfigName string
+	deployLatest         bool
+	retryDeploy          bool
+	cancelDeploy         bool
+}
+
+const deployLongDesc = `
+View, start, cancel, or retry deployments.
+
+This command allows you to control a deployment config. With no options, the
+status of the latest deployment is printed.
+
+Pass '--latest' to start a new deployment of the current configuration. A
+deployment which is in progress can be stopped with '--cancel', which kills the
+deployer pod and restores the replica count of the last successful deployment.
+A failed or cancelled deployment can be restarted with '--retry'.
+
+Examples:
+
+	# Display the latest deployment for the 'frontend' deployment config
+	$ %[1]s deploy frontend
+
+	# Start a new deployment based on the 'frontend' deployment config
+	$ %[1]s deploy frontend --latest
+
+	# Retry the latest failed deployment of the 'frontend' deployment config
+	$ %[1]s deploy frontend --retry
+
+	# Cancel the in-progress deployment of the 'frontend' deployment config
+	$ %[1]s deploy frontend --cancel
+
+	# List the deployments of the 'frontend' deployment config
+	$ %[1]s deploy history frontend
+`
+
+const deployHistoryLongDesc = `
+List the deployments of a deployment config.
+
+Each deployment is shown with its version, status, the causes which triggered
+it and the images it runs, newest first.
+
+Examples:
+
+	# List the deployments of the 'frontend' deployment config
+	$ %[1]s deploy history frontend
+`
+
+// NewCmdDeploy implements the OpenShift cli deploy command
+func NewCmdDeploy(fullName string, f *clientcmd.Factory, out io.Writer) *cobra.Command {
+	options := &DeployOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "deploy <deploymentConfig> [--latest|--retry|--cancel]",
+		Short: "View, start, cancel, or retry deployments",
+		Long:  fmt.Sprintf(deployLongDesc, fullName),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := options.Complete(f, args, out); err != nil {
+				cmdutil.CheckErr(cmdutil.UsageError(cmd, "%v", err))
+			}
+
+			if err := options.Validate(); err != nil {
+				cmdutil.CheckErr(cmdutil.UsageError(cmd, "%v", err))
+			}
+
+			if err := options.RunDeploy(); err != nil {
+				cmdutil.CheckErr(err)
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&options.deployLatest, "latest", false, "Start a new deployment now.")
+	cmd.Flags().BoolVar(&options.retryDeploy, "retry", false, "Retry the latest failed deployment.")
+	cmd.Flags().BoolVar(&options.cancelDeploy, "cancel", false, "Cancel the in-progress deployment.")
+
+	cmd.AddCommand(NewCmdDeployHistory(fullName, f, out))
+
+	return cmd
+}
+
+// NewCmdDeployHistory implements the OpenShift cli deploy history command
+func NewCmdDeployHistory(fullName string, f *clientcmd.Factory, out io.Writer) *cobra.Command {
+	options := &DeployOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "history <deploymentConfig>",
+		Short: "List the deployments of a deployment config",
+		Long:  fmt.Sprintf(deployHistoryLongDesc, fullName),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := options.Complete(f, args, out); err != nil {
+				cmdutil.CheckErr(cmdutil.UsageError(cmd, "%v", err))
+			}
+
+			if err := options.RunHistory(); err != nil {
+				cmdutil.CheckErr(err)
+			}
+		},
+	}
+
+	return cmd
+}
+
+// Complete sets up the clients and the target deployment config from the
+// arguments.
+func (o *DeployOptions) Complete(f *clientcmd.Factory, args []string, out io.Writer) error {
+	if len(args) != 1 || len(args[0]) == 0 {
+		return errors.New("a deployment config name is required.")
+	}
+	o.deploymentConfigName = args[0]
+	o.out = out
+
+	var err error
+	if o.osClient, o.kubeClient, err = f.Clients(); err != nil {
+		return err
+	}
+	if o.namespace, err = f.DefaultNamespace(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate ensures at most one deploy operation was requested.
+func (o DeployOptions) Validate() error {
+	numOptions := 0
+	for _, set := range []bool{o.deployLatest, o.retryDeploy, o.cancelDeploy} {
+		if set {
+			numOptions++
+		}
+	}
+	if numOptions > 1 {
+		return errors.New("only one of --latest, --retry, or --cancel is allowed.")
+	}
+	return nil
+}
+
+// RunDeploy performs the requested operation on the deployment config.
+func (o DeployOptions) RunDeploy() error {
+	config, err := o.osClient.DeploymentConfigs(o.namespace).Get(o.deploymentConfigName)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case o.deployLatest:
+		return o.deploy(config)
+	case o.retryDeploy:
+		return o.retry(config)
+	case o.cancelDeploy:
+		return o.cancel(config)
+	default:
+		return o.describe(config)
+	}
+}
+
+// RunHistory prints the deployments of the deployment config.
+func (o DeployOptions) RunHistory() error {
+	config, err := o.osClient.DeploymentConfigs(o.namespace).Get(o.deploymentConfigName)
+	if err != nil {
+		return err
+	}
+
+	list, err := o.kubeClient.ReplicationControllers(o.namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	deployments := deployutil.ConfigDeployments(list, config.Name)
+	if len(deployments) == 0 {
+		fmt.Fprintf(o.out, "No deployments found for %s\n", config.Name)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(o.out, 10, 4, 3, ' ', 0)
+	defer w.Flush()
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tCAUSE\tIMAGES")
+	for i := range deployments {
+		deployment := &deployments[i]
+
+		causes := "<unknown>"
+		if deploymentConfig, err := deployutil.DecodeDeploymentConfig(deployment, latest.Codec); err == nil {
+			causes = formatDeploymentCauses(deploymentConfig.Details)
+		}
+
+		status := string(deployutil.DeploymentStatusFor(deployment))
+		if deployutil.IsDeploymentCancelled(deployment) {
+			status += " (cancelled)"
+		}
+
+		images := []string{}
+		if deployment.Spec.Template != nil {
+			for _, container := range deployment.Spec.Template.Spec.Containers {
+				images = append(images, container.Image)
+			}
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n",
+			deployutil.DeploymentVersionFor(deployment),
+			deployment.Name,
+			status,
+			causes,
+			strings.Join(images, ","))
+	}
+	return nil
+}
+
+// describe prints the status of the latest deployment of config.
+func (o DeployOptions) describe(config *deployapi.DeploymentConfig) error {
+	if config.LatestVersion == 0 {
+		fmt.Fprintf(o.out, "%s has not been deployed yet\n", config.Name)
+		return nil
+	}
+
+	deployment, err := o.kubeClient.ReplicationControllers(config.Namespace).Get(deployutil.LatestDeploymentNameForConfig(config))
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			fmt.Fprintf(o.out, "%s #%d is waiting to be created\n", config.Name, config.LatestVersion)
+			return nil
+		}
+		return err
+	}
+
+	status := string(deployutil.DeploymentStatusFor(deployment))
+	if deployutil.IsDeploymentCancelled(deployment) {
+		status += " (cancelled)"
+	}
+	fmt.Fprintf(o.out, "%s #%d is %s\n", config.Name, config.LatestVersion, strings.ToLower(status))
+	return nil
+}
+
+// deploy starts a new deployment of config unless a deployment is already in
+// progress.
+func (o DeployOptions) deploy(config *deployapi.DeploymentConfig) error {
+	if config.LatestVersion > 0 {
+		deployment, err := o.kubeClient.ReplicationControllers(config.Namespace).Get(deployutil.LatestDeploymentNameForConfig(config))
+		if err != nil {
+			if !kerrors.IsNotFound(err) {
+				return err
+			}
+		} else if isDeploymentInProgress(deployment) {
+			return fmt.Errorf("%s #%d is already in progress (%s).", config.Name, config.LatestVersion, deployutil.DeploymentStatusFor(deployment))
+		}
+	}
+
+	config.LatestVersion++
+	config.Details = &deployapi.DeploymentDetails{
+		Causes: []*deployapi.DeploymentCause{
+			{Type: deployapi.DeploymentTriggerManual},
+		},
+	}
+	updated, err := o.osClient.DeploymentConfigs(config.Namespace).Update(config)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.out, "Started deployment #%d\n", updated.LatestVersion)
+	return nil
+}
+
+// retry resets the latest deployment of config to new, causing a new deployer
+// pod to be created for it. Only failed deployments can be retried.
+func (o DeployOptions) retry(config *deployapi.DeploymentConfig) error {
+	if config.LatestVersion == 0 {
+		return fmt.Errorf("no deployments found for %s", config.Name)
+	}
+
+	deployment, err := o.kubeClient.ReplicationControllers(config.Namespace).Get(deployutil.LatestDeploymentNameForConfig(config))
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return fmt.Errorf("unable to find the latest deployment (#%d).", config.LatestVersion)
+		}
+		return err
+	}
+
+	if status := deployutil.DeploymentStatusFor(deployment); status != deployapi.DeploymentStatusFailed {
+		return fmt.Errorf("#%d is %s; only failed deployments can be retried.", config.LatestVersion, status)
+	}
+
+	// Remove the failed deployer pod so the deployment controller can create
+	// a fresh one.
+	if err := o.deleteDeployerPod(deployment); err != nil {
+		return err
+	}
+
+	deployment.Annotations[deployapi.DeploymentStatusAnnotation] = string(deployapi.DeploymentStatusNew)
+	delete(deployment.Annotations, deployapi.DeploymentCancelledAnnotation)
+	delete(deployment.Annotations, deployapi.DeploymentPodAnnotation)
+	if _, err := o.kubeClient.ReplicationControllers(deployment.Namespace).Update(deployment); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.out, "Retried #%d\n", config.LatestVersion)
+	return nil
+}
+
+// cancel stops the in-progress deployment of config. The deployment is marked
+// as cancelled and failed, its deployer pod is killed, and the last successful
+// deployment is scaled back up.
+func (o DeployOptions) cancel(config *deployapi.DeploymentConfig) error {
+	if config.LatestVersion == 0 {
+		return fmt.Errorf("no deployments found for %s", config.Name)
+	}
+
+	rcClient := o.kubeClient.ReplicationControllers(config.Namespace)
+	deployment, err := rcClient.Get(deployutil.LatestDeploymentNameForConfig(config))
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return fmt.Errorf("unable to find the latest deployment (#%d).", config.LatestVersion)
+		}
+		return err
+	}
+
+	if !isDeploymentInProgress(deployment) {
+		fmt.Fprintf(o.out, "No deployment is in progress (#%d is %s)\n", config.LatestVersion, deployutil.DeploymentStatusFor(deployment))
+		return nil
+	}
+
+	// Mark the deployment first so the controllers stop acting on it before
+	// the deployer pod goes away.
+	deployment.Annotations[deployapi.DeploymentCancelledAnnotation] = deployapi.DeploymentCancelledAnnotationValue
+	deployment.Annotations[deployapi.DeploymentStatusAnnotation] = string(deployapi.DeploymentStatusFailed)
+	deployment.Spec.Replicas = 0
+	if _, err := rcClient.Update(deployment); err != nil {
+		return err
+	}
+
+	if err := o.deleteDeployerPod(deployment); err != nil {
+		return err
+	}
+
+	list, err := rcClient.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, prior := range deployutil.ConfigDeployments(list, config.Name) {
+		if deployutil.DeploymentVersionFor(&prior) >= config.LatestVersion ||
+			deployutil.DeploymentStatusFor(&prior) != deployapi.DeploymentStatusComplete {
+			continue
+		}
+
+		priorConfig, err := deployutil.DecodeDeploymentConfig(&prior, latest.Codec)
+		if err != nil {
+			return err
+		}
+		if replicas := priorConfig.Template.ControllerTemplate.Replicas; prior.Spec.Replicas != replicas {
+			prior.Spec.Replicas = replicas
+			if _, err := rcClient.Update(&prior); err != nil {
+				return err
+			}
+			fmt.Fprintf(o.out, "Restored %s to %d replicas\n", prior.Name, replicas)
+		}
+		break
+	}
+
+	fmt.Fprintf(o.out, "Cancelled #%d\n", config.LatestVersion)
+	return nil
+}
+
+// deleteDeployerPod deletes the deployer pod recorded on deployment, if any.
+func (o DeployOptions) deleteDeployerPod(deployment *kapi.ReplicationController) error {
+	podName := deployment.Annotations[deployapi.DeploymentPodAnnotation]
+	if len(podName) == 0 {
+		return nil
+	}
+	if err := o.kubeClient.Pods(deployment.Namespace).Delete(podName); err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("couldn't delete deployer pod %s: %v", podName, err)
+	}
+	return nil
+}
+
+// isDeploymentInProgress returns true if deployment has not reached a
+// terminal status.
+func isDeploymentInProgress(deployment *kapi.ReplicationController) bool {
+	switch deployutil.DeploymentStatusFor(deployment) {
+	case deployapi.DeploymentStatusNew,
+		deployapi.DeploymentStatusPending,
+		deployapi.DeploymentStatusRunning:
+		return true
+	}
+	return false
+}
+
+// formatDeploymentCauses builds a human readable summary of the causes of a
+// deployment.
+func formatDeploymentCauses(details *deployapi.DeploymentDetails) string {
+	if details == nil || len(details.Causes) == 0 {
+		return "<unknown>"
+	}
+	causes := []string{}
+	for _, cause := range details.Causes {
+		switch {
+		case cause.Type == deployapi.DeploymentTriggerOnImageChange && cause.ImageTrigger != nil:
+			causes = append(causes, fmt.Sprintf("%s(%s)", cause.Type, cause.ImageTrigger.RepositoryName))
+		default:
+			causes = append(causes, string(cause.Type))
+		}
+	}
+	return strings.Join(causes, ",")
+}
@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	ktc "github.com/GoogleCloudPlatform/kubernetes/pkg/client/testclient"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	"github.com/openshift/origin/pkg/api/latest"
+	"github.com/openshift/origin/pkg/client"
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	deploytest "github.com/openshift/origin/pkg/deploy/api/test"
+	deployutil "github.com/openshift/origin/pkg/deploy/util"
+)
+
+func deploymentFor(config *deployapi.DeploymentConfig, status deployapi.DeploymentStatus) *kapi.ReplicationController {
+	deployment, _ := deployutil.MakeDeployment(config, latest.Codec)
+	deployment.Annotations[deployapi.DeploymentStatusAnnotation] = string(status)
+	deployment.Annotations[deployapi.DeploymentPodAnnotation] = "deploy-" + deployment.Name
+	return deployment
+}
+
+func fakeConfigClient(config *deployapi.DeploymentConfig, updated **deployapi.DeploymentConfig) *client.Fake {
+	return &client.Fake{
+		ReactFn: func(action ktc.FakeAction) (runtime.Object, error) {
+			switch action.Action {
+			case "get-deploymentconfig":
+				return config, nil
+			case "update-deploymentconfig":
+				*updated = config
+				return config, nil
+			}
+			return nil, nil
+		},
+	}
+}
+
+// TestCmdDeploy_latestOk ensures that --latest bumps the config version when
+// no deployment is in progress.
+func TestCmdDeploy_latestOk(t *testing.T) {
+	var updatedConfig *deployapi.DeploymentConfig
+	config := deploytest.OkDeploymentConfig(1)
+
+	o := &DeployOptions{
+		out:      ioutil.Discard,
+		osClient: fakeConfigClient(config, &updatedConfig),
+		kubeClient: &ktc.Fake{
+			ReactFn: func(action ktc.FakeAction) (runtime.Object, error) {
+				return deploymentFor(config, deployapi.DeploymentStatusComplete), nil
+			},
+		},
+		deploymentConfigName: config.Name,
+		deployLatest:         true,
+	}
+
+	if err := o.RunDeploy(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if updatedConfig == nil {
+		t.Fatalf("expected updated config")
+	}
+	if e, a := 2, updatedConfig.LatestVersion; e != a {
+		t.Fatalf("expected version %d, got %d", e, a)
+	}
+	if updatedConfig.Details == nil || len(updatedConfig.Details.Causes) != 1 || updatedConfig.Details.Causes[0].Type != deployapi.DeploymentTriggerManual {
+		t.Fatalf("expected a manual cause, got %#v", updatedConfig.Details)
+	}
+}
+
+// TestCmdDeploy_latestConcurrentRejection ensures that --latest is rejected
+// while a deployment is in progress.
+func TestCmdDeploy_latestConcurrentRejection(t *testing.T) {
+	var updatedConfig *deployapi.DeploymentConfig
+	config := deploytest.OkDeploymentConfig(1)
+
+	for _, status := range []deployapi.DeploymentStatus{deployapi.DeploymentStatusNew, deployapi.DeploymentStatusPending, deployapi.DeploymentStatusRunning} {
+		o := &DeployOptions{
+			out:      ioutil.Discard,
+			osClient: fakeConfigClient(config, &updatedConfig),
+			kubeClient: &ktc.Fake{
+				ReactFn: func(action ktc.FakeAction) (runtime.Object, error) {
+					return deploymentFor(config, status), nil
+				},
+			},
+			deploymentConfigName: config.Name,
+			deployLatest:         true,
+		}
+
+		if err := o.RunDeploy(); err == nil {
+			t.Errorf("expected an error for status %s", status)
+		}
+		if updatedConfig != nil {
+			t.Fatalf("unexpected config update for status %s", status)
+		}
+	}
+}
+
+// TestCmdDeploy_retryOk ensures that a failed deployment is reset to new and
+// its deployer pod is deleted.
+func TestCmdDeploy_retryOk(t *testing.T) {
+	var updatedConfig *deployapi.DeploymentConfig
+	var updatedDeployment *kapi.ReplicationController
+	var deletedPod string
+	config := deploytest.OkDeploymentConfig(1)
+	existing := deploymentFor(config, deployapi.DeploymentStatusFailed)
+	existing.Annotations[deployapi.DeploymentCancelledAnnotation] = deployapi.DeploymentCancelledAnnotationValue
+
+	o := &DeployOptions{
+		out:      ioutil.Discard,
+		osClient: fakeConfigClient(config, &updatedConfig),
+		kubeClient: &ktc.Fake{
+			ReactFn: func(action ktc.FakeAction) (runtime.Object, error) {
+				switch action.Action {
+				case "get-replicationController":
+					return existing, nil
+				case "update-replicationController":
+					updatedDeployment = action.Value.(*kapi.ReplicationController)
+					return updatedDeployment, nil
+				case "delete-pod":
+					deletedPod = action.Value.(string)
+					return nil, nil
+				}
+				t.Fatalf("unexpected action %s", action.Action)
+				return nil, nil
+			},
+		},
+		deploymentConfigName: config.Name,
+		retryDeploy:          true,
+	}
+
+	if err := o.RunDeploy(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if updatedDeployment == nil {
+		t.Fatalf("expected updated deployment")
+	}
+	if e, a := deployapi.DeploymentStatusNew, deployutil.DeploymentStatusFor(updatedDeployment); e != a {
+		t.Fatalf("expected status %s, got %s", e, a)
+	}
+	if deployutil.IsDeploymentCancelled(updatedDeployment) {
+		t.Fatalf("expected cancelled annotation to be cleared")
+	}
+	if e, a := "deploy-config-1", deletedPod; e != a {
+		t.Fatalf("expected deleted pod %s, got %s", e, a)
+	}
+	if updatedConfig != nil {
+		t.Fatalf("unexpected config update")
+	}
+}
+
+// TestCmdDeploy_retryRejectNonFailed ensures that only failed deployments
+// can be retried.
+func TestCmdDeploy_retryRejectNonFailed(t *testing.T) {
+	var updatedConfig *deployapi.DeploymentConfig
+	config := deploytest.OkDeploymentConfig(1)
+
+	for _, status := range []deployapi.DeploymentStatus{deployapi.DeploymentStatusNew, deployapi.DeploymentStatusRunning, deployapi.DeploymentStatusComplete} {
+		o := &DeployOptions{
+			out:      ioutil.Discard,
+			osClient: fakeConfigClient(config, &updatedConfig),
+			kubeClient: &ktc.Fake{
+				ReactFn: func(action ktc.FakeAction) (runtime.Object, error) {
+					if action.Action != "get-replicationController" {
+						t.Fatalf("unexpected action %s", action.Action)
+					}
+					return deploymentFor(config, status), nil
+				},
+			},
+			deploymentConfigName: config.Name,
+			retryDeploy:          true,
+		}
+
+		if err := o.RunDeploy(); err == nil {
+			t.Errorf("expected an error for status %s", status)
+		}
+	}
+}
+
+// TestCmdDeploy_cancelOk ensures that an in-progress deployment is marked
+// cancelled and scaled down, its deployer pod deleted, and the last complete
+// deployment is scaled back up.
+func TestCmdDeploy_cancelOk(t *testing.T) {
+	var updatedConfig *deployapi.DeploymentConfig
+	updatedDeployments := map[string]*kapi.ReplicationController{}
+	var deletedPod string
+
+	config := deploytest.OkDeploymentConfig(3)
+	config.Template.ControllerTemplate.Replicas = 1
+	running := deploymentFor(config, deployapi.DeploymentStatusRunning)
+	running.Spec.Replicas = 1
+
+	priorConfig := deploytest.OkDeploymentConfig(2)
+	priorConfig.Template.ControllerTemplate.Replicas = 2
+	prior := deploymentFor(priorConfig, deployapi.DeploymentStatusComplete)
+	failed := deploymentFor(deploytest.OkDeploymentConfig(1), deployapi.DeploymentStatusFailed)
+
+	o := &DeployOptions{
+		out:      ioutil.Discard,
+		osClient: fakeConfigClient(config, &updatedConfig),
+		kubeClient: &ktc.Fake{
+			ReactFn: func(action ktc.FakeAction) (runtime.Object, error) {
+				switch action.Action {
+				case "get-replicationController":
+					if action.Value.(string) != running.Name {
+						return nil, kerrors.NewNotFound("ReplicationController", action.Value.(string))
+					}
+					return running, nil
+				case "list-replicationControllers":
+					return &kapi.ReplicationControllerList{
+						Items: []kapi.ReplicationController{*failed, *prior, *running},
+					}, nil
+				case "update-replicationController":
+					rc := action.Value.(*kapi.ReplicationController)
+					updatedDeployments[rc.Name] = rc
+					return rc, nil
+				case "delete-pod":
+					deletedPod = action.Value.(string)
+					return nil, nil
+				}
+				t.Fatalf("unexpected action %s", action.Action)
+				return nil, nil
+			},
+		},
+		deploymentConfigName: config.Name,
+		cancelDeploy:         true,
+	}
+
+	if err := o.RunDeploy(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cancelled, ok := updatedDeployments[running.Name]
+	if !ok {
+		t.Fatalf("expected %s to be updated", running.Name)
+	}
+	if !deployutil.IsDeploymentCancelled(cancelled) {
+		t.Fatalf("expected %s to be cancelled", running.Name)
+	}
+	if e, a := deployapi.DeploymentStatusFailed, deployutil.DeploymentStatusFor(cancelled); e != a {
+		t.Fatalf("expected status %s, got %s", e, a)
+	}
+	if e, a := 0, cancelled.Spec.Replicas; e != a {
+		t.Fatalf("expected %d replicas for cancelled deployment, got %d", e, a)
+	}
+	if e, a := "deploy-"+running.Name, deletedPod; e != a {
+		t.Fatalf("expected deleted pod %s, got %s", e, a)
+	}
+
+	restored, ok := updatedDeployments[prior.Name]
+	if !ok {
+		t.Fatalf("expected %s to be restored", prior.Name)
+	}
+	if e, a := 2, restored.Spec.Replicas; e != a {
+		t.Fatalf("expected %d replicas for restored deployment, got %d", e, a)
+	}
+	if _, ok := updatedDeployments[failed.Name]; ok {
+		t.Fatalf("unexpected update of failed deployment %s", failed.Name)
+	}
+}
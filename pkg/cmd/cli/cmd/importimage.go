@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	cmdutil "github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl/cmd/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/wait"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/origin/pkg/client"
+	"github.com/openshift/origin/pkg/cmd/util/clientcmd"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+)
+
+// ImportImageOptions holds all the options for the `import-image` command
+type ImportImageOptions struct {
+	out       io.Writer
+	osClient  client.Interface
+	namespace string
+
+	name         string
+	timeout      time.Duration
+	pollInterval time.Duration
+}
+
+const importImageLongDesc = `
+Import the latest images of an image stream from their external Docker repositories.
+
+Image streams with a Docker image repository, and image stream tags set from an external
+Docker pull spec, are imported once when they are created. Set the annotation
+openshift.io/image.importInterval on the image stream, or on one of its tags, to a duration
+such as "1h" to import them again that often. This command imports them now. A new tag
+event is recorded only for the tags whose upstream image changed.
+
+Images in private registries are imported with the credentials in the secrets of the
+project which hold a .dockercfg file.
+
+Examples:
+
+	# Import the latest images of the 'centos' image stream
+	$ %[1]s import-image centos
+`
+
+// NewCmdImportImage implements the OpenShift cli import-image command
+func NewCmdImportImage(fullName string, f *clientcmd.Factory, out io.Writer) *cobra.Command {
+	options := &ImportImageOptions{
+		timeout:      time.Minute,
+		pollInterval: time.Second,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "import-image IMAGESTREAM",
+		Short: "Import the latest images of an image stream from external repositories",
+		Long:  fmt.Sprintf(importImageLongDesc, fullName),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := options.Complete(f, args, out); err != nil {
+				cmdutil.CheckErr(cmdutil.UsageError(cmd, "%v", err))
+			}
+
+			if err := options.RunImportImage(); err != nil {
+				cmdutil.CheckErr(err)
+			}
+		},
+	}
+
+	cmd.Flags().DurationVar(&options.timeout, "timeout", options.timeout, "How long to wait for the import to finish.")
+
+	return cmd
+}
+
+// Complete sets up the client and records the image stream to import.
+func (o *ImportImageOptions) Complete(f *clientcmd.Factory, args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return errors.New("exactly one image stream name is required.")
+	}
+	o.out = out
+	o.name = args[0]
+
+	var err error
+	if o.osClient, _, err = f.Clients(); err != nil {
+		return err
+	}
+	if o.namespace, err = f.DefaultNamespace(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// RunImportImage clears the import checks recorded on the image stream, so that it
+// is imported again, and waits for the import to finish.
+func (o ImportImageOptions) RunImportImage() error {
+	streams := o.osClient.ImageStreams(o.namespace)
+	stream, err := streams.Get(o.name)
+	if err != nil {
+		return err
+	}
+
+	tags := []string{}
+	for tag, tagRef := range stream.Spec.Tags {
+		if tagRef.From == nil && len(tagRef.DockerImageReference) > 0 {
+			tags = append(tags, tag)
+		}
+	}
+	if len(stream.Spec.DockerImageRepository) == 0 && len(tags) == 0 {
+		return fmt.Errorf("image stream %s has no Docker image repository or tags to import", o.name)
+	}
+
+	delete(stream.Annotations, imageapi.DockerImageRepositoryCheckAnnotation)
+	delete(stream.Annotations, imageapi.DockerImageRepositoryCheckErrorAnnotation)
+	for _, tag := range tags {
+		tagRef := stream.Spec.Tags[tag]
+		annotations := make(map[string]string)
+		for k, v := range tagRef.Annotations {
+			if k != imageapi.DockerImageRepositoryCheckAnnotation && k != imageapi.DockerImageRepositoryCheckErrorAnnotation {
+				annotations[k] = v
+			}
+		}
+		tagRef.Annotations = annotations
+		stream.Spec.Tags[tag] = tagRef
+	}
+	if _, err := streams.Update(stream); err != nil {
+		return err
+	}
+
+	var reasons []string
+	err = wait.Poll(o.pollInterval, o.timeout, func() (bool, error) {
+		if stream, err = streams.Get(o.name); err != nil {
+			return false, err
+		}
+		reasons = []string{}
+		checks := []map[string]string{}
+		if len(stream.Spec.DockerImageRepository) > 0 {
+			checks = append(checks, stream.Annotations)
+		}
+		for _, tag := range tags {
+			checks = append(checks, stream.Spec.Tags[tag].Annotations)
+		}
+		for _, annotations := range checks {
+			if len(annotations[imageapi.DockerImageRepositoryCheckAnnotation]) == 0 {
+				return false, nil
+			}
+			if reason := annotations[imageapi.DockerImageRepositoryCheckErrorAnnotation]; len(reason) > 0 {
+				reasons = append(reasons, reason)
+			}
+		}
+		return true, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("timed out waiting for the import of image stream %s", o.name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(reasons) > 0 {
+		return fmt.Errorf("unable to import image stream %s: %v", o.name, reasons)
+	}
+
+	fmt.Fprintf(o.out, "Imported image stream %s\n", o.name)
+	o.describeTags(stream)
+	return nil
+}
+
+// describeTags prints the current image of each tag of stream.
+func (o ImportImageOptions) describeTags(stream *imageapi.ImageStream) {
+	tags := []string{}
+	for tag := range stream.Status.Tags {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	w := tabwriter.NewWriter(o.out, 10, 4, 3, ' ', 0)
+	defer w.Flush()
+	fmt.Fprintln(w, "TAG\tPULL SPEC\tIMAGE")
+	for _, tag := range tags {
+		event, err := imageapi.LatestTaggedImage(stream, tag)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", tag, event.DockerImageReference, event.Image)
+	}
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	ktc "github.com/GoogleCloudPlatform/kubernetes/pkg/client/testclient"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	"github.com/openshift/origin/pkg/client"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+)
+
+func TestCmdImportImage(t *testing.T) {
+	checked := time.Now().UTC().Format(time.RFC3339)
+	var updated *imageapi.ImageStream
+	gets := 0
+
+	out := &bytes.Buffer{}
+	o := &ImportImageOptions{
+		out: out,
+		osClient: &client.Fake{
+			ReactFn: func(action ktc.FakeAction) (runtime.Object, error) {
+				switch action.Action {
+				case "get-imagestream":
+					gets++
+					stream := &imageapi.ImageStream{
+						ObjectMeta: kapi.ObjectMeta{Name: "centos", Annotations: map[string]string{imageapi.DockerImageRepositoryCheckAnnotation: checked}},
+						Spec: imageapi.ImageStreamSpec{
+							Tags: map[string]imageapi.TagReference{
+								"7": {
+									DockerImageReference: "centos:7",
+									Annotations:          map[string]string{imageapi.DockerImageRepositoryCheckAnnotation: checked},
+								},
+							},
+						},
+						Status: imageapi.ImageStreamStatus{
+							Tags: map[string]imageapi.TagEventList{
+								"7": {Items: []imageapi.TagEvent{{DockerImageReference: "centos:7", Image: "abc"}}},
+							},
+						},
+					}
+					// the import has not finished on the first check
+					if gets == 2 {
+						stream.Spec.Tags["7"] = imageapi.TagReference{DockerImageReference: "centos:7"}
+					}
+					return stream, nil
+				case "update-imagestream":
+					updated = action.Value.(*imageapi.ImageStream)
+					return updated, nil
+				}
+				return nil, nil
+			},
+		},
+		namespace:    "ns",
+		name:         "centos",
+		timeout:      time.Second,
+		pollInterval: time.Millisecond,
+	}
+
+	if err := o.RunImportImage(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated == nil {
+		t.Fatalf("expected the image stream to be updated")
+	}
+	if _, ok := updated.Spec.Tags["7"].Annotations[imageapi.DockerImageRepositoryCheckAnnotation]; ok {
+		t.Errorf("expected the tag check to be cleared: %#v", updated.Spec.Tags["7"])
+	}
+	if gets != 3 {
+		t.Errorf("expected to wait for the import, got %d checks", gets-1)
+	}
+	if !strings.Contains(out.String(), "centos:7") {
+		t.Errorf("expected the imported tags to be described, got:\n%s", out.String())
+	}
+}
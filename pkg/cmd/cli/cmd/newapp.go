@@ -6,8 +6,10 @@ import (
 	"os"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
 	ctl "github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl"
 	cmdutil "github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl/cmd/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/errors"
 	"github.com/golang/glog"
 	"github.com/spf13/cobra"
@@ -16,6 +18,7 @@ import (
 	"github.com/openshift/origin/pkg/cmd/util/clientcmd"
 	dockerutil "github.com/openshift/origin/pkg/cmd/util/docker"
 	configcmd "github.com/openshift/origin/pkg/config/cmd"
+	"github.com/openshift/origin/pkg/dockerregistry"
 	newcmd "github.com/openshift/origin/pkg/generate/app/cmd"
 	imageapi "github.com/openshift/origin/pkg/image/api"
 	"github.com/openshift/origin/pkg/util"
@@ -117,11 +120,16 @@ func RunNewApplication(f *clientcmd.Factory, out io.Writer, c *cobra.Command, ar
 		}
 	}
 
-	osclient, _, err := f.Clients()
+	osclient, kclient, err := f.Clients()
 	if err != nil {
 		return err
 	}
 	config.SetOpenShiftClient(osclient, namespace)
+	if secrets, err := kclient.Secrets(namespace).List(labels.Everything(), fields.Everything()); err == nil {
+		config.SetDockerRegistryCredentials(dockerregistry.NewCredentialsForSecrets(secrets.Items))
+	} else {
+		glog.V(2).Infof("Unable to read the pull secrets of the project, Docker registries will be accessed anonymously: %v", err)
+	}
 
 	unknown := config.AddArguments(args)
 	if len(unknown) != 0 {
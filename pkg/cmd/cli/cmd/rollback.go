@@ -5,14 +5,19 @@ import (
 	"io"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	kubectl "github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl"
 	cmdutil "github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl/cmd/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/spf13/cobra"
 
 	latest "github.com/openshift/origin/pkg/api/latest"
+	"github.com/openshift/origin/pkg/client"
 	describe "github.com/openshift/origin/pkg/cmd/cli/describe"
 	"github.com/openshift/origin/pkg/cmd/util/clientcmd"
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	deployutil "github.com/openshift/origin/pkg/deploy/util"
 )
 
 const rollbackLongDesc = `
@@ -25,16 +30,31 @@ environment variables and volumes are included in rollbacks, so if you've
 recently updated security credentials in your environment your previous
 deployment may not have the correct values.
 
+The rollback target may be a deployment, or a deployment configuration. When a
+deployment configuration is given, it is rolled back to the deployment named by
+'--to-version', or to the last successful deployment before the current one if
+no version is given.
+
 If you would like to review the outcome of the rollback, pass '--dry-run' to print
-a human-readable representation of the updated deployment configuration instead of
-executing the rollback. This is useful if you're not quite sure what the outcome
-will be.
+a human-readable description of the changes the rollback would make to the triggers,
+strategy and template of the deployment configuration instead of executing the
+rollback. This is useful if you're not quite sure what the outcome will be.
+
+Any image change triggers on the deployment configuration may immediately deploy
+a newer image on top of the rollback. Pass '--disable-image-triggers' to turn
+off automatic image change triggers as part of the rollback.
 
 Examples:
 
 	# Perform a rollback
 	$ %[1]s rollback deployment-1
 
+	# Roll back the 'frontend' deployment configuration to the last successful deployment
+	$ %[1]s rollback frontend
+
+	# Roll back the 'frontend' deployment configuration to version 3
+	$ %[1]s rollback frontend --to-version=3
+
 	# See what the rollback will look like, but don't perform the rollback
 	$ %[1]s rollback deployment-1 --dry-run
 
@@ -51,7 +71,7 @@ func NewCmdRollback(fullName string, f *clientcmd.Factory, out io.Writer) *cobra
 	}
 
 	cmd := &cobra.Command{
-		Use:   "rollback <from-deployment>",
+		Use:   "rollback (<from-deployment>|<deploymentConfig> [--to-version=<version>])",
 		Short: "Revert part of an application back to a previous deployment.",
 		Long:  fmt.Sprintf(rollbackLongDesc, fullName),
 		Run: func(cmd *cobra.Command, args []string) {
@@ -63,7 +83,9 @@ func NewCmdRollback(fullName string, f *clientcmd.Factory, out io.Writer) *cobra
 	cmd.Flags().BoolVar(&rollback.Spec.IncludeTriggers, "change-triggers", false, "Include the previous deployment's triggers in the rollback")
 	cmd.Flags().BoolVar(&rollback.Spec.IncludeStrategy, "change-strategy", false, "Include the previous deployment's strategy in the rollback")
 	cmd.Flags().BoolVar(&rollback.Spec.IncludeReplicationMeta, "change-scaling-settings", false, "Include the previous deployment's replicationController replica count and selector in the rollback")
-	cmd.Flags().BoolP("dry-run", "d", false, "Instead of performing the rollback, describe what the rollback will look like in human-readable form")
+	cmd.Flags().Int("to-version", 0, "The version of the deployment configuration to roll back to. Only valid when rolling back a deployment configuration.")
+	cmd.Flags().Bool("disable-image-triggers", false, "Disable automatic image change triggers in the rollback so that it isn't replaced by a newer image")
+	cmd.Flags().BoolP("dry-run", "d", false, "Instead of performing the rollback, describe the changes the rollback will make in human-readable form")
 	cmd.Flags().StringP("output", "o", "", "Instead of performing the rollback, print the updated deployment configuration in the specified format (json|yaml|template|templatefile)")
 	cmd.Flags().StringP("template", "t", "", "Template string or path to template file to use when -o=template or -o=templatefile.")
 
@@ -73,16 +95,20 @@ func NewCmdRollback(fullName string, f *clientcmd.Factory, out io.Writer) *cobra
 // RunRollback contains all the necessary functionality for OpenShift cli rollback command
 func RunRollback(f *clientcmd.Factory, out io.Writer, cmd *cobra.Command, args []string, rollback *deployapi.DeploymentConfigRollback) error {
 	if len(args) == 0 || len(args[0]) == 0 {
-		return cmdutil.UsageError(cmd, "A deployment name is required.")
+		return cmdutil.UsageError(cmd, "A deployment or deployment configuration name is required.")
 	}
 
-	rollback.Spec.From.Name = args[0]
-
 	outputFormat := cmdutil.GetFlagString(cmd, "output")
 	outputTemplate := cmdutil.GetFlagString(cmd, "template")
 	dryRun := cmdutil.GetFlagBool(cmd, "dry-run")
+	toVersion := cmdutil.GetFlagInt(cmd, "to-version")
+	disableImageTriggers := cmdutil.GetFlagBool(cmd, "disable-image-triggers")
 
-	osClient, _, err := f.Clients()
+	if toVersion < 0 {
+		return cmdutil.UsageError(cmd, "--to-version may not be negative.")
+	}
+
+	osClient, kClient, err := f.Clients()
 	if err != nil {
 		return err
 	}
@@ -92,20 +118,30 @@ func RunRollback(f *clientcmd.Factory, out io.Writer, cmd *cobra.Command, args [
 		return err
 	}
 
+	// Find the deployment to roll back to
+	rollback.Spec.From.Name, err = findRollbackTarget(osClient, kClient, namespace, args[0], toVersion)
+	if err != nil {
+		return err
+	}
+
 	// Generate the rollback config
 	newConfig, err := osClient.DeploymentConfigs(namespace).Rollback(rollback)
 	if err != nil {
 		return err
 	}
 
-	// If dry-run is specified, describe the rollback and exit
+	disabled := []string{}
+	if disableImageTriggers {
+		disabled = disableAutomaticImageTriggers(newConfig)
+	}
+
+	// If dry-run is specified, describe the changes the rollback would make and exit
 	if dryRun {
-		describer := describe.NewDeploymentConfigDescriberForConfig(newConfig)
-		description, err := describer.Describe(newConfig.Namespace, newConfig.Name)
+		currentConfig, err := osClient.DeploymentConfigs(namespace).Get(newConfig.Name)
 		if err != nil {
 			return err
 		}
-		out.Write([]byte(description))
+		fmt.Fprint(out, describe.DescribeDeploymentConfigChanges(currentConfig, newConfig))
 		return nil
 	}
 
@@ -122,6 +158,69 @@ func RunRollback(f *clientcmd.Factory, out io.Writer, cmd *cobra.Command, args [
 	}
 
 	// Apply the rollback config
-	_, err = osClient.DeploymentConfigs(namespace).Update(newConfig)
-	return err
+	updatedConfig, err := osClient.DeploymentConfigs(namespace).Update(newConfig)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "#%d rolled back to %s\n", updatedConfig.LatestVersion, rollback.Spec.From.Name)
+	for _, trigger := range disabled {
+		fmt.Fprintf(out, "Disabled automatic image change trigger for %s\n", trigger)
+	}
+	return nil
+}
+
+// findRollbackTarget returns the name of the deployment to roll back to. If
+// name refers to a deployment configuration, the deployment is chosen by
+// toVersion, or is the last complete deployment before the current one if
+// toVersion is zero. Otherwise, name is assumed to be a deployment.
+func findRollbackTarget(osClient client.Interface, kClient kclient.Interface, namespace, name string, toVersion int) (string, error) {
+	config, err := osClient.DeploymentConfigs(namespace).Get(name)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return "", err
+		}
+		if toVersion > 0 {
+			return "", fmt.Errorf("--to-version requires a deployment configuration, and %q was not found", name)
+		}
+		return name, nil
+	}
+
+	if toVersion > 0 {
+		if toVersion >= config.LatestVersion {
+			return "", fmt.Errorf("version %d is not a previous deployment of %s (the latest version is %d)", toVersion, config.Name, config.LatestVersion)
+		}
+		return deployutil.DeploymentNameForConfigVersion(config.Name, toVersion), nil
+	}
+
+	deployments, err := kClient.ReplicationControllers(namespace).List(labels.Everything())
+	if err != nil {
+		return "", err
+	}
+	for _, deployment := range deployutil.ConfigDeployments(deployments, config.Name) {
+		if deployutil.DeploymentVersionFor(&deployment) < config.LatestVersion &&
+			deployutil.DeploymentStatusFor(&deployment) == deployapi.DeploymentStatusComplete {
+			return deployment.Name, nil
+		}
+	}
+	return "", fmt.Errorf("no previous successful deployment of %s was found", config.Name)
+}
+
+// disableAutomaticImageTriggers turns off automatic image change triggers on
+// config and returns a description of each trigger which was changed.
+func disableAutomaticImageTriggers(config *deployapi.DeploymentConfig) []string {
+	disabled := []string{}
+	for _, trigger := range config.Triggers {
+		if trigger.Type != deployapi.DeploymentTriggerOnImageChange || trigger.ImageChangeParams == nil || !trigger.ImageChangeParams.Automatic {
+			continue
+		}
+		trigger.ImageChangeParams.Automatic = false
+
+		repository := trigger.ImageChangeParams.From.Name
+		if len(repository) == 0 {
+			repository = trigger.ImageChangeParams.RepositoryName
+		}
+		disabled = append(disabled, fmt.Sprintf("%s:%s", repository, trigger.ImageChangeParams.Tag))
+	}
+	return disabled
 }
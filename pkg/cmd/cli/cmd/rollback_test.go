@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	ktc "github.com/GoogleCloudPlatform/kubernetes/pkg/client/testclient"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	"github.com/openshift/origin/pkg/client"
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	deploytest "github.com/openshift/origin/pkg/deploy/api/test"
+)
+
+func TestFindRollbackTarget(t *testing.T) {
+	config := deploytest.OkDeploymentConfig(4)
+	deployments := &kapi.ReplicationControllerList{
+		Items: []kapi.ReplicationController{
+			*deploymentFor(deploytest.OkDeploymentConfig(1), deployapi.DeploymentStatusComplete),
+			*deploymentFor(deploytest.OkDeploymentConfig(2), deployapi.DeploymentStatusComplete),
+			*deploymentFor(deploytest.OkDeploymentConfig(3), deployapi.DeploymentStatusFailed),
+			*deploymentFor(config, deployapi.DeploymentStatusComplete),
+		},
+	}
+
+	configClient := &client.Fake{
+		ReactFn: func(action ktc.FakeAction) (runtime.Object, error) {
+			return config, nil
+		},
+	}
+	missingConfigClient := &client.Fake{
+		ReactFn: func(action ktc.FakeAction) (runtime.Object, error) {
+			return &deployapi.DeploymentConfig{}, kerrors.NewNotFound("DeploymentConfig", "config-1")
+		},
+	}
+	kubeClient := &ktc.Fake{
+		ReactFn: func(action ktc.FakeAction) (runtime.Object, error) {
+			return deployments, nil
+		},
+	}
+
+	tests := []struct {
+		name         string
+		configClient client.Interface
+		target       string
+		toVersion    int
+		expected     string
+		expectErr    bool
+	}{
+		{
+			name:         "deployment name",
+			configClient: missingConfigClient,
+			target:       "config-1",
+			expected:     "config-1",
+		},
+		{
+			name:         "deployment name with version",
+			configClient: missingConfigClient,
+			target:       "config-1",
+			toVersion:    1,
+			expectErr:    true,
+		},
+		{
+			name:         "config with version",
+			configClient: configClient,
+			target:       "config",
+			toVersion:    1,
+			expected:     "config-1",
+		},
+		{
+			name:         "config with current version",
+			configClient: configClient,
+			target:       "config",
+			toVersion:    4,
+			expectErr:    true,
+		},
+		{
+			name:         "config without version skips failed deployments",
+			configClient: configClient,
+			target:       "config",
+			expected:     "config-2",
+		},
+	}
+
+	for _, test := range tests {
+		actual, err := findRollbackTarget(test.configClient, kubeClient, "", test.target, test.toVersion)
+		if test.expectErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if actual != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestDisableAutomaticImageTriggers(t *testing.T) {
+	config := deploytest.OkDeploymentConfig(1)
+	config.Triggers = append(config.Triggers, deploytest.OkConfigChangeTrigger())
+
+	disabled := disableAutomaticImageTriggers(config)
+
+	if e, a := 1, len(disabled); e != a {
+		t.Fatalf("expected %d disabled triggers, got %d", e, a)
+	}
+	if config.Triggers[0].ImageChangeParams.Automatic {
+		t.Fatalf("expected image change trigger to be disabled")
+	}
+}
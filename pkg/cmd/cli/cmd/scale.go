@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	cmdutil "github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl/cmd/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl/resource"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/origin/pkg/client"
+	"github.com/openshift/origin/pkg/cmd/util/clientcmd"
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+)
+
+// ScaleOptions holds all the options for the `scale` command
+type ScaleOptions struct {
+	out        io.Writer
+	osClient   client.Interface
+	kubeClient kclient.Interface
+	namespace  string
+
+	kind     string
+	name     string
+	replicas int
+}
+
+const scaleLongDesc = `
+Set a new size for a deployment config or replication controller.
+
+Scaling a deployment config updates the replica count of both the config and
+its active deployment, so the new size is kept by future deployments. Scaling
+is refused while a deployment of the config is in progress.
+
+Examples:
+
+	# Scale the 'frontend' deployment config to 3 replicas
+	$ %[1]s scale dc/frontend --replicas=3
+
+	# Scale the 'frontend-1' replication controller to 0 replicas
+	$ %[1]s scale rc frontend-1 --replicas=0
+`
+
+// NewCmdScale implements the OpenShift cli scale command
+func NewCmdScale(fullName string, f *clientcmd.Factory, out io.Writer) *cobra.Command {
+	options := &ScaleOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "scale (TYPE/NAME | TYPE NAME) --replicas=COUNT",
+		Short: "Set a new size for a deployment config or replication controller",
+		Long:  fmt.Sprintf(scaleLongDesc, fullName),
+		Run: func(cmd *cobra.Command, args []string) {
+			options.replicas = cmdutil.GetFlagInt(cmd, "replicas")
+
+			if err := options.Complete(f, args, out); err != nil {
+				cmdutil.CheckErr(cmdutil.UsageError(cmd, "%v", err))
+			}
+
+			if err := options.Validate(); err != nil {
+				cmdutil.CheckErr(cmdutil.UsageError(cmd, "%v", err))
+			}
+
+			if err := options.RunScale(); err != nil {
+				cmdutil.CheckErr(err)
+			}
+		},
+	}
+
+	cmd.Flags().Int("replicas", -1, "The new desired number of replicas. Required.")
+
+	return cmd
+}
+
+// Complete resolves the resource to scale from the arguments and sets up the
+// clients.
+func (o *ScaleOptions) Complete(f *clientcmd.Factory, args []string, out io.Writer) error {
+	if len(args) == 0 || len(args) > 2 {
+		return errors.New("a resource type and name are required.")
+	}
+	o.out = out
+
+	var err error
+	if o.osClient, o.kubeClient, err = f.Clients(); err != nil {
+		return err
+	}
+	if o.namespace, err = f.DefaultNamespace(); err != nil {
+		return err
+	}
+
+	mapper, typer := f.Object()
+	infos, err := resource.NewBuilder(mapper, typer, f.ClientMapperForCommand()).
+		NamespaceParam(o.namespace).DefaultNamespace().
+		ResourceTypeOrNameArgs(false, args...).
+		SingleResourceType().
+		Do().Infos()
+	if err != nil {
+		return err
+	}
+	if len(infos) != 1 {
+		return errors.New("exactly one resource must be specified.")
+	}
+	o.kind = infos[0].Mapping.Kind
+	o.name = infos[0].Name
+	return nil
+}
+
+// Validate ensures a non-negative replica count was requested.
+func (o ScaleOptions) Validate() error {
+	if o.replicas < 0 {
+		return errors.New("--replicas=COUNT is required, and COUNT must be greater than or equal to 0.")
+	}
+	return nil
+}
+
+// RunScale sets the replica count of the resource.
+func (o ScaleOptions) RunScale() error {
+	switch o.kind {
+	case "DeploymentConfig":
+		scale, err := o.osClient.DeploymentConfigs(o.namespace).GetScale(o.name)
+		if err != nil {
+			return err
+		}
+		scale.Replicas = o.replicas
+		if _, err := o.osClient.DeploymentConfigs(o.namespace).UpdateScale(scale); err != nil {
+			return err
+		}
+	case "ReplicationController":
+		controller, err := o.kubeClient.ReplicationControllers(o.namespace).Get(o.name)
+		if err != nil {
+			return err
+		}
+		if len(controller.Annotations[deployapi.DeploymentConfigAnnotation]) > 0 {
+			fmt.Fprintf(o.out, "Warning: %s is a deployment of %s; scale the deployment config to keep the new size\n", o.name, controller.Annotations[deployapi.DeploymentConfigAnnotation])
+		}
+		controller.Spec.Replicas = o.replicas
+		if _, err := o.kubeClient.ReplicationControllers(o.namespace).Update(controller); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("cannot scale a %s", o.kind)
+	}
+
+	fmt.Fprintf(o.out, "Scaled %s to %d\n", o.name, o.replicas)
+	return nil
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	ktc "github.com/GoogleCloudPlatform/kubernetes/pkg/client/testclient"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	"github.com/openshift/origin/pkg/client"
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+)
+
+// TestCmdScale_deploymentConfig ensures that scaling a deployment config goes
+// through the scale subresource.
+func TestCmdScale_deploymentConfig(t *testing.T) {
+	var updated *deployapi.DeploymentConfigScale
+
+	o := &ScaleOptions{
+		out: ioutil.Discard,
+		osClient: &client.Fake{
+			ReactFn: func(action ktc.FakeAction) (runtime.Object, error) {
+				switch action.Action {
+				case "get-deploymentconfigscale":
+					return &deployapi.DeploymentConfigScale{ObjectMeta: kapi.ObjectMeta{Name: "config"}, Replicas: 1}, nil
+				case "update-deploymentconfigscale":
+					updated = action.Value.(*deployapi.DeploymentConfigScale)
+					return updated, nil
+				}
+				return nil, nil
+			},
+		},
+		kubeClient: &ktc.Fake{
+			ReactFn: func(action ktc.FakeAction) (runtime.Object, error) {
+				t.Fatalf("unexpected kube client action: %s", action.Action)
+				return nil, nil
+			},
+		},
+		kind:     "DeploymentConfig",
+		name:     "config",
+		replicas: 3,
+	}
+
+	if err := o.RunScale(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if updated == nil {
+		t.Fatalf("expected the scale to be updated")
+	}
+	if e, a := 3, updated.Replicas; e != a {
+		t.Fatalf("expected replicas %d, got %d", e, a)
+	}
+}
+
+// TestCmdScale_replicationController ensures that scaling a replication
+// controller updates it directly.
+func TestCmdScale_replicationController(t *testing.T) {
+	var updated *kapi.ReplicationController
+
+	o := &ScaleOptions{
+		out: ioutil.Discard,
+		osClient: &client.Fake{
+			ReactFn: func(action ktc.FakeAction) (runtime.Object, error) {
+				t.Fatalf("unexpected client action: %s", action.Action)
+				return nil, nil
+			},
+		},
+		kubeClient: &ktc.Fake{
+			ReactFn: func(action ktc.FakeAction) (runtime.Object, error) {
+				switch action.Action {
+				case "get-replicationController":
+					return &kapi.ReplicationController{ObjectMeta: kapi.ObjectMeta{Name: "rc"}}, nil
+				case "update-replicationController":
+					updated = action.Value.(*kapi.ReplicationController)
+					return updated, nil
+				}
+				return nil, nil
+			},
+		},
+		kind:     "ReplicationController",
+		name:     "rc",
+		replicas: 2,
+	}
+
+	if err := o.RunScale(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if updated == nil {
+		t.Fatalf("expected the replication controller to be updated")
+	}
+	if e, a := 2, updated.Spec.Replicas; e != a {
+		t.Fatalf("expected replicas %d, got %d", e, a)
+	}
+}
+
+// TestCmdScale_unsupportedKind ensures other kinds are rejected.
+func TestCmdScale_unsupportedKind(t *testing.T) {
+	o := &ScaleOptions{
+		out:      ioutil.Discard,
+		kind:     "Pod",
+		name:     "pod",
+		replicas: 1,
+	}
+
+	if err := o.RunScale(); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	cmdutil "github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl/cmd/util"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/origin/pkg/client"
+	"github.com/openshift/origin/pkg/cmd/util/clientcmd"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+)
+
+// TagOptions holds all the options for the `tag` command
+type TagOptions struct {
+	out       io.Writer
+	osClient  client.Interface
+	namespace string
+
+	sourceKind string
+	alias      bool
+
+	source       string
+	destinations []string
+}
+
+const tagLongDesc = `
+Set an image stream tag to point to another image stream tag, image or Docker pull spec.
+
+By default, the tag is set to the image the source image stream tag points to right
+now, so later changes to the source are not copied. The source may be in another
+project, and you must be able to view the source image stream. Pass --alias to make
+the tag follow every change to a source tag in the same image stream.
+
+Examples:
+
+	# Promote the image 'myapp:latest' currently points to in the 'dev' project as 'myapp:prod'
+	$ %[1]s tag dev/myapp:latest myapp:prod
+
+	# Make 'myapp:stable' follow 'myapp:latest'
+	$ %[1]s tag --alias myapp:latest myapp:stable
+
+	# Tag a specific image
+	$ %[1]s tag myapp@sha256:c3d8a3642ebfa6bd1fd50c2b8b90e99d3e29af1eac88637678f982cde90993fb myapp:v1
+
+	# Tag an external Docker image
+	$ %[1]s tag --source=docker openshift/origin:latest origin:latest
+`
+
+// NewCmdTag implements the OpenShift cli tag command
+func NewCmdTag(fullName string, f *clientcmd.Factory, out io.Writer) *cobra.Command {
+	options := &TagOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "tag [--source=SOURCETYPE] SOURCE DEST [DEST ...]",
+		Short: "Tag existing images into image streams",
+		Long:  fmt.Sprintf(tagLongDesc, fullName),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := options.Complete(f, args, out); err != nil {
+				cmdutil.CheckErr(cmdutil.UsageError(cmd, "%v", err))
+			}
+
+			if err := options.Validate(); err != nil {
+				cmdutil.CheckErr(cmdutil.UsageError(cmd, "%v", err))
+			}
+
+			if err := options.RunTag(); err != nil {
+				cmdutil.CheckErr(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.sourceKind, "source", "", "Optional hint for the source type: 'imagestreamtag', 'imagestreamimage' or 'docker'. Defaults to 'imagestreamimage' when the source has an image ID and 'imagestreamtag' otherwise.")
+	cmd.Flags().BoolVar(&options.alias, "alias", false, "Make the destination tags follow every change to the source tag, which must be in the same image stream.")
+
+	return cmd
+}
+
+// Complete sets up the client and records the source and destinations.
+func (o *TagOptions) Complete(f *clientcmd.Factory, args []string, out io.Writer) error {
+	if len(args) < 2 {
+		return errors.New("a source and at least one destination are required.")
+	}
+	o.out = out
+	o.source = args[0]
+	o.destinations = args[1:]
+
+	var err error
+	if o.osClient, _, err = f.Clients(); err != nil {
+		return err
+	}
+	if o.namespace, err = f.DefaultNamespace(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Validate ensures the source type is known.
+func (o TagOptions) Validate() error {
+	switch o.sourceKind {
+	case "", "imagestreamtag", "imagestreamimage", "docker":
+	default:
+		return fmt.Errorf("--source must be one of 'imagestreamtag', 'imagestreamimage' or 'docker', not %q.", o.sourceKind)
+	}
+	if o.alias && o.sourceKind != "" && o.sourceKind != "imagestreamtag" {
+		return errors.New("--alias may only be used with an image stream tag source.")
+	}
+	return nil
+}
+
+// RunTag sets each destination tag from the source.
+func (o TagOptions) RunTag() error {
+	for _, destination := range o.destinations {
+		namespace, name, tag, id, err := parseStreamName(o.namespace, destination)
+		if err != nil {
+			return err
+		}
+		if len(id) > 0 {
+			return fmt.Errorf("the destination %q must be an image stream tag, not an image", destination)
+		}
+
+		tagRef, err := o.tagReference(namespace, name)
+		if err != nil {
+			return err
+		}
+
+		ist := &imageapi.ImageStreamTag{Tag: tagRef}
+		ist.Name = fmt.Sprintf("%s:%s", name, tag)
+		if _, err := o.osClient.ImageStreamTags(namespace).Update(ist); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.out, "Tag %s/%s set to %s\n", namespace, ist.Name, o.source)
+	}
+	return nil
+}
+
+// tagReference returns the spec tag which points a tag of the destination stream
+// at the source.
+func (o TagOptions) tagReference(destNamespace, destName string) (*imageapi.TagReference, error) {
+	if o.sourceKind == "docker" {
+		if _, err := imageapi.ParseDockerImageReference(o.source); err != nil {
+			return nil, err
+		}
+		return &imageapi.TagReference{DockerImageReference: o.source}, nil
+	}
+
+	namespace, name, tag, id, err := parseStreamName(o.namespace, o.source)
+	if err != nil {
+		return nil, fmt.Errorf("%v; use --source=docker to tag an external image", err)
+	}
+
+	kind := o.sourceKind
+	if len(kind) == 0 {
+		kind = "imagestreamtag"
+		if len(id) > 0 {
+			kind = "imagestreamimage"
+		}
+	}
+
+	switch {
+	case kind == "imagestreamimage":
+		if len(id) == 0 {
+			return nil, fmt.Errorf("the source %q must be of the form [namespace/]name@id", o.source)
+		}
+	case len(id) > 0:
+		return nil, fmt.Errorf("the source %q must be of the form [namespace/]name:tag", o.source)
+	case o.alias:
+		if namespace != destNamespace || name != destName {
+			return nil, errors.New("cannot set an alias across different image streams")
+		}
+		return &imageapi.TagReference{
+			From: &kapi.ObjectReference{Kind: "ImageStreamTag", Namespace: namespace, Name: fmt.Sprintf("%s:%s", name, tag)},
+		}, nil
+	default:
+		// copy the image the source tag points to now
+		ist, err := o.osClient.ImageStreamTags(namespace).Get(name, tag)
+		if err != nil {
+			return nil, err
+		}
+		id = ist.Image.Name
+	}
+	return &imageapi.TagReference{
+		From: &kapi.ObjectReference{Kind: "ImageStreamImage", Namespace: namespace, Name: fmt.Sprintf("%s@%s", name, id)},
+	}, nil
+}
+
+// parseStreamName splits [namespace/]name:tag or [namespace/]name@id into its parts,
+// defaulting the namespace and the tag.
+func parseStreamName(defaultNamespace, spec string) (namespace, name, tag, id string, err error) {
+	ref, err := imageapi.ParseDockerImageReference(spec)
+	if err != nil || len(ref.Registry) > 0 {
+		return "", "", "", "", fmt.Errorf("%q must be of the form [namespace/]name:tag or [namespace/]name@id", spec)
+	}
+	namespace, name, tag, id = ref.Namespace, ref.Name, ref.Tag, ref.ID
+	if len(namespace) == 0 {
+		namespace = defaultNamespace
+	}
+	if len(tag) == 0 && len(id) == 0 {
+		tag = imageapi.DefaultImageTag
+	}
+	return namespace, name, tag, id, nil
+}
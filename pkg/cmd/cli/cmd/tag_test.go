@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	ktc "github.com/GoogleCloudPlatform/kubernetes/pkg/client/testclient"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	"github.com/openshift/origin/pkg/client"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+)
+
+func fakeTagClient(updated map[string]*imageapi.TagReference) *client.Fake {
+	return &client.Fake{
+		ReactFn: func(action ktc.FakeAction) (runtime.Object, error) {
+			switch action.Action {
+			case "get-imagestream-tag":
+				ist := &imageapi.ImageStreamTag{}
+				ist.Name = "id"
+				return ist, nil
+			case "update-imagestream-tag":
+				ist := action.Value.(*imageapi.ImageStreamTag)
+				updated[ist.Name] = ist.Tag
+				return ist, nil
+			}
+			return nil, nil
+		},
+	}
+}
+
+func TestCmdTag(t *testing.T) {
+	tests := map[string]struct {
+		source       string
+		destinations []string
+		sourceKind   string
+		alias        bool
+		expected     map[string]*imageapi.TagReference
+		expectErr    bool
+	}{
+		"promote across namespaces": {
+			source:       "dev/myapp:latest",
+			destinations: []string{"myapp:prod"},
+			expected: map[string]*imageapi.TagReference{
+				"myapp:prod": {From: &kapi.ObjectReference{Kind: "ImageStreamImage", Namespace: "dev", Name: "myapp@id"}},
+			},
+		},
+		"image id": {
+			source:       "myapp@other",
+			destinations: []string{"myapp:v1", "copy"},
+			expected: map[string]*imageapi.TagReference{
+				"myapp:v1":    {From: &kapi.ObjectReference{Kind: "ImageStreamImage", Namespace: "prod", Name: "myapp@other"}},
+				"copy:latest": {From: &kapi.ObjectReference{Kind: "ImageStreamImage", Namespace: "prod", Name: "myapp@other"}},
+			},
+		},
+		"alias": {
+			source:       "myapp:latest",
+			destinations: []string{"prod/myapp:stable"},
+			alias:        true,
+			expected: map[string]*imageapi.TagReference{
+				"myapp:stable": {From: &kapi.ObjectReference{Kind: "ImageStreamTag", Namespace: "prod", Name: "myapp:latest"}},
+			},
+		},
+		"alias across streams": {
+			source:       "dev/myapp:latest",
+			destinations: []string{"myapp:stable"},
+			alias:        true,
+			expectErr:    true,
+		},
+		"docker": {
+			source:       "docker.io/openshift/origin:latest",
+			destinations: []string{"origin"},
+			sourceKind:   "docker",
+			expected: map[string]*imageapi.TagReference{
+				"origin:latest": {DockerImageReference: "docker.io/openshift/origin:latest"},
+			},
+		},
+		"pull spec without --source": {
+			source:       "docker.io/openshift/origin:latest",
+			destinations: []string{"origin"},
+			expectErr:    true,
+		},
+	}
+
+	for name, test := range tests {
+		updated := map[string]*imageapi.TagReference{}
+		o := &TagOptions{
+			out:          ioutil.Discard,
+			osClient:     fakeTagClient(updated),
+			namespace:    "prod",
+			sourceKind:   test.sourceKind,
+			alias:        test.alias,
+			source:       test.source,
+			destinations: test.destinations,
+		}
+
+		err := o.RunTag()
+		if test.expectErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(test.expected, updated) {
+			t.Errorf("%s: expected tags %#v, got %#v", name, test.expected, updated)
+		}
+	}
+}
@@ -1,11 +1,15 @@
 package describe
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
@@ -28,13 +32,15 @@ type deploymentDescriberClient interface {
 	getDeployment(namespace, name string) (*kapi.ReplicationController, error)
 	listPods(namespace string, selector labels.Selector) (*kapi.PodList, error)
 	listEvents(deploymentConfig *deployapi.DeploymentConfig) (*kapi.EventList, error)
+	listDeploymentEvents(deployment *kapi.ReplicationController) (*kapi.EventList, error)
 }
 
 type genericDeploymentDescriberClient struct {
-	getDeploymentConfigFunc func(namespace, name string) (*deployapi.DeploymentConfig, error)
-	getDeploymentFunc       func(namespace, name string) (*kapi.ReplicationController, error)
-	listPodsFunc            func(namespace string, selector labels.Selector) (*kapi.PodList, error)
-	listEventsFunc          func(deploymentConfig *deployapi.DeploymentConfig) (*kapi.EventList, error)
+	getDeploymentConfigFunc  func(namespace, name string) (*deployapi.DeploymentConfig, error)
+	getDeploymentFunc        func(namespace, name string) (*kapi.ReplicationController, error)
+	listPodsFunc             func(namespace string, selector labels.Selector) (*kapi.PodList, error)
+	listEventsFunc           func(deploymentConfig *deployapi.DeploymentConfig) (*kapi.EventList, error)
+	listDeploymentEventsFunc func(deployment *kapi.ReplicationController) (*kapi.EventList, error)
 }
 
 func (c *genericDeploymentDescriberClient) getDeploymentConfig(namespace, name string) (*deployapi.DeploymentConfig, error) {
@@ -53,6 +59,10 @@ func (c *genericDeploymentDescriberClient) listEvents(deploymentConfig *deployap
 	return c.listEventsFunc(deploymentConfig)
 }
 
+func (c *genericDeploymentDescriberClient) listDeploymentEvents(deployment *kapi.ReplicationController) (*kapi.EventList, error) {
+	return c.listDeploymentEventsFunc(deployment)
+}
+
 // NewDeploymentConfigDescriberForConfig returns a new DeploymentConfigDescriber
 // for a DeploymentConfig
 func NewDeploymentConfigDescriberForConfig(config *deployapi.DeploymentConfig) *DeploymentConfigDescriber {
@@ -67,6 +77,12 @@ func NewDeploymentConfigDescriberForConfig(config *deployapi.DeploymentConfig) *
 			listPodsFunc: func(namespace string, selector labels.Selector) (*kapi.PodList, error) {
 				return nil, kerrors.NewNotFound("PodList", fmt.Sprintf("%v", selector))
 			},
+			listEventsFunc: func(deploymentConfig *deployapi.DeploymentConfig) (*kapi.EventList, error) {
+				return nil, nil
+			},
+			listDeploymentEventsFunc: func(deployment *kapi.ReplicationController) (*kapi.EventList, error) {
+				return nil, nil
+			},
 		},
 	}
 }
@@ -87,6 +103,9 @@ func NewDeploymentConfigDescriber(client client.Interface, kclient kclient.Inter
 			listEventsFunc: func(deploymentConfig *deployapi.DeploymentConfig) (*kapi.EventList, error) {
 				return kclient.Events(deploymentConfig.Namespace).Search(deploymentConfig)
 			},
+			listDeploymentEventsFunc: func(deployment *kapi.ReplicationController) (*kapi.EventList, error) {
+				return kclient.Events(deployment.Namespace).Search(deployment)
+			},
 		},
 	}
 }
@@ -180,15 +199,8 @@ func printTriggers(triggers []deployapi.DeploymentTriggerPolicy, w *tabwriter.Wr
 	labels := []string{}
 
 	for _, t := range triggers {
-		switch t.Type {
-		case deployapi.DeploymentTriggerOnConfigChange:
-			labels = append(labels, "Config")
-		case deployapi.DeploymentTriggerOnImageChange:
-			if len(t.ImageChangeParams.RepositoryName) > 0 {
-				labels = append(labels, fmt.Sprintf("Image(%s@%s, auto=%v)", t.ImageChangeParams.RepositoryName, t.ImageChangeParams.Tag, t.ImageChangeParams.Automatic))
-			} else if len(t.ImageChangeParams.From.Name) > 0 {
-				labels = append(labels, fmt.Sprintf("Image(%s@%s, auto=%v)", t.ImageChangeParams.From.Name, t.ImageChangeParams.Tag, t.ImageChangeParams.Automatic))
-			}
+		if label := formatTrigger(t); len(label) > 0 {
+			labels = append(labels, label)
 		}
 	}
 
@@ -196,6 +208,22 @@ func printTriggers(triggers []deployapi.DeploymentTriggerPolicy, w *tabwriter.Wr
 	formatString(w, "Triggers", desc)
 }
 
+// formatTrigger returns a short description of a trigger, or an empty string
+// if the trigger has no effect.
+func formatTrigger(t deployapi.DeploymentTriggerPolicy) string {
+	switch t.Type {
+	case deployapi.DeploymentTriggerOnConfigChange:
+		return "Config"
+	case deployapi.DeploymentTriggerOnImageChange:
+		if len(t.ImageChangeParams.RepositoryName) > 0 {
+			return fmt.Sprintf("Image(%s@%s, auto=%v)", t.ImageChangeParams.RepositoryName, t.ImageChangeParams.Tag, t.ImageChangeParams.Automatic)
+		} else if len(t.ImageChangeParams.From.Name) > 0 {
+			return fmt.Sprintf("Image(%s@%s, auto=%v)", t.ImageChangeParams.From.Name, t.ImageChangeParams.Tag, t.ImageChangeParams.Automatic)
+		}
+	}
+	return ""
+}
+
 func printReplicationControllerSpec(spec kapi.ReplicationControllerSpec, w io.Writer) error {
 	fmt.Fprint(w, "Template:\n")
 
@@ -227,9 +255,40 @@ func printDeploymentRc(deployment *kapi.ReplicationController, client deployment
 	fmt.Fprintf(w, "\tReplicas:\t%d current / %d desired\n", deployment.Status.Replicas, deployment.Spec.Replicas)
 	fmt.Fprintf(w, "\tPods Status:\t%d Running / %d Waiting / %d Succeeded / %d Failed\n", running, waiting, succeeded, failed)
 
+	events, err := client.listDeploymentEvents(deployment)
+	if err != nil {
+		fmt.Fprintf(w, "\tEvents:\terror: %v\n", err)
+		return nil
+	}
+	printDeploymentEvents(events, w)
+
 	return nil
 }
 
+// maxDeploymentEvents is the number of recent events shown for a deployment.
+const maxDeploymentEvents = 10
+
+// printDeploymentEvents prints the most recent events recorded for a deployment,
+// oldest first.
+func printDeploymentEvents(events *kapi.EventList, w io.Writer) {
+	if events == nil || len(events.Items) == 0 {
+		fmt.Fprint(w, "\tEvents:\t<none>\n")
+		return
+	}
+
+	items := make([]kapi.Event, len(events.Items))
+	copy(items, events.Items)
+	sort.Sort(kctl.SortableEvents(items))
+	if len(items) > maxDeploymentEvents {
+		items = items[len(items)-maxDeploymentEvents:]
+	}
+
+	fmt.Fprint(w, "\tEvents:\n\t  LastSeen\tCount\tReason\tMessage\n")
+	for _, e := range items {
+		fmt.Fprintf(w, "\t  %s\t%d\t%s\t%s\n", e.LastTimestamp.Time.Format(time.RFC1123Z), e.Count, e.Reason, e.Message)
+	}
+}
+
 func getPodStatusForDeployment(deployment *kapi.ReplicationController, client deploymentDescriberClient) (running, waiting, succeeded, failed int, err error) {
 	rcPods, err := client.listPods(deployment.Namespace, labels.SelectorFromSet(deployment.Spec.Selector))
 	if err != nil {
@@ -278,3 +337,150 @@ func (d *DeploymentDescriber) Describe(namespace, name string) (string, error) {
 		return nil
 	})
 }
+
+// DescribeDeploymentConfigChanges returns a human readable description of the
+// differences in the triggers, strategy and template of two DeploymentConfigs.
+// Removed lines are prefixed with "-" and added lines with "+".
+func DescribeDeploymentConfigChanges(from, to *deployapi.DeploymentConfig) string {
+	sections := []struct {
+		name  string
+		lines func(*deployapi.DeploymentConfig) []string
+	}{
+		{"Triggers", triggerLines},
+		{"Strategy", strategyLines},
+		{"Template", templateLines},
+	}
+
+	out := &bytes.Buffer{}
+	for _, section := range sections {
+		fromLines, toLines := section.lines(from), section.lines(to)
+		if reflect.DeepEqual(fromLines, toLines) {
+			fmt.Fprintf(out, "%s: <unchanged>\n", section.name)
+			continue
+		}
+		fmt.Fprintf(out, "%s:\n", section.name)
+		for _, line := range diffLines(fromLines, toLines) {
+			fmt.Fprintf(out, "  %s\n", line)
+		}
+	}
+	return out.String()
+}
+
+func triggerLines(config *deployapi.DeploymentConfig) []string {
+	lines := []string{}
+	for _, t := range config.Triggers {
+		if label := formatTrigger(t); len(label) > 0 {
+			lines = append(lines, label)
+		}
+	}
+	return lines
+}
+
+func strategyLines(config *deployapi.DeploymentConfig) []string {
+	strategy := config.Template.Strategy
+	lines := []string{fmt.Sprintf("Type: %s", strategy.Type)}
+	switch strategy.Type {
+	case deployapi.DeploymentStrategyTypeRecreate:
+		if strategy.RecreateParams != nil {
+			lines = append(lines, hookLines("Pre-deployment", strategy.RecreateParams.Pre)...)
+			lines = append(lines, hookLines("Post-deployment", strategy.RecreateParams.Post)...)
+		}
+	case deployapi.DeploymentStrategyTypeCustom:
+		if strategy.CustomParams != nil {
+			lines = append(lines,
+				fmt.Sprintf("Image: %s", toString(strategy.CustomParams.Image)),
+				fmt.Sprintf("Environment: %s", toString(formatLabels(convertEnv(strategy.CustomParams.Environment)))),
+				fmt.Sprintf("Command: %s", toString(strings.Join(strategy.CustomParams.Command, " "))))
+		}
+	}
+	return lines
+}
+
+func hookLines(prefix string, hook *deployapi.LifecycleHook) []string {
+	if hook == nil || hook.ExecNewPod == nil {
+		return nil
+	}
+	return []string{
+		fmt.Sprintf("%s hook (failure policy: %s)", prefix, hook.FailurePolicy),
+		fmt.Sprintf("  Container: %s", toString(hook.ExecNewPod.ContainerName)),
+		fmt.Sprintf("  Command: %s", toString(strings.Join(hook.ExecNewPod.Command, " "))),
+		fmt.Sprintf("  Env: %s", toString(formatLabels(convertEnv(hook.ExecNewPod.Env)))),
+	}
+}
+
+func templateLines(config *deployapi.DeploymentConfig) []string {
+	spec := config.Template.ControllerTemplate
+	lines := []string{
+		fmt.Sprintf("Replicas: %d", spec.Replicas),
+		fmt.Sprintf("Selector: %s", toString(formatLabels(spec.Selector))),
+	}
+	if spec.Template == nil {
+		return lines
+	}
+	lines = append(lines, fmt.Sprintf("Labels: %s", toString(formatLabels(spec.Template.Labels))))
+	for _, container := range spec.Template.Spec.Containers {
+		ports := []string{}
+		for _, port := range container.Ports {
+			ports = append(ports, fmt.Sprintf("%d/%s", port.ContainerPort, port.Protocol))
+		}
+		lines = append(lines,
+			fmt.Sprintf("Container %s:", container.Name),
+			fmt.Sprintf("  Image: %s", toString(container.Image)),
+			fmt.Sprintf("  Command: %s", toString(strings.Join(container.Command, " "))),
+			fmt.Sprintf("  Args: %s", toString(strings.Join(container.Args, " "))),
+			fmt.Sprintf("  Env: %s", toString(formatLabels(convertEnv(container.Env)))),
+			fmt.Sprintf("  Ports: %s", toString(strings.Join(ports, ", "))))
+	}
+	volumes := []string{}
+	for _, volume := range spec.Template.Spec.Volumes {
+		volumes = append(volumes, volume.Name)
+	}
+	lines = append(lines, fmt.Sprintf("Volumes: %s", toString(strings.Join(volumes, ", "))))
+	return lines
+}
+
+// diffLines computes a line based diff of from and to using their longest
+// common subsequence. Unchanged lines are indented, removed lines are prefixed
+// with "-" and added lines with "+".
+func diffLines(from, to []string) []string {
+	// lcs[i][j] is the length of the longest common subsequence of from[i:] and to[j:].
+	lcs := make([][]int, len(from)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(to)+1)
+	}
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			if from[i] == to[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	result := []string{}
+	i, j := 0, 0
+	for i < len(from) && j < len(to) {
+		switch {
+		case from[i] == to[j]:
+			result = append(result, "  "+from[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			result = append(result, "- "+from[i])
+			i++
+		default:
+			result = append(result, "+ "+to[j])
+			j++
+		}
+	}
+	for ; i < len(from); i++ {
+		result = append(result, "- "+from[i])
+	}
+	for ; j < len(to); j++ {
+		result = append(result, "+ "+to[j])
+	}
+	return result
+}
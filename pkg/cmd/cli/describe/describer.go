@@ -25,6 +25,7 @@ import (
 	buildutil "github.com/openshift/origin/pkg/build/util"
 	"github.com/openshift/origin/pkg/client"
 	imageapi "github.com/openshift/origin/pkg/image/api"
+	routeapi "github.com/openshift/origin/pkg/route/api"
 	templateapi "github.com/openshift/origin/pkg/template/api"
 )
 
@@ -419,11 +420,42 @@ func (d *RouteDescriber) Describe(namespace, name string) (string, error) {
 		formatMeta(out, route.ObjectMeta)
 		formatString(out, "Host", route.Host)
 		formatString(out, "Path", route.Path)
+		if len(route.WildcardPolicy) > 0 {
+			formatString(out, "Wildcard Policy", route.WildcardPolicy)
+		}
 		formatString(out, "Service", route.ServiceName)
+		describeRouteStatus(out, route)
 		return nil
 	})
 }
 
+// describeRouteStatus writes whether each router which reported on the route admitted it.
+func describeRouteStatus(out *tabwriter.Writer, route *routeapi.Route) {
+	if route.Status == nil || len(route.Status.Ingress) == 0 {
+		formatString(out, "Status", "not admitted by any router")
+		return
+	}
+	fmt.Fprintf(out, "Status:\n")
+	for _, ingress := range route.Status.Ingress {
+		for _, condition := range ingress.Conditions {
+			if condition.Type != routeapi.RouteAdmitted {
+				continue
+			}
+			state := "rejected"
+			if condition.Status == kapi.ConditionTrue {
+				state = "admitted"
+			}
+			if !condition.LastTransitionTime.IsZero() {
+				state = fmt.Sprintf("%s %s ago", state, formatRelativeTime(condition.LastTransitionTime.Time))
+			}
+			fmt.Fprintf(out, "  router %s:\t%s for host %s\n", ingress.RouterName, state, ingress.Host)
+			if len(condition.Reason) > 0 {
+				fmt.Fprintf(out, "  \t%s: %s\n", condition.Reason, condition.Message)
+			}
+		}
+	}
+}
+
 // ProjectDescriber generates information about a Project
 type ProjectDescriber struct {
 	client.Interface
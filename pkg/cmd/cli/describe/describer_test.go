@@ -1,8 +1,10 @@
 package describe
 
 import (
+	"reflect"
 	"strings"
 	"testing"
+	"text/tabwriter"
 	"time"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
@@ -16,6 +18,7 @@ import (
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
 	deployapitest "github.com/openshift/origin/pkg/deploy/api/test"
 	deployutil "github.com/openshift/origin/pkg/deploy/util"
+	routeapi "github.com/openshift/origin/pkg/route/api"
 )
 
 type describeClient struct {
@@ -77,6 +80,11 @@ func TestDeploymentConfigDescriber(t *testing.T) {
 	deployment, _ := deployutil.MakeDeployment(config, kapi.Codec)
 	podList := &kapi.PodList{}
 	eventList := &kapi.EventList{}
+	deploymentEventList := &kapi.EventList{
+		Items: []kapi.Event{
+			{Reason: "deployerPodCreated", Message: "Created deployer pod"},
+		},
+	}
 
 	d := &DeploymentConfigDescriber{
 		client: &genericDeploymentDescriberClient{
@@ -92,6 +100,9 @@ func TestDeploymentConfigDescriber(t *testing.T) {
 			listEventsFunc: func(deploymentConfig *deployapi.DeploymentConfig) (*kapi.EventList, error) {
 				return eventList, nil
 			},
+			listDeploymentEventsFunc: func(deployment *kapi.ReplicationController) (*kapi.EventList, error) {
+				return deploymentEventList, nil
+			},
 		},
 	}
 
@@ -100,6 +111,9 @@ func TestDeploymentConfigDescriber(t *testing.T) {
 			t.Fatalf("unexpected error: %v", err)
 		} else {
 			t.Logf("describer output:\n%s\n", output)
+			if !strings.Contains(output, "deployerPodCreated") {
+				t.Errorf("expected deployment events in output")
+			}
 		}
 	}
 
@@ -148,6 +162,58 @@ func TestDeploymentConfigDescriber(t *testing.T) {
 	describe()
 }
 
+func TestDescribeDeploymentConfigChanges(t *testing.T) {
+	from := deployapitest.OkDeploymentConfig(2)
+	to := deployapitest.OkDeploymentConfig(3)
+	to.Template.Strategy = deployapitest.OkCustomStrategy()
+	to.Template.ControllerTemplate.Template.Spec.Containers[0].Image = "registry:8080/repo1:ref3"
+
+	output := DescribeDeploymentConfigChanges(from, to)
+	t.Logf("changes output:\n%s\n", output)
+
+	for _, expected := range []string{
+		"Triggers: <unchanged>",
+		"- Type: Recreate",
+		"+ Type: Custom",
+		"+ Image: openshift/origin-deployer",
+		"-   Image: registry:8080/repo1:ref1",
+		"+   Image: registry:8080/repo1:ref3",
+		"    Image: registry:8080/repo1:ref2",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected output to contain %q", expected)
+		}
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		from, to, expected []string
+	}{
+		{
+			from:     []string{"a", "b", "c"},
+			to:       []string{"a", "b", "c"},
+			expected: []string{"  a", "  b", "  c"},
+		},
+		{
+			from:     []string{"a", "b", "c"},
+			to:       []string{"a", "x", "c", "d"},
+			expected: []string{"  a", "- b", "+ x", "  c", "+ d"},
+		},
+		{
+			from:     []string{"a", "b"},
+			to:       []string{},
+			expected: []string{"- a", "- b"},
+		},
+	}
+
+	for i, test := range tests {
+		if actual := diffLines(test.from, test.to); !reflect.DeepEqual(test.expected, actual) {
+			t.Errorf("%d: expected %v, got %v", i, test.expected, actual)
+		}
+	}
+}
+
 func TestDescribeBuildDuration(t *testing.T) {
 	type testBuild struct {
 		build  *buildapi.Build
@@ -261,3 +327,42 @@ func mkPod(status kapi.PodPhase, exitCode int) *kapi.Pod {
 		},
 	}
 }
+
+func TestDescribeRouteStatus(t *testing.T) {
+	route := &routeapi.Route{Host: "www.example.com"}
+	out, _ := tabbedString(func(out *tabwriter.Writer) error {
+		describeRouteStatus(out, route)
+		return nil
+	})
+	if !strings.Contains(out, "not admitted by any router") {
+		t.Errorf("unexpected output: %s", out)
+	}
+
+	route.Status = &routeapi.RouteStatus{Ingress: []routeapi.RouteIngress{
+		{
+			Host:       "www.example.com",
+			RouterName: "public",
+			Conditions: []routeapi.RouteIngressCondition{{Type: routeapi.RouteAdmitted, Status: kapi.ConditionTrue}},
+		},
+		{
+			Host:       "www.example.com",
+			RouterName: "internal",
+			Conditions: []routeapi.RouteIngressCondition{{Type: routeapi.RouteAdmitted, Status: kapi.ConditionFalse, Reason: "HostAlreadyClaimed", Message: "taken"}},
+		},
+	}}
+	out, _ = tabbedString(func(out *tabwriter.Writer) error {
+		describeRouteStatus(out, route)
+		return nil
+	})
+	for _, expected := range []string{
+		"router public:",
+		"admitted for host www.example.com",
+		"router internal:",
+		"rejected for host www.example.com",
+		"HostAlreadyClaimed: taken",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected output to contain %q: %s", expected, out)
+		}
+	}
+}
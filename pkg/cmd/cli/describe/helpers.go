@@ -161,7 +161,21 @@ func formatImageStreamTags(out *tabwriter.Writer, stream *imageapi.ImageStream)
 		} else {
 			specTag = "<pushed>"
 		}
-		for _, event := range stream.Status.Tags[tag].Items {
+		// the tag and spec are only displayed on the first line of the tag
+		history := stream.Status.Tags[tag]
+		for _, condition := range history.Conditions {
+			if condition.Type != imageapi.ImportSuccess || condition.Status != api.ConditionFalse {
+				continue
+			}
+			d := timeNowFn().Sub(condition.LastTransitionTime.Time)
+			fmt.Fprintf(out, "  %s \t%s \t%s ago \t! import failed: %s \t\n",
+				tag,
+				specTag,
+				units.HumanDuration(d),
+				condition.Message)
+			tag, specTag = "", ""
+		}
+		for _, event := range history.Items {
 			d := timeNowFn().Sub(event.Created.Time)
 			image := event.Image
 			ref, err := imageapi.ParseDockerImageReference(event.DockerImageReference)
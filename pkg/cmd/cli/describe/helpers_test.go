@@ -2,6 +2,7 @@ package describe
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 	"text/tabwriter"
 	"time"
@@ -79,3 +80,46 @@ func TestFormatImageStreamTags(t *testing.T) {
 	actual := string(buf.String())
 	t.Logf("\n%s", actual)
 }
+
+func TestFormatImageStreamTagsImportFailed(t *testing.T) {
+	repo := imageapi.ImageStream{
+		Spec: imageapi.ImageStreamSpec{
+			Tags: map[string]imageapi.TagReference{
+				imageapi.DefaultImageTag: {DockerImageReference: "mysql:latest"},
+			},
+		},
+		Status: imageapi.ImageStreamStatus{
+			Tags: map[string]imageapi.TagEventList{
+				imageapi.DefaultImageTag: {
+					Conditions: []imageapi.TagEventCondition{
+						{
+							Type:               imageapi.ImportSuccess,
+							Status:             kapi.ConditionFalse,
+							LastTransitionTime: util.Date(2015, 3, 24, 9, 38, 0, 0, time.UTC),
+							Message:            "unable to reach the registry",
+						},
+					},
+					Items: []imageapi.TagEvent{
+						{
+							Created:              util.Date(2015, 3, 23, 7, 15, 0, 0, time.UTC),
+							DockerImageReference: "mysql@sha256:e52c6534db85036dabac5e71ff14e720db94def2d90f986f3548425ea27b3719",
+							Image:                "sha256:e52c6534db85036dabac5e71ff14e720db94def2d90f986f3548425ea27b3719",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	out := new(tabwriter.Writer)
+	out.Init(buf, 0, 8, 1, '\t', 0)
+	formatImageStreamTags(out, &repo)
+	out.Flush()
+
+	for _, s := range []string{"! import failed: unable to reach the registry", "mysql@sha256:e52c6534db85036dabac5e71ff14e720db94def2d90f986f3548425ea27b3719"} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("expected %q in:\n%s", s, buf.String())
+		}
+	}
+}
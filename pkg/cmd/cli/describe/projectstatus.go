@@ -1,23 +1,31 @@
 package describe
 
 import (
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"io"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/docker/docker/pkg/units"
 
 	"github.com/openshift/origin/pkg/api/graph"
 	buildapi "github.com/openshift/origin/pkg/build/api"
 	"github.com/openshift/origin/pkg/client"
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	routeapi "github.com/openshift/origin/pkg/route/api"
 )
 
+// certificateExpiryWarning is how long before the certificate of a route expires a warning is shown
+const certificateExpiryWarning = 30 * 24 * time.Hour
+
 // ProjectStatusDescriber generates extended information about a Project
 type ProjectStatusDescriber struct {
 	K kclient.Interface
@@ -58,6 +66,11 @@ func (d *ProjectStatusDescriber) Describe(namespace, name string) (string, error
 		rcs = &kapi.ReplicationControllerList{}
 	}
 
+	routes, err := d.C.Routes(namespace).List(labels.Everything(), fields.Everything())
+	if err != nil {
+		routes = &routeapi.RouteList{}
+	}
+
 	g := graph.New()
 	for i := range bcs.Items {
 		build := graph.BuildConfig(g, &bcs.Items[i])
@@ -105,6 +118,11 @@ func (d *ProjectStatusDescriber) Describe(namespace, name string) (string, error
 			}
 		}
 
+		if warnings := describeRouteCertificateWarnings(routes.Items); len(warnings) > 0 {
+			fmt.Fprintln(out, "\nWarnings:")
+			printLines(out, indent, 1, warnings...)
+		}
+
 		if len(groups) == 0 {
 			fmt.Fprintln(out, "\nYou have no services, deployment configs, or build configs. 'osc new-app' can be used to create applications from scratch from existing Docker images and templates.")
 		} else {
@@ -116,6 +134,33 @@ func (d *ProjectStatusDescriber) Describe(namespace, name string) (string, error
 	})
 }
 
+// describeRouteCertificateWarnings returns a warning for each route whose certificate expired or
+// expires within certificateExpiryWarning.
+func describeRouteCertificateWarnings(routes []routeapi.Route) []string {
+	warnings := []string{}
+	now := timeNowFn()
+	for _, route := range routes {
+		if route.TLS == nil || len(route.TLS.Certificate) == 0 {
+			continue
+		}
+		block, _ := pem.Decode([]byte(route.TLS.Certificate))
+		if block == nil {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		switch remaining := cert.NotAfter.Sub(now); {
+		case remaining <= 0:
+			warnings = append(warnings, fmt.Sprintf("* route %s has a certificate which expired %s ago", route.Name, units.HumanDuration(-remaining)))
+		case remaining < certificateExpiryWarning:
+			warnings = append(warnings, fmt.Sprintf("* route %s has a certificate which expires in %s", route.Name, units.HumanDuration(remaining)))
+		}
+	}
+	return warnings
+}
+
 func printLines(out io.Writer, indent string, depth int, lines ...string) {
 	for i, s := range lines {
 		fmt.Fprintf(out, strings.Repeat(indent, depth))
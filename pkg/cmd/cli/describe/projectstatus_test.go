@@ -1,6 +1,13 @@
 package describe
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
 	"strings"
 	"testing"
 	"time"
@@ -12,6 +19,7 @@ import (
 
 	"github.com/openshift/origin/pkg/client/testclient"
 	projectapi "github.com/openshift/origin/pkg/project/api"
+	routeapi "github.com/openshift/origin/pkg/route/api"
 )
 
 func mustParseTime(t string) time.Time {
@@ -22,13 +30,34 @@ func mustParseTime(t string) time.Time {
 	return out
 }
 
+// mustCreateCertificate returns a PEM encoded self-signed certificate for host which expires at notAfter
+func mustCreateCertificate(host string, notAfter time.Time) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: host},
+		DNSNames:     []string{host},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		panic(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
 func TestProjectStatus(t *testing.T) {
 	testCases := map[string]struct {
-		Path     string
-		Extra    []runtime.Object
-		ErrFn    func(error) bool
-		Contains []string
-		Time     time.Time
+		Path        string
+		Extra       []runtime.Object
+		ErrFn       func(error) bool
+		Contains    []string
+		NotContains []string
+		Time        time.Time
 	}{
 		"missing project": {
 			ErrFn: func(err error) bool { return errors.IsNotFound(err) },
@@ -138,6 +167,47 @@ func TestProjectStatus(t *testing.T) {
 			},
 			Time: mustParseTime("2015-04-07T04:12:25Z"),
 		},
+		"route certificates expiring soon": {
+			Extra: []runtime.Object{
+				&projectapi.Project{
+					ObjectMeta: kapi.ObjectMeta{Name: "example", Namespace: ""},
+				},
+				&routeapi.Route{
+					ObjectMeta: kapi.ObjectMeta{Name: "expiring", Namespace: "example"},
+					Host:       "expiring.example.com",
+					TLS: &routeapi.TLSConfig{
+						Termination: routeapi.TLSTerminationEdge,
+						Certificate: mustCreateCertificate("expiring.example.com", mustParseTime("2015-04-19T21:20:03Z")),
+					},
+				},
+				&routeapi.Route{
+					ObjectMeta: kapi.ObjectMeta{Name: "expired", Namespace: "example"},
+					Host:       "expired.example.com",
+					TLS: &routeapi.TLSConfig{
+						Termination: routeapi.TLSTerminationEdge,
+						Certificate: mustCreateCertificate("expired.example.com", mustParseTime("2015-04-03T21:20:03Z")),
+					},
+				},
+				&routeapi.Route{
+					ObjectMeta: kapi.ObjectMeta{Name: "valid", Namespace: "example"},
+					Host:       "valid.example.com",
+					TLS: &routeapi.TLSConfig{
+						Termination: routeapi.TLSTerminationEdge,
+						Certificate: mustCreateCertificate("valid.example.com", mustParseTime("2016-04-06T21:20:03Z")),
+					},
+				},
+			},
+			ErrFn: func(err error) bool { return err == nil },
+			Contains: []string{
+				"Warnings:",
+				"route expiring has a certificate which expires in 13 days",
+				"route expired has a certificate which expired 3 days ago",
+			},
+			NotContains: []string{
+				"route valid",
+			},
+			Time: mustParseTime("2015-04-06T21:20:03Z"),
+		},
 	}
 	oldTimeFn := timeNowFn
 	defer func() { timeNowFn = oldTimeFn }()
@@ -171,6 +241,11 @@ func TestProjectStatus(t *testing.T) {
 				t.Errorf("%s: did not have %q:\n%s\n---", k, s, out)
 			}
 		}
+		for _, s := range test.NotContains {
+			if strings.Contains(out, s) {
+				t.Errorf("%s: should not have %q:\n%s\n---", k, s, out)
+			}
+		}
 		//t.Logf("\n%s", out)
 	}
 }
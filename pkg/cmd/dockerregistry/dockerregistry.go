@@ -9,12 +9,14 @@ import (
 	"github.com/docker/distribution/configuration"
 	ctxu "github.com/docker/distribution/context"
 	"github.com/docker/distribution/registry/handlers"
+	"github.com/docker/distribution/registry/storage/driver/factory"
 	_ "github.com/docker/distribution/registry/storage/driver/filesystem"
 	_ "github.com/docker/distribution/registry/storage/driver/s3"
 	"github.com/docker/distribution/version"
 	gorillahandlers "github.com/gorilla/handlers"
-	_ "github.com/openshift/origin/pkg/dockerregistry/auth"
+	"github.com/openshift/origin/pkg/dockerregistry/auth"
 	_ "github.com/openshift/origin/pkg/dockerregistry/middleware/repository"
+	"github.com/openshift/origin/pkg/dockerregistry/prune"
 	"golang.org/x/net/context"
 )
 
@@ -37,7 +39,17 @@ func Execute(configFile io.Reader) {
 	ctx = ctxu.WithLogger(ctx, ctxu.GetLogger(ctx, "version"))
 
 	app := handlers.NewApp(ctx, *config)
-	handler := gorillahandlers.CombinedLoggingHandler(os.Stdout, app)
+
+	// layers are pruned directly from storage, outside of the registry API
+	driver, err := factory.Create(config.Storage.Type(), config.Storage.Parameters())
+	if err != nil {
+		log.Fatalf("Error creating storage driver: %s", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle(prune.LayersPath, prune.NewHandler(driver, auth.VerifyImagePruneAccess))
+	mux.Handle("/", app)
+
+	handler := gorillahandlers.CombinedLoggingHandler(os.Stdout, mux)
 
 	if config.HTTP.TLS.Certificate == "" {
 		ctxu.GetLogger(app).Infof("listening on %v", config.HTTP.Addr)
@@ -0,0 +1,262 @@
+package prune
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	cmdutil "github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl/cmd/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/origin/pkg/client"
+	"github.com/openshift/origin/pkg/cmd/util/clientcmd"
+	registryprune "github.com/openshift/origin/pkg/dockerregistry/prune"
+	"github.com/openshift/origin/pkg/image/prune"
+)
+
+const pruneImagesLongDesc = `Remove image stream tag history and images which are no longer used
+
+Each image stream tag keeps the history of the images it pointed to. The history
+of every tag is trimmed to the most recent revisions, and entries older than
+--max-tag-age are removed. Images are then removed when they are no longer
+recorded in the history of any image stream and are not used by any pod,
+replication controller, deployment config or build config. The layers of the
+removed images which are not shared with any remaining image are deleted from
+the integrated registry, which requires logging in with a token.
+
+By default, this command only lists what would be removed. Pass --confirm to
+remove it.
+
+Examples:
+
+	# See what would be removed, keeping the three most recent revisions of each tag
+	$ %[1]s %[2]s --keep-tag-revisions=3
+
+	# Remove the images and registry layers
+	$ %[1]s %[2]s --confirm
+`
+
+// PruneImagesOptions holds all the options for the `prune images` command
+type PruneImagesOptions struct {
+	out        io.Writer
+	osClient   client.Interface
+	kubeClient kclient.Interface
+	token      string
+
+	// deleteLayers deletes layers from the registry at registryURL.
+	deleteLayers func(registryURL, token string, layers map[string][]string) error
+
+	confirm          bool
+	keepTagRevisions int
+	maxTagAge        time.Duration
+	keepYoungerThan  time.Duration
+	registryURL      string
+}
+
+// NewCmdPruneImages implements the prune images command
+func NewCmdPruneImages(f *clientcmd.Factory, parentName, name string, out io.Writer) *cobra.Command {
+	options := &PruneImagesOptions{
+		keepTagRevisions: 3,
+		keepYoungerThan:  60 * time.Minute,
+	}
+
+	cmd := &cobra.Command{
+		Use:   name,
+		Short: "Remove unreferenced images and registry layers",
+		Long:  fmt.Sprintf(pruneImagesLongDesc, parentName, name),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := options.Complete(f, args, out); err != nil {
+				cmdutil.CheckErr(cmdutil.UsageError(cmd, "%v", err))
+			}
+
+			if err := options.Validate(); err != nil {
+				cmdutil.CheckErr(cmdutil.UsageError(cmd, "%v", err))
+			}
+
+			if err := options.RunPruneImages(); err != nil {
+				cmdutil.CheckErr(err)
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&options.confirm, "confirm", false, "Remove the images and layers. By default, only what would be removed is listed.")
+	cmd.Flags().IntVar(&options.keepTagRevisions, "keep-tag-revisions", options.keepTagRevisions, "The number of revisions to keep in the history of each image stream tag.")
+	cmd.Flags().DurationVar(&options.maxTagAge, "max-tag-age", options.maxTagAge, "Remove image stream tag history entries older than this. Zero keeps entries of any age.")
+	cmd.Flags().DurationVar(&options.keepYoungerThan, "keep-younger-than", options.keepYoungerThan, "Do not remove images created more recently than this.")
+	cmd.Flags().StringVar(&options.registryURL, "registry-url", "", "The address of the integrated registry. Defaults to the registry recorded in the image streams.")
+
+	return cmd
+}
+
+// Complete sets up the clients.
+func (o *PruneImagesOptions) Complete(f *clientcmd.Factory, args []string, out io.Writer) error {
+	if len(args) > 0 {
+		return errors.New("no arguments are allowed to this command")
+	}
+	o.out = out
+
+	var err error
+	if o.osClient, o.kubeClient, err = f.Clients(); err != nil {
+		return err
+	}
+	config, err := f.OpenShiftClientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+	o.token = config.BearerToken
+	o.deleteLayers = func(registryURL, token string, layers map[string][]string) error {
+		return registryprune.DeleteLayers(http.DefaultClient, registryURL, token, layers)
+	}
+	return nil
+}
+
+// Validate ensures the options are sensible.
+func (o PruneImagesOptions) Validate() error {
+	if o.keepTagRevisions < 1 {
+		return errors.New("--keep-tag-revisions must be at least 1")
+	}
+	if o.maxTagAge < 0 || o.keepYoungerThan < 0 {
+		return errors.New("--max-tag-age and --keep-younger-than may not be negative")
+	}
+	return nil
+}
+
+// RunPruneImages lists, and if confirmed removes, the tag history entries, images
+// and registry layers which are no longer needed.
+func (o PruneImagesOptions) RunPruneImages() error {
+	resources, err := o.listResources()
+	if err != nil {
+		return err
+	}
+
+	registries := prune.StreamRegistries(resources.Streams)
+	registryURL := o.registryURL
+	if len(registryURL) > 0 {
+		host := registryURL
+		if u, err := url.Parse(registryURL); err == nil && len(u.Host) > 0 {
+			host = u.Host
+		}
+		registries = util.NewStringSet(host)
+	}
+
+	plan := prune.NewPlan(resources, prune.Options{
+		KeepTagRevisions: o.keepTagRevisions,
+		MaxTagAge:        o.maxTagAge,
+		KeepYoungerThan:  o.keepYoungerThan,
+		Registries:       registries,
+	}, time.Now())
+
+	if len(plan.Layers) > 0 && len(registryURL) == 0 {
+		if len(registries) != 1 {
+			return fmt.Errorf("the image streams are stored in more than one registry (%s), use --registry-url to choose one", strings.Join(registries.List(), ", "))
+		}
+		registryURL = registries.List()[0]
+	}
+
+	o.describePlan(plan)
+
+	if !o.confirm {
+		fmt.Fprintln(o.out, "\nDry run enabled - no modifications will be made. Add --confirm to remove images")
+		return nil
+	}
+
+	// the registry only authenticates requests with a token
+	if len(plan.Layers) > 0 && len(o.token) == 0 {
+		return errors.New("deleting layers from the registry requires a token, log in as a user who may prune images and try again")
+	}
+
+	for _, stream := range plan.Streams {
+		if _, err := o.osClient.ImageStreams(stream.Namespace).UpdateStatus(stream); err != nil {
+			return fmt.Errorf("unable to trim the tag history of %s/%s: %v", stream.Namespace, stream.Name, err)
+		}
+	}
+	// layers are deleted first, so that a failure can be retried while the
+	// images which reference them still exist
+	if len(plan.Layers) > 0 {
+		if err := o.deleteLayers(registryURL, o.token, plan.Layers); err != nil {
+			return fmt.Errorf("unable to delete layers from %s: %v", registryURL, err)
+		}
+	}
+	for _, image := range plan.Images {
+		if err := o.osClient.Images().Delete(image.Name); err != nil {
+			return fmt.Errorf("unable to delete image %s: %v", image.Name, err)
+		}
+	}
+
+	fmt.Fprintf(o.out, "\nRemoved %d images and %d layers\n", len(plan.Images), len(plan.Layers))
+	return nil
+}
+
+// listResources retrieves the objects which may reference images from all namespaces.
+func (o PruneImagesOptions) listResources() (*prune.Resources, error) {
+	var err error
+	resources := &prune.Resources{}
+	if resources.Images, err = o.osClient.Images().List(labels.Everything(), fields.Everything()); err != nil {
+		return nil, err
+	}
+	if resources.Streams, err = o.osClient.ImageStreams(kapi.NamespaceAll).List(labels.Everything(), fields.Everything()); err != nil {
+		return nil, err
+	}
+	if resources.Pods, err = o.kubeClient.Pods(kapi.NamespaceAll).List(labels.Everything()); err != nil {
+		return nil, err
+	}
+	if resources.Controllers, err = o.kubeClient.ReplicationControllers(kapi.NamespaceAll).List(labels.Everything()); err != nil {
+		return nil, err
+	}
+	if resources.DeploymentConfigs, err = o.osClient.DeploymentConfigs(kapi.NamespaceAll).List(labels.Everything(), fields.Everything()); err != nil {
+		return nil, err
+	}
+	if resources.BuildConfigs, err = o.osClient.BuildConfigs(kapi.NamespaceAll).List(labels.Everything(), fields.Everything()); err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+// describePlan prints the changes plan will make.
+func (o PruneImagesOptions) describePlan(plan *prune.Plan) {
+	w := tabwriter.NewWriter(o.out, 10, 4, 3, ' ', 0)
+	defer w.Flush()
+
+	if len(plan.Streams) > 0 {
+		fmt.Fprintln(w, "Tag history to trim:\nIMAGE STREAM\tTAG\tREVISIONS KEPT")
+		for _, stream := range plan.Streams {
+			tags := []string{}
+			for tag := range stream.Status.Tags {
+				tags = append(tags, tag)
+			}
+			sort.Strings(tags)
+			for _, tag := range tags {
+				fmt.Fprintf(w, "%s/%s\t%s\t%d\n", stream.Namespace, stream.Name, tag, len(stream.Status.Tags[tag].Items))
+			}
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintln(w, "Images to remove:\nIMAGE\tREFERENCE")
+	for _, image := range plan.Images {
+		fmt.Fprintf(w, "%s\t%s\n", image.Name, image.DockerImageReference)
+	}
+
+	if len(plan.Layers) > 0 {
+		layers := []string{}
+		for layer := range plan.Layers {
+			layers = append(layers, layer)
+		}
+		sort.Strings(layers)
+		fmt.Fprintln(w, "\nLayers to remove:\nLAYER\tREPOSITORIES")
+		for _, layer := range layers {
+			fmt.Fprintf(w, "%s\t%s\n", layer, strings.Join(plan.Layers[layer], ", "))
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package prune
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	ktc "github.com/GoogleCloudPlatform/kubernetes/pkg/client/testclient"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
+	buildapi "github.com/openshift/origin/pkg/build/api"
+	"github.com/openshift/origin/pkg/client"
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+)
+
+func fakePruneClient() *client.Fake {
+	old := util.NewTime(time.Now().Add(-24 * time.Hour))
+	ref := func(n int) string {
+		return fmt.Sprintf("registry:5000/ns/stream@sha256:%064d", n)
+	}
+	id := func(n int) string {
+		return fmt.Sprintf("sha256:%064d", n)
+	}
+	return &client.Fake{
+		ReactFn: func(action ktc.FakeAction) (runtime.Object, error) {
+			switch action.Action {
+			case "list-images":
+				return &imageapi.ImageList{Items: []imageapi.Image{
+					{ObjectMeta: kapi.ObjectMeta{Name: id(1), CreationTimestamp: old}, DockerImageReference: ref(1), DockerImageManifest: `{"fsLayers":[{"blobSum":"sha256:1"}]}`},
+					{ObjectMeta: kapi.ObjectMeta{Name: id(2), CreationTimestamp: old}, DockerImageReference: ref(2)},
+				}}, nil
+			case "list-imagestreams":
+				return &imageapi.ImageStreamList{Items: []imageapi.ImageStream{{
+					ObjectMeta: kapi.ObjectMeta{Namespace: "ns", Name: "stream"},
+					Status: imageapi.ImageStreamStatus{
+						DockerImageRepository: "registry:5000/ns/stream",
+						Tags: map[string]imageapi.TagEventList{
+							"latest": {Items: []imageapi.TagEvent{
+								{DockerImageReference: ref(2), Image: id(2)},
+								{DockerImageReference: ref(1), Image: id(1)},
+							}},
+						},
+					},
+				}}}, nil
+			case "list-deploymentconfig":
+				return &deployapi.DeploymentConfigList{}, nil
+			case "list-buildconfig":
+				return &buildapi.BuildConfigList{}, nil
+			case "update-status-imagestream":
+				return action.Value.(*imageapi.ImageStream), nil
+			}
+			return nil, nil
+		},
+	}
+}
+
+func hasAction(fake *client.Fake, action string) bool {
+	for _, a := range fake.Actions {
+		if a.Action == action {
+			return true
+		}
+	}
+	return false
+}
+
+func TestPruneImages_dryRun(t *testing.T) {
+	osClient := fakePruneClient()
+	out := &bytes.Buffer{}
+	o := &PruneImagesOptions{
+		out:        out,
+		osClient:   osClient,
+		kubeClient: &ktc.Fake{},
+		deleteLayers: func(registryURL, token string, layers map[string][]string) error {
+			t.Fatalf("unexpected layer deletion")
+			return nil
+		},
+		keepTagRevisions: 1,
+		keepYoungerThan:  time.Hour,
+	}
+
+	if err := o.RunPruneImages(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, action := range []string{"update-status-imagestream", "delete-image"} {
+		if hasAction(osClient, action) {
+			t.Errorf("unexpected action %s during a dry run", action)
+		}
+	}
+	if !strings.Contains(out.String(), fmt.Sprintf("sha256:%064d", 1)) {
+		t.Errorf("expected the image to prune to be listed, got:\n%s", out.String())
+	}
+}
+
+func TestPruneImages_confirm(t *testing.T) {
+	osClient := fakePruneClient()
+	var deletedLayers map[string][]string
+	var deletedFrom string
+	o := &PruneImagesOptions{
+		out:        &bytes.Buffer{},
+		osClient:   osClient,
+		kubeClient: &ktc.Fake{},
+		token:      "token",
+		deleteLayers: func(registryURL, token string, layers map[string][]string) error {
+			deletedFrom = registryURL
+			deletedLayers = layers
+			return nil
+		},
+		confirm:          true,
+		keepTagRevisions: 1,
+		keepYoungerThan:  time.Hour,
+	}
+
+	if err := o.RunPruneImages(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !hasAction(osClient, "update-status-imagestream") {
+		t.Errorf("expected the tag history to be trimmed")
+	}
+	deleted := []string{}
+	for _, action := range osClient.Actions {
+		if action.Action == "delete-image" {
+			deleted = append(deleted, action.Value.(string))
+		}
+	}
+	if len(deleted) != 1 || deleted[0] != fmt.Sprintf("sha256:%064d", 1) {
+		t.Errorf("unexpected deleted images: %v", deleted)
+	}
+	if e, a := "registry:5000", deletedFrom; e != a {
+		t.Errorf("expected layers to be deleted from %s, got %s", e, a)
+	}
+	if repos, ok := deletedLayers["sha256:1"]; !ok || len(repos) != 1 || repos[0] != "ns/stream" {
+		t.Errorf("unexpected deleted layers: %v", deletedLayers)
+	}
+}
+
+func TestPruneImages_noToken(t *testing.T) {
+	osClient := fakePruneClient()
+	o := &PruneImagesOptions{
+		out:        &bytes.Buffer{},
+		osClient:   osClient,
+		kubeClient: &ktc.Fake{},
+		deleteLayers: func(registryURL, token string, layers map[string][]string) error {
+			t.Fatalf("unexpected layer deletion")
+			return nil
+		},
+		confirm:          true,
+		keepTagRevisions: 1,
+		keepYoungerThan:  time.Hour,
+	}
+
+	if err := o.RunPruneImages(); err == nil || !strings.Contains(err.Error(), "requires a token") {
+		t.Fatalf("expected an error for a missing token, got %v", err)
+	}
+	for _, action := range []string{"update-status-imagestream", "delete-image"} {
+		if hasAction(osClient, action) {
+			t.Errorf("unexpected action %s without a token", action)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package prune
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/origin/pkg/cmd/util/clientcmd"
+)
+
+const pruneLongDesc = `Remove older versions of resources from the server
+
+The commands here allow administrators to manage the older versions of resources on
+the system by removing them.
+`
+
+// NewCmdPrune groups the commands which remove unneeded resources.
+func NewCmdPrune(f *clientcmd.Factory, parentName, name string, out io.Writer) *cobra.Command {
+	cmds := &cobra.Command{
+		Use:   name,
+		Short: "Remove older versions of resources from the server",
+		Long:  fmt.Sprintf(pruneLongDesc),
+		Run: func(c *cobra.Command, args []string) {
+			c.SetOutput(out)
+			c.Help()
+		},
+	}
+
+	cmds.AddCommand(NewCmdPruneImages(f, parentName+" "+name, "images", out))
+	return cmds
+}
@@ -11,6 +11,7 @@ import (
 	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	kclientcmd "github.com/GoogleCloudPlatform/kubernetes/pkg/client/clientcmd"
 	cmdutil "github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl/cmd/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	kutil "github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/golang/glog"
@@ -49,6 +50,10 @@ Examples:
 
   $ %[1]s %[2]s router-west --create --replicas=2
 
+  Create a router serving only the routes labeled router=public in projects labeled network=dmz:
+
+  $ %[1]s %[2]s router-public --create --route-labels=router=public --namespace-labels=network=dmz
+
   Use a different router image and see the router configuration:
 
   $ %[1]s %[2]s region-west -o yaml --images=myrepo/somerouter:mytag
@@ -65,6 +70,12 @@ type config struct {
 	Labels        string
 	Create        bool
 	Credentials   string
+
+	RouteLabels     string
+	NamespaceLabels string
+
+	DisableNamespaceOwnershipCheck bool
+	AllowWildcardRoutes            bool
 }
 
 const defaultLabel = "router=<name>"
@@ -111,6 +122,13 @@ func NewCmdRouter(f *clientcmd.Factory, parentName, name string, out io.Writer)
 				label = valid
 			}
 
+			if _, err := labels.Parse(cfg.RouteLabels); err != nil {
+				glog.Fatalf("The route label selector %q is not valid: %v", cfg.RouteLabels, err)
+			}
+			if _, err := labels.Parse(cfg.NamespaceLabels); err != nil {
+				glog.Fatalf("The namespace label selector %q is not valid: %v", cfg.NamespaceLabels, err)
+			}
+
 			image := cfg.ImageTemplate.ExpandOrDie(cfg.Type)
 
 			namespace, err := f.OpenShiftClientConfig.Namespace()
@@ -170,6 +188,19 @@ func NewCmdRouter(f *clientcmd.Factory, parentName, name string, out io.Writer)
 					"OPENSHIFT_KEY_DATA":  string(config.KeyData),
 					"OPENSHIFT_CERT_DATA": string(config.CertData),
 					"OPENSHIFT_INSECURE":  insecure,
+					"ROUTER_SERVICE_NAME": name,
+				}
+				if len(cfg.RouteLabels) > 0 {
+					env["ROUTE_LABELS"] = cfg.RouteLabels
+				}
+				if len(cfg.NamespaceLabels) > 0 {
+					env["NAMESPACE_LABELS"] = cfg.NamespaceLabels
+				}
+				if cfg.DisableNamespaceOwnershipCheck {
+					env["DISABLE_NAMESPACE_OWNERSHIP_CHECK"] = "true"
+				}
+				if cfg.AllowWildcardRoutes {
+					env["ROUTER_ALLOW_WILDCARD_ROUTES"] = "true"
 				}
 
 				objects := []runtime.Object{
@@ -248,6 +279,10 @@ func NewCmdRouter(f *clientcmd.Factory, parentName, name string, out io.Writer)
 	cmd.Flags().StringVar(&cfg.Labels, "labels", cfg.Labels, "A set of labels to uniquely identify the router and its components.")
 	cmd.Flags().BoolVar(&cfg.Create, "create", cfg.Create, "Create the router if it does not exist.")
 	cmd.Flags().StringVar(&cfg.Credentials, "credentials", "", "Path to a .kubeconfig file that will contain the credentials the router should use to contact the master.")
+	cmd.Flags().StringVar(&cfg.RouteLabels, "route-labels", "", "A label selector to apply to the routes the router serves; all routes are served if empty.")
+	cmd.Flags().StringVar(&cfg.NamespaceLabels, "namespace-labels", "", "A label selector to apply to the namespaces whose routes the router serves; all namespaces are served if empty.")
+	cmd.Flags().BoolVar(&cfg.DisableNamespaceOwnershipCheck, "disable-namespace-ownership-check", false, "Allow routes in different namespaces to claim the same host. By default only the namespace of the oldest route for a host is served.")
+	cmd.Flags().BoolVar(&cfg.AllowWildcardRoutes, "allow-wildcard-routes", false, "Serve routes with a Subdomain wildcard policy, such as a route for www.apps.example.com serving *.apps.example.com.")
 
 	cmdutil.AddPrinterFlags(cmd)
 
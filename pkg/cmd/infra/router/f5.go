@@ -0,0 +1,92 @@
+package router
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/origin/pkg/cmd/util"
+	"github.com/openshift/origin/pkg/cmd/util/clientcmd"
+	"github.com/openshift/origin/pkg/router/controller"
+	"github.com/openshift/origin/pkg/version"
+	f5plugin "github.com/openshift/origin/plugins/router/f5"
+)
+
+const f5CommandDesc = `
+Start an OpenShift router backed by an F5 BIG-IP
+
+This command launches a router connected to your OpenShift master. The router listens for routes and endpoints
+created by users and configures the pools, policies and SSL profiles of an F5 BIG-IP through its iControl REST
+API. The HTTP and HTTPS virtual servers the routes are served on must already exist on the BIG-IP.
+`
+
+type f5RouterConfig struct {
+	routerSelection
+	Config *clientcmd.Config
+	F5     f5plugin.F5PluginConfig
+	// AllowWildcardRoutes lets routes with a Subdomain wildcard policy be served
+	AllowWildcardRoutes bool
+}
+
+// NewCommandF5Router provides CLI handler for the F5 router backend
+func NewCommandF5Router(name string) *cobra.Command {
+	cfg := &f5RouterConfig{
+		Config: clientcmd.NewConfig(),
+	}
+
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("%s%s", name, clientcmd.ConfigSyntax),
+		Short: "Start an OpenShift router backed by an F5 BIG-IP",
+		Long:  f5CommandDesc,
+		Run: func(c *cobra.Command, args []string) {
+			if err := startF5(cfg); err != nil {
+				glog.Fatal(err)
+			}
+		},
+	}
+
+	cmd.AddCommand(version.NewVersionCommand(name))
+
+	flag := cmd.Flags()
+	cfg.Config.Bind(flag)
+	cfg.routerSelection.Bind(flag)
+	flag.StringVar(&cfg.F5.Host, "f5-host", util.Env("ROUTER_EXTERNAL_HOST_HOSTNAME", ""), "The host, and optional port, of the management interface of the F5 BIG-IP")
+	flag.StringVar(&cfg.F5.Username, "f5-username", util.Env("ROUTER_EXTERNAL_HOST_USERNAME", ""), "The username to authenticate to the iControl REST API of the F5 BIG-IP with")
+	flag.StringVar(&cfg.F5.Password, "f5-password", util.Env("ROUTER_EXTERNAL_HOST_PASSWORD", ""), "The password to authenticate to the iControl REST API of the F5 BIG-IP with")
+	flag.StringVar(&cfg.F5.HTTPVserver, "f5-http-vserver", util.Env("ROUTER_EXTERNAL_HOST_HTTP_VSERVER", "ose-vserver"), "The name of the virtual server of the F5 BIG-IP serving HTTP routes")
+	flag.StringVar(&cfg.F5.HTTPSVserver, "f5-https-vserver", util.Env("ROUTER_EXTERNAL_HOST_HTTPS_VSERVER", "https-ose-vserver"), "The name of the virtual server of the F5 BIG-IP serving HTTPS routes")
+	flag.BoolVar(&cfg.AllowWildcardRoutes, "allow-wildcard-routes", util.Env("ROUTER_ALLOW_WILDCARD_ROUTES", "") == "true", "Serve routes with a Subdomain wildcard policy for every host in their subdomain")
+	flag.BoolVar(&cfg.F5.Insecure, "f5-insecure", util.Env("ROUTER_EXTERNAL_HOST_INSECURE", "") == "true", "Do not verify the certificate of the F5 BIG-IP")
+
+	return cmd
+}
+
+// startF5 launches the F5 router.
+func startF5(cfg *f5RouterConfig) error {
+	osClient, kubeClient, err := cfg.Config.Clients()
+	if err != nil {
+		return err
+	}
+
+	// the BIG-IP applies changes as the plugin makes them, so admissions are flushed
+	// each time the plugin has handled an event
+	admitter := controller.NewStatusAdmitter(osClient, cfg.RouterName)
+	f5Plugin, err := f5plugin.NewF5Plugin(cfg.F5, admitter.Flush)
+	if err != nil {
+		return err
+	}
+	uniqueHost := controller.NewUniqueHost(f5Plugin, admitter, cfg.DisableNamespaceOwnershipCheck, cfg.AllowWildcardRoutes)
+	// routes with certificates the router cannot load are skipped instead of failing the router
+	plugin := controller.NewExtendedValidator(uniqueHost, admitter)
+
+	factory, err := cfg.NewFactory(kubeClient, osClient)
+	if err != nil {
+		return err
+	}
+
+	routerController := factory.Create(plugin)
+	routerController.Run()
+
+	select {}
+}
@@ -3,14 +3,23 @@ package router
 import (
 	"errors"
 	"fmt"
+	"net/http"
+	"time"
 
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/healthz"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/golang/glog"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
+	osclient "github.com/openshift/origin/pkg/client"
 	"github.com/openshift/origin/pkg/cmd/util"
 	"github.com/openshift/origin/pkg/cmd/util/clientcmd"
-	"github.com/openshift/origin/pkg/router"
+	"github.com/openshift/origin/pkg/router/controller"
 	controllerfactory "github.com/openshift/origin/pkg/router/controller/factory"
+	"github.com/openshift/origin/pkg/router/metrics"
 	"github.com/openshift/origin/pkg/util/proc"
 	"github.com/openshift/origin/pkg/version"
 	templateplugin "github.com/openshift/origin/plugins/router/template"
@@ -23,10 +32,61 @@ This command launches a router connected to your OpenShift master. The router li
 created by users and keeps a local router configuration up to date with those changes.
 `
 
+// routerSelection holds the options shared by the router commands to select the routes a
+// router serves and to report their admission.
+type routerSelection struct {
+	// RouteLabels selects the routes served by the router
+	RouteLabels string
+	// NamespaceLabels selects the namespaces whose routes are served by the router
+	NamespaceLabels string
+	// RouterName identifies the router in the status of the routes it admits
+	RouterName string
+	// DisableNamespaceOwnershipCheck lets routes from different namespaces claim the same host
+	DisableNamespaceOwnershipCheck bool
+}
+
+// Bind binds the route selection options to flags.
+func (o *routerSelection) Bind(flag *pflag.FlagSet) {
+	flag.StringVar(&o.RouteLabels, "route-labels", util.Env("ROUTE_LABELS", ""), "A label selector to apply to the routes to serve, such as 'router=public'")
+	flag.StringVar(&o.NamespaceLabels, "namespace-labels", util.Env("NAMESPACE_LABELS", ""), "A label selector to apply to the namespaces whose routes to serve, such as 'network=internal'")
+	flag.StringVar(&o.RouterName, "name", util.Env("ROUTER_SERVICE_NAME", "public"), "The name the router will identify itself with in the route status")
+	flag.BoolVar(&o.DisableNamespaceOwnershipCheck, "disable-namespace-ownership-check", util.Env("DISABLE_NAMESPACE_OWNERSHIP_CHECK", "") == "true", "Allow routes in different namespaces to claim the same host, instead of only the namespace of the oldest route")
+}
+
+// NewFactory returns a factory for router controllers watching the selected routes.
+func (o *routerSelection) NewFactory(kubeClient kclient.Interface, osClient osclient.Interface) (*controllerfactory.RouterControllerFactory, error) {
+	factory := &controllerfactory.RouterControllerFactory{KClient: kubeClient, OSClient: osClient}
+	var err error
+	if len(o.RouteLabels) > 0 {
+		if factory.Labels, err = labels.Parse(o.RouteLabels); err != nil {
+			return nil, fmt.Errorf("Invalid route label selector %q: %v", o.RouteLabels, err)
+		}
+	}
+	if len(o.NamespaceLabels) > 0 {
+		if factory.NamespaceLabels, err = labels.Parse(o.NamespaceLabels); err != nil {
+			return nil, fmt.Errorf("Invalid namespace label selector %q: %v", o.NamespaceLabels, err)
+		}
+	}
+	return factory, nil
+}
+
 type templateRouterConfig struct {
-	Config       *clientcmd.Config
-	TemplateFile string
-	ReloadScript string
+	routerSelection
+	Config         *clientcmd.Config
+	TemplateFile   string
+	ReloadScript   string
+	ReloadInterval string
+	// WorkingDir is the directory the router state and route certificates are written to
+	WorkingDir string
+	// AllowWildcardRoutes lets routes with a Subdomain wildcard policy be served
+	AllowWildcardRoutes bool
+	// ListenAddr is the address the health and metrics endpoints are served on
+	ListenAddr string
+	// EnableMetrics serves the metrics of the router, which expose the routes and traffic of
+	// every namespace, on ListenAddr without authentication
+	EnableMetrics bool
+	// StatsSocket is the path of the HAProxy stats socket to scrape metrics from
+	StatsSocket string
 }
 
 // NewCommndTemplateRouter provides CLI handler for the template router backend
@@ -40,12 +100,7 @@ func NewCommandTemplateRouter(name string) *cobra.Command {
 		Short: "Start an OpenShift router",
 		Long:  longCommandDesc,
 		Run: func(c *cobra.Command, args []string) {
-			plugin, err := makeTemplatePlugin(cfg)
-			if err != nil {
-				glog.Fatal(err)
-			}
-
-			if err = start(cfg.Config, plugin); err != nil {
+			if err := start(cfg); err != nil {
 				glog.Fatal(err)
 			}
 		},
@@ -55,13 +110,20 @@ func NewCommandTemplateRouter(name string) *cobra.Command {
 
 	flag := cmd.Flags()
 	cfg.Config.Bind(flag)
+	cfg.routerSelection.Bind(flag)
 	flag.StringVar(&cfg.TemplateFile, "template", util.Env("TEMPLATE_FILE", ""), "The path to the template file to use")
 	flag.StringVar(&cfg.ReloadScript, "reload", util.Env("RELOAD_SCRIPT", ""), "The path to the reload script to use")
+	flag.StringVar(&cfg.ReloadInterval, "interval", util.Env("RELOAD_INTERVAL", "5s"), "The minimum time between reloads of the router, changes made in between are applied together")
+	flag.StringVar(&cfg.WorkingDir, "working-dir", util.Env("ROUTER_WORKING_DIR", "/var/lib/containers/router"), "The directory to write the router state and route certificates to, the certificates are written to its certs and cacerts subdirectories")
+	flag.BoolVar(&cfg.AllowWildcardRoutes, "allow-wildcard-routes", util.Env("ROUTER_ALLOW_WILDCARD_ROUTES", "") == "true", "Serve routes with a Subdomain wildcard policy for every host in their subdomain")
+	flag.StringVar(&cfg.ListenAddr, "listen-addr", util.Env("ROUTER_LISTEN_ADDR", "0.0.0.0:1935"), "The address to serve /healthz, and /metrics if enabled, on, disabled if empty")
+	flag.BoolVar(&cfg.EnableMetrics, "enable-metrics", util.Env("ROUTER_ENABLE_METRICS", "") == "true", "Serve the metrics of the router and its backends on /metrics without authentication")
+	flag.StringVar(&cfg.StatsSocket, "stats-socket", util.Env("STATS_SOCKET", ""), "The path of the HAProxy stats socket, used to check HAProxy is up and to report metrics about its backends")
 
 	return cmd
 }
 
-func makeTemplatePlugin(cfg *templateRouterConfig) (*templateplugin.TemplatePlugin, error) {
+func makeTemplatePlugin(cfg *templateRouterConfig, committed func()) (*templateplugin.TemplatePlugin, error) {
 	if cfg.TemplateFile == "" {
 		return nil, errors.New("Template file must be specified")
 	}
@@ -70,21 +132,66 @@ func makeTemplatePlugin(cfg *templateRouterConfig) (*templateplugin.TemplatePlug
 		return nil, errors.New("Reload script must be specified")
 	}
 
-	return templateplugin.NewTemplatePlugin(cfg.TemplateFile, cfg.ReloadScript)
+	reloadInterval, err := time.ParseDuration(cfg.ReloadInterval)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid reload interval %q: %v", cfg.ReloadInterval, err)
+	}
+
+	if cfg.WorkingDir == "" {
+		return nil, errors.New("Working directory must be specified")
+	}
+
+	return templateplugin.NewTemplatePlugin(cfg.TemplateFile, cfg.ReloadScript, cfg.WorkingDir, reloadInterval, committed)
 }
 
 // start launches the load balancer.
-func start(cfg *clientcmd.Config, plugin router.Plugin) error {
-	osClient, kubeClient, err := cfg.Clients()
+func start(cfg *templateRouterConfig) error {
+	osClient, kubeClient, err := cfg.Config.Clients()
+	if err != nil {
+		return err
+	}
+
+	// routes are reported as admitted once the router has applied their configuration
+	admitter := controller.NewStatusAdmitter(osClient, cfg.RouterName)
+	templatePlugin, err := makeTemplatePlugin(cfg, admitter.Flush)
+	if err != nil {
+		return err
+	}
+	uniqueHost := controller.NewUniqueHost(templatePlugin, admitter, cfg.DisableNamespaceOwnershipCheck, cfg.AllowWildcardRoutes)
+	// routes with certificates the router cannot load are skipped instead of failing the router
+	plugin := controller.NewExtendedValidator(uniqueHost, admitter)
+
+	if len(cfg.ListenAddr) > 0 {
+		checks := []healthz.HealthzChecker{
+			healthz.NamedCheck("reload", func(*http.Request) error { return templatePlugin.Healthz() }),
+		}
+		if len(cfg.StatsSocket) > 0 {
+			exporter := metrics.NewExporter(cfg.StatsSocket, 5*time.Second)
+			if cfg.EnableMetrics {
+				prometheus.MustRegister(exporter)
+			}
+			checks = append(checks, exporter)
+		}
+		mux := http.NewServeMux()
+		healthz.InstallHandler(mux, checks...)
+		// the metrics name the routes of every namespace, so they are only served on request
+		if cfg.EnableMetrics {
+			mux.Handle("/metrics", prometheus.Handler())
+		}
+		go func() {
+			glog.Fatal(http.ListenAndServe(cfg.ListenAddr, mux))
+		}()
+	}
+
+	factory, err := cfg.NewFactory(kubeClient, osClient)
 	if err != nil {
 		return err
 	}
 
 	proc.StartReaper()
 
-	factory := controllerfactory.RouterControllerFactory{KClient: kubeClient, OSClient: osClient}
-	controller := factory.Create(plugin)
-	controller.Run()
+	routerController := factory.Create(plugin)
+	routerController.Run()
 
 	select {}
 
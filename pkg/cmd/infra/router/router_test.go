@@ -0,0 +1,32 @@
+package router
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// TestRouterSelectionFlags tests that the router commands expose the route selection flags
+func TestRouterSelectionFlags(t *testing.T) {
+	for _, cmd := range []*cobra.Command{NewCommandTemplateRouter("router"), NewCommandF5Router("f5-router")} {
+		for _, name := range []string{"route-labels", "namespace-labels", "name", "disable-namespace-ownership-check"} {
+			if cmd.Flags().Lookup(name) == nil {
+				t.Errorf("%s: expected the flag --%s to be defined", cmd.Name(), name)
+			}
+		}
+		if flag := cmd.Flags().Lookup("name"); flag != nil && flag.DefValue != "public" {
+			t.Errorf("%s: expected the router name to default to public, got %q", cmd.Name(), flag.DefValue)
+		}
+	}
+}
+
+// TestMetricsDisabledByDefault tests that the metrics of the router are only served on request
+func TestMetricsDisabledByDefault(t *testing.T) {
+	flag := NewCommandTemplateRouter("router").Flags().Lookup("enable-metrics")
+	if flag == nil {
+		t.Fatalf("expected the flag --enable-metrics to be defined")
+	}
+	if flag.DefValue != "false" {
+		t.Errorf("expected metrics to be disabled by default, got %q", flag.DefValue)
+	}
+}
@@ -57,6 +57,8 @@ func CommandFor(basename string) *cobra.Command {
 	switch basename {
 	case "openshift-router":
 		cmd = router.NewCommandTemplateRouter(basename)
+	case "openshift-f5-router":
+		cmd = router.NewCommandF5Router(basename)
 	case "openshift-deploy":
 		cmd = deployer.NewCommandDeployer(basename)
 	case "openshift-sti-build":
@@ -105,6 +107,7 @@ func NewCommandOpenShift() *cobra.Command {
 
 	infra.AddCommand(
 		router.NewCommandTemplateRouter("router"),
+		router.NewCommandF5Router("f5-router"),
 		deployer.NewCommandDeployer("deploy"),
 		builder.NewCommandSTIBuilder("sti-build"),
 		builder.NewCommandDockerBuilder("docker-build"),
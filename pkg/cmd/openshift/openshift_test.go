@@ -11,6 +11,11 @@ func TestCommandFor(t *testing.T) {
 		t.Errorf("expected command to start with prefix: %#v", cmd)
 	}
 
+	cmd = CommandFor("openshift-f5-router")
+	if !strings.HasPrefix(cmd.Use, "openshift-f5-router ") {
+		t.Errorf("expected command to start with prefix: %#v", cmd)
+	}
+
 	cmd = CommandFor("unknown")
 	if cmd.Use != "openshift" {
 		t.Errorf("expected command to be openshift: %#v", cmd)
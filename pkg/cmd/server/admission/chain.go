@@ -0,0 +1,26 @@
+package admission
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/admission"
+)
+
+// chainAdmissionHandler is an admission.Interface which runs each of its handlers in
+// order and returns the first error.
+type chainAdmissionHandler []admission.Interface
+
+// NewChain returns an admission control plugin which runs the given plugins in order,
+// so plugins which are not registered by name can be combined with those which are.
+func NewChain(handlers ...admission.Interface) admission.Interface {
+	return chainAdmissionHandler(handlers)
+}
+
+// Admit performs an admission control check using each handler, and returns immediately
+// on the first error.
+func (handlers chainAdmissionHandler) Admit(a admission.Attributes) error {
+	for _, handler := range handlers {
+		if err := handler.Admit(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
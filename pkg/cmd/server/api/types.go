@@ -84,6 +84,16 @@ type MasterConfig struct {
 
 	// PolicyConfig holds information about where to locate critical pieces of bootstrapping policy
 	PolicyConfig PolicyConfig
+
+	// ImagePolicyConfig controls limits and behavior for importing images and tracking their history
+	ImagePolicyConfig ImagePolicyConfig
+}
+
+type ImagePolicyConfig struct {
+	// MaxTagHistory is the default number of images kept in the history of each image stream tag.
+	// Zero keeps every image. An image stream may set its own limit with the
+	// openshift.io/image.maxTagHistory annotation.
+	MaxTagHistory int
 }
 
 type PolicyConfig struct {
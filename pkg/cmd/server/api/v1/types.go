@@ -80,6 +80,16 @@ type MasterConfig struct {
 	ImageConfig ImageConfig `json:"imageConfig"`
 
 	PolicyConfig PolicyConfig `json:"policyConfig"`
+
+	// ImagePolicyConfig controls limits and behavior for importing images and tracking their history
+	ImagePolicyConfig ImagePolicyConfig `json:"imagePolicyConfig"`
+}
+
+type ImagePolicyConfig struct {
+	// MaxTagHistory is the default number of images kept in the history of each image stream tag.
+	// Zero keeps every image. An image stream may set its own limit with the
+	// openshift.io/image.maxTagHistory annotation.
+	MaxTagHistory int `json:"maxTagHistory"`
 }
 
 type PolicyConfig struct {
@@ -75,6 +75,7 @@ func ValidateMasterConfig(config *api.MasterConfig) fielderrors.ValidationErrorL
 	allErrs = append(allErrs, ValidateKubeConfig(config.MasterClients.KubernetesKubeConfig, "kubernetesKubeConfig").Prefix("masterClients")...)
 
 	allErrs = append(allErrs, ValidatePolicyConfig(config.PolicyConfig).Prefix("policyConfig")...)
+	allErrs = append(allErrs, ValidateImagePolicyConfig(config.ImagePolicyConfig).Prefix("imagePolicyConfig")...)
 	if config.OAuthConfig != nil {
 		allErrs = append(allErrs, ValidateOAuthConfig(config.OAuthConfig).Prefix("oauthConfig")...)
 	}
@@ -136,6 +137,16 @@ func ValidateImageConfig(config api.ImageConfig) fielderrors.ValidationErrorList
 	return allErrs
 }
 
+func ValidateImagePolicyConfig(config api.ImagePolicyConfig) fielderrors.ValidationErrorList {
+	allErrs := fielderrors.ValidationErrorList{}
+
+	if config.MaxTagHistory < 0 {
+		allErrs = append(allErrs, fielderrors.NewFieldInvalid("maxTagHistory", config.MaxTagHistory, "must be zero or more"))
+	}
+
+	return allErrs
+}
+
 func ValidateKubeletConnectionInfo(config api.KubeletConnectionInfo) fielderrors.ValidationErrorList {
 	allErrs := fielderrors.ValidationErrorList{}
 	if config.Port == 0 {
@@ -42,6 +42,8 @@ const (
 	DeployerRoleName          = "system:deployer"
 	RouterRoleName            = "system:router"
 	RegistryRoleName          = "system:registry"
+	ImagePullerRoleName       = "system:image-puller"
+	ImagePusherRoleName       = "system:image-pusher"
 	InternalComponentRoleName = "system:component"
 	DeleteTokensRoleName      = "system:delete-tokens"
 
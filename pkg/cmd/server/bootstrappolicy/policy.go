@@ -174,6 +174,14 @@ func GetBootstrapMasterRoles(masterNamespace string) []authorizationapi.Role {
 					Verbs:     util.NewStringSet("list", "watch"),
 					Resources: util.NewStringSet("routes", "endpoints"),
 				},
+				{
+					Verbs:     util.NewStringSet("list"),
+					Resources: util.NewStringSet("namespaces"),
+				},
+				{
+					Verbs:     util.NewStringSet("update"),
+					Resources: util.NewStringSet("routes/status"),
+				},
 			},
 		},
 		{
@@ -190,6 +198,42 @@ func GetBootstrapMasterRoles(masterNamespace string) []authorizationapi.Role {
 					Verbs:     util.NewStringSet("create"),
 					Resources: util.NewStringSet("imagerepositorymappings"),
 				},
+				{
+					Verbs:     util.NewStringSet("get", "list"),
+					Resources: util.NewStringSet("imagestreams"),
+				},
+				{
+					Verbs:     util.NewStringSet("get"),
+					Resources: util.NewStringSet("imagestreamtags", "imagestreamimages", "namespaces"),
+				},
+				{
+					Verbs:     util.NewStringSet("list"),
+					Resources: util.NewStringSet("resourcequotas", "secrets"),
+				},
+			},
+		},
+		{
+			ObjectMeta: kapi.ObjectMeta{
+				Name:      ImagePullerRoleName,
+				Namespace: masterNamespace,
+			},
+			Rules: []authorizationapi.PolicyRule{
+				{
+					Verbs:     util.NewStringSet("get"),
+					Resources: util.NewStringSet("imagestreams/layers"),
+				},
+			},
+		},
+		{
+			ObjectMeta: kapi.ObjectMeta{
+				Name:      ImagePusherRoleName,
+				Namespace: masterNamespace,
+			},
+			Rules: []authorizationapi.PolicyRule{
+				{
+					Verbs:     util.NewStringSet("get", "update"),
+					Resources: util.NewStringSet("imagestreams/layers"),
+				},
 			},
 		},
 	}
@@ -15,6 +15,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
 
 	"github.com/openshift/origin/pkg/cmd/flagtypes"
+	serveradmission "github.com/openshift/origin/pkg/cmd/server/admission"
 	configapi "github.com/openshift/origin/pkg/cmd/server/api"
 	"github.com/openshift/origin/pkg/cmd/server/etcd"
 )
@@ -38,7 +39,7 @@ type MasterConfig struct {
 	SchedulerConfigFile string
 }
 
-func BuildKubernetesMasterConfig(options configapi.MasterConfig, requestContextMapper kapi.RequestContextMapper, kubeClient *kclient.Client) (*MasterConfig, error) {
+func BuildKubernetesMasterConfig(options configapi.MasterConfig, requestContextMapper kapi.RequestContextMapper, kubeClient *kclient.Client, imageSignaturePolicy admission.Interface) (*MasterConfig, error) {
 	if options.KubernetesMasterConfig == nil {
 		return nil, errors.New("insufficient information to build KubernetesMasterConfig")
 	}
@@ -80,7 +81,7 @@ func BuildKubernetesMasterConfig(options configapi.MasterConfig, requestContextM
 		KubeClient:           kubeClient,
 		KubeletClientConfig:  kubeletClientConfig,
 		Authorizer:           apiserver.NewAlwaysAllowAuthorizer(),
-		AdmissionControl:     admissionController,
+		AdmissionControl:     serveradmission.NewChain(admissionController, imageSignaturePolicy),
 		SchedulerConfigFile:  options.KubernetesMasterConfig.SchedulerConfigFile,
 	}
 
@@ -26,6 +26,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/rest"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
 	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	kmaster "github.com/GoogleCloudPlatform/kubernetes/pkg/master"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	utilerrs "github.com/GoogleCloudPlatform/kubernetes/pkg/util/errors"
@@ -161,7 +162,7 @@ func (c *MasterConfig) InstallProtectedAPI(container *restful.Container) []strin
 
 	imageStorage := imageetcd.NewREST(c.EtcdHelper)
 	imageRegistry := image.NewRegistry(imageStorage)
-	imageStreamStorage, imageStreamStatusStorage := imagestreametcd.NewREST(c.EtcdHelper, imagestream.DefaultRegistryFunc(defaultRegistryFunc), subjectAccessReviewRegistry)
+	imageStreamStorage, imageStreamStatusStorage := imagestreametcd.NewREST(c.EtcdHelper, imagestream.DefaultRegistryFunc(defaultRegistryFunc), subjectAccessReviewRegistry, c.Options.ImagePolicyConfig.MaxTagHistory)
 	imageStreamRegistry := imagestream.NewRegistry(imageStreamStorage, imageStreamStatusStorage)
 	imageStreamMappingStorage := imagestreammapping.NewREST(imageRegistry, imageStreamRegistry)
 	imageStreamMappingRegistry := imagestreammapping.NewRegistry(imageStreamMappingStorage)
@@ -202,6 +203,11 @@ func (c *MasterConfig) InstallProtectedAPI(container *restful.Container) []strin
 		GRFn: deployRollback.GenerateRollback,
 	}
 
+	deployScaleClient := &deployconfigregistry.DeploymentClientImpl{
+		ListDeploymentsFunc:  clientDeploymentInterface{kclient}.ListDeployments,
+		UpdateDeploymentFunc: clientDeploymentInterface{kclient}.UpdateDeployment,
+	}
+
 	projectStorage := projectproxy.NewREST(kclient.Namespaces(), c.ProjectAuthorizationCache)
 
 	// initialize OpenShift API
@@ -224,7 +230,8 @@ func (c *MasterConfig) InstallProtectedAPI(container *restful.Container) []strin
 		"imageRepositoryTags":      imageRepositoryTagStorage,
 
 		"deployments":               deployregistry.NewREST(deployEtcd),
-		"deploymentConfigs":         deployconfigregistry.NewREST(deployEtcd),
+		"deploymentConfigs":         deployconfigregistry.NewREST(deployEtcd, subjectAccessReviewRegistry),
+		"deploymentConfigs/scale":   deployconfigregistry.NewScaleREST(deployEtcd, deployScaleClient),
 		"generateDeploymentConfigs": deployconfiggenerator.NewREST(deployConfigGenerator, latest.Codec),
 		"deploymentConfigRollbacks": deployrollback.NewREST(deployRollbackClient, latest.Codec),
 
@@ -233,7 +240,8 @@ func (c *MasterConfig) InstallProtectedAPI(container *restful.Container) []strin
 		// DEPRECATED: remove with v1beta1
 		"templateConfigs": templateregistry.NewREST(true),
 
-		"routes": routeregistry.NewREST(routeEtcd, routeAllocator),
+		"routes":        routeregistry.NewREST(routeEtcd, routeAllocator),
+		"routes/status": routeregistry.NewStatusREST(routeEtcd),
 
 		"projects":        projectStorage,
 		"projectRequests": projectrequeststorage.NewREST(c.Options.PolicyConfig.MasterAuthorizationNamespace, roleBindingStorage, *projectStorage),
@@ -844,9 +852,10 @@ func (c *MasterConfig) RouteAllocator() *routeallocationcontroller.RouteAllocati
 }
 
 func (c *MasterConfig) RunImageImportController() {
-	osclient := c.ImageImportControllerClient()
+	osclient, kclient := c.ImageImportControllerClients()
 	factory := imagecontroller.ImportControllerFactory{
-		Client: osclient,
+		Client:     osclient,
+		KubeClient: kclient,
 	}
 	controller := factory.Create()
 	controller.Run()
@@ -882,6 +891,14 @@ func (c clientDeploymentInterface) GetDeployment(ctx api.Context, name string) (
 	return c.KubeClient.ReplicationControllers(api.NamespaceValue(ctx)).Get(name)
 }
 
+func (c clientDeploymentInterface) ListDeployments(ctx api.Context) (*api.ReplicationControllerList, error) {
+	return c.KubeClient.ReplicationControllers(api.NamespaceValue(ctx)).List(labels.Everything())
+}
+
+func (c clientDeploymentInterface) UpdateDeployment(ctx api.Context, deployment *api.ReplicationController) (*api.ReplicationController, error) {
+	return c.KubeClient.ReplicationControllers(api.NamespaceValue(ctx)).Update(deployment)
+}
+
 // namespacingFilter adds a filter that adds the namespace of the request to the context.  Not all requests will have namespaces,
 // but any that do will have the appropriate value added.
 func namespacingFilter(handler http.Handler, contextMapper kapi.RequestContextMapper) http.Handler {
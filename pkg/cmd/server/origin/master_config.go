@@ -31,9 +31,11 @@ import (
 	policybindingetcd "github.com/openshift/origin/pkg/authorization/registry/policybinding/etcd"
 	"github.com/openshift/origin/pkg/authorization/rulevalidation"
 	osclient "github.com/openshift/origin/pkg/client"
+	serveradmission "github.com/openshift/origin/pkg/cmd/server/admission"
 	configapi "github.com/openshift/origin/pkg/cmd/server/api"
 	"github.com/openshift/origin/pkg/cmd/server/etcd"
 	"github.com/openshift/origin/pkg/cmd/util/variable"
+	imageadmission "github.com/openshift/origin/pkg/image/admission"
 	accesstokenregistry "github.com/openshift/origin/pkg/oauth/registry/oauthaccesstoken"
 	accesstokenetcd "github.com/openshift/origin/pkg/oauth/registry/oauthaccesstoken/etcd"
 	projectauth "github.com/openshift/origin/pkg/project/auth"
@@ -61,6 +63,9 @@ type MasterConfig struct {
 	RequestContextMapper kapi.RequestContextMapper
 
 	AdmissionControl admission.Interface
+	// ImageSignaturePolicy rejects pods and deployment configs running images which are not
+	// signed by a key trusted by their project
+	ImageSignaturePolicy admission.Interface
 
 	TLS bool
 
@@ -139,6 +144,9 @@ func BuildMasterConfig(options configapi.MasterConfig) (*MasterConfig, error) {
 	// in-order list of plug-ins that should intercept admission decisions (origin only intercepts)
 	admissionControlPluginNames := []string{"OriginNamespaceLifecycle"}
 	admissionController := admission.NewFromPlugins(kubeClient, admissionControlPluginNames, "")
+	// the deployer and builder images are referenced by tag, so they are not verified
+	infraImages := []string{imageTemplate.ExpandOrDie("deployer"), imageTemplate.ExpandOrDie("docker-builder"), imageTemplate.ExpandOrDie("sti-builder")}
+	imageSignaturePolicy := imageadmission.NewSignaturePolicy(kubeClient, kubeClient, openshiftClient, infraImages)
 
 	config := &MasterConfig{
 		Options: options,
@@ -152,7 +160,8 @@ func BuildMasterConfig(options configapi.MasterConfig) (*MasterConfig, error) {
 
 		RequestContextMapper: requestContextMapper,
 
-		AdmissionControl: admissionController,
+		AdmissionControl:     serveradmission.NewChain(admissionController, imageSignaturePolicy),
+		ImageSignaturePolicy: imageSignaturePolicy,
 
 		TLS: configapi.UseTLS(options.ServingInfo),
 
@@ -284,9 +293,9 @@ func (c *MasterConfig) ImageChangeControllerClient() *osclient.Client {
 	return c.OSClient
 }
 
-// ImageImportControllerClient returns the deployment client object
-func (c *MasterConfig) ImageImportControllerClient() *osclient.Client {
-	return c.OSClient
+// ImageImportControllerClients returns the image import controller client objects
+func (c *MasterConfig) ImageImportControllerClients() (*osclient.Client, *kclient.Client) {
+	return c.OSClient, c.KubernetesClient
 }
 
 // DeploymentControllerClients returns the deployment controller client object
@@ -330,7 +330,7 @@ func StartMaster(openshiftMasterConfig *configapi.MasterConfig) error {
 	if openshiftMasterConfig.KubernetesMasterConfig != nil {
 		glog.Infof("Static Nodes: %v", openshiftMasterConfig.KubernetesMasterConfig.StaticNodeNames)
 
-		kubeConfig, err := kubernetes.BuildKubernetesMasterConfig(*openshiftMasterConfig, openshiftConfig.RequestContextMapper, openshiftConfig.KubeClient())
+		kubeConfig, err := kubernetes.BuildKubernetesMasterConfig(*openshiftMasterConfig, openshiftConfig.RequestContextMapper, openshiftConfig.KubeClient(), openshiftConfig.ImageSignaturePolicy)
 		if err != nil {
 			return err
 		}
@@ -11,6 +11,7 @@ func init() {
 		&DeploymentConfig{},
 		&DeploymentConfigList{},
 		&DeploymentConfigRollback{},
+		&DeploymentConfigScale{},
 	)
 }
 
@@ -19,3 +20,4 @@ func (*DeploymentList) IsAnAPIObject()           {}
 func (*DeploymentConfig) IsAnAPIObject()         {}
 func (*DeploymentConfigList) IsAnAPIObject()     {}
 func (*DeploymentConfigRollback) IsAnAPIObject() {}
+func (*DeploymentConfigScale) IsAnAPIObject()    {}
@@ -152,6 +152,13 @@ const (
 	// annotation value is the LatestVersion value of the DeploymentConfig which was the basis for
 	// the deployment.
 	DeploymentVersionAnnotation = "deploymentVersion"
+	// DeploymentCancelledAnnotation is an annotation on a deployment (a ReplicationController).
+	// When set to DeploymentCancelledAnnotationValue, the deployment was cancelled by a user and
+	// its status is no longer synchronized with its deployer Pod.
+	DeploymentCancelledAnnotation = "deploymentCancelled"
+	// DeploymentCancelledAnnotationValue is the value of DeploymentCancelledAnnotation for a
+	// cancelled deployment.
+	DeploymentCancelledAnnotationValue = "true"
 	// DeploymentLabel is the name of a label used to correlate a deployment with the Pod created
 	// to execute the deployment logic.
 	// TODO: This is a workaround for upstream's lack of annotation support on PodTemplate. Once
@@ -255,6 +262,11 @@ type DeploymentCauseImageTrigger struct {
 	RepositoryName string `json:"repositoryName,omitempty"`
 	// Tag is the name of an image repository tag that is now pointing to a new image.
 	Tag string `json:"tag,omitempty"`
+	// From is a reference to the image repository that was updated.
+	From kapi.ObjectReference `json:"from,omitempty"`
+	// Image is the pull spec of the image that fired the trigger, pinned to its immutable ID
+	// where possible.
+	Image string `json:"image,omitempty"`
 }
 
 // A DeploymentConfigList is a collection of deployment configs.
@@ -284,3 +296,12 @@ type DeploymentConfigRollbackSpec struct {
 	// IncludeStrategy specifies whether to include the deployment Strategy.
 	IncludeStrategy bool `json:"includeStrategy"`
 }
+
+// DeploymentConfigScale represents the desired replica count of a deployment config
+// and its active deployment.
+type DeploymentConfigScale struct {
+	kapi.TypeMeta   `json:",inline"`
+	kapi.ObjectMeta `json:"metadata,omitempty"`
+	// Replicas is the desired number of replicas.
+	Replicas int `json:"replicas"`
+}
@@ -70,6 +70,20 @@ func ValidateDeploymentConfigRollback(rollback *deployapi.DeploymentConfigRollba
 	return result
 }
 
+func ValidateDeploymentConfigScale(scale *deployapi.DeploymentConfigScale) fielderrors.ValidationErrorList {
+	result := fielderrors.ValidationErrorList{}
+
+	if len(scale.Name) == 0 {
+		result = append(result, fielderrors.NewFieldRequired("name"))
+	}
+
+	if scale.Replicas < 0 {
+		result = append(result, fielderrors.NewFieldInvalid("replicas", scale.Replicas, "replicas cannot be negative"))
+	}
+
+	return result
+}
+
 func validateDeploymentStrategy(strategy *deployapi.DeploymentStrategy) fielderrors.ValidationErrorList {
 	errs := fielderrors.ValidationErrorList{}
 
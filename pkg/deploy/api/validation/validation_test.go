@@ -410,6 +410,44 @@ func TestValidateDeploymentConfigRollbackInvalidFields(t *testing.T) {
 	}
 }
 
+func TestValidateDeploymentConfigScale(t *testing.T) {
+	errorCases := map[string]struct {
+		S api.DeploymentConfigScale
+		T fielderrors.ValidationErrorType
+		F string
+	}{
+		"missing name": {
+			api.DeploymentConfigScale{Replicas: 1},
+			fielderrors.ValidationErrorTypeRequired,
+			"name",
+		},
+		"negative replicas": {
+			api.DeploymentConfigScale{ObjectMeta: kapi.ObjectMeta{Name: "config"}, Replicas: -1},
+			fielderrors.ValidationErrorTypeInvalid,
+			"replicas",
+		},
+	}
+
+	for k, v := range errorCases {
+		errs := ValidateDeploymentConfigScale(&v.S)
+		if len(errs) != 1 {
+			t.Errorf("%s: expected one error, got %v", k, errs)
+			continue
+		}
+		if errs[0].(*fielderrors.ValidationError).Type != v.T {
+			t.Errorf("%s: expected errors to have type %s: %v", k, v.T, errs[0])
+		}
+		if errs[0].(*fielderrors.ValidationError).Field != v.F {
+			t.Errorf("%s: expected errors to have field %s: %v", k, v.F, errs[0])
+		}
+	}
+
+	ok := &api.DeploymentConfigScale{ObjectMeta: kapi.ObjectMeta{Name: "config"}}
+	if errs := ValidateDeploymentConfigScale(ok); len(errs) > 0 {
+		t.Errorf("Unexpected non-empty error list: %v", errs)
+	}
+}
+
 func TestValidateDeploymentConfigDefaultImageStreamKind(t *testing.T) {
 	config := &api.DeploymentConfig{
 		ObjectMeta: kapi.ObjectMeta{Name: "foo", Namespace: "bar"},
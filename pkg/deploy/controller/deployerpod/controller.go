@@ -6,8 +6,10 @@ import (
 	"github.com/golang/glog"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/record"
 
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	deployutil "github.com/openshift/origin/pkg/deploy/util"
 )
 
 // DeployerPodController keeps a deployment's status in sync with the deployer pod
@@ -17,6 +19,8 @@ import (
 type DeployerPodController struct {
 	// deploymentClient provides access to deployments.
 	deploymentClient deploymentClient
+	// recorder records events for deployments.
+	recorder record.EventRecorder
 }
 
 // Handle syncs pod's status with any associated deployment.
@@ -33,8 +37,15 @@ func (c *DeployerPodController) Handle(pod *kapi.Pod) error {
 		return fmt.Errorf("couldn't get deployment %s/%s associated with pod %s", pod.Namespace, deploymentName, pod.Name)
 	}
 
+	// A cancelled deployment's status is no longer driven by its deployer pod.
+	if deployutil.IsDeploymentCancelled(deployment) {
+		glog.V(4).Infof("Ignoring pod %s for cancelled deployment %s", pod.Name, labelForDeployment(deployment))
+		return nil
+	}
+
 	currentStatus := statusFor(deployment)
 	nextStatus := currentStatus
+	exitCode := 0
 
 	switch pod.Status.Phase {
 	case kapi.PodRunning:
@@ -45,6 +56,7 @@ func (c *DeployerPodController) Handle(pod *kapi.Pod) error {
 		for _, info := range pod.Status.ContainerStatuses {
 			if info.State.Termination != nil && info.State.Termination.ExitCode != 0 {
 				nextStatus = deployapi.DeploymentStatusFailed
+				exitCode = info.State.Termination.ExitCode
 			}
 		}
 	}
@@ -55,6 +67,15 @@ func (c *DeployerPodController) Handle(pod *kapi.Pod) error {
 			return fmt.Errorf("couldn't update deployment %s to status %s: %v", labelForDeployment(deployment), nextStatus, err)
 		}
 		glog.V(2).Infof("Updated deployment %s status from %s to %s", labelForDeployment(deployment), currentStatus, nextStatus)
+
+		switch nextStatus {
+		case deployapi.DeploymentStatusRunning:
+			c.recorder.Eventf(deployment, "deploymentRunning", "Deployer pod %s is running", pod.Name)
+		case deployapi.DeploymentStatusComplete:
+			c.recorder.Eventf(deployment, "deploymentCompleted", "Deployment completed successfully")
+		case deployapi.DeploymentStatusFailed:
+			c.recorder.Eventf(deployment, "deploymentFailed", "Deployer pod %s failed with exit code %d", pod.Name, exitCode)
+		}
 	}
 
 	return nil
@@ -5,9 +5,11 @@ import (
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/record"
 
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
 	deploytest "github.com/openshift/origin/pkg/deploy/api/test"
+	controllertest "github.com/openshift/origin/pkg/deploy/controller/test"
 	deployutil "github.com/openshift/origin/pkg/deploy/util"
 )
 
@@ -21,6 +23,7 @@ func TestHandle_uncorrelatedPod(t *testing.T) {
 				return nil, nil
 			},
 		},
+		recorder: &record.FakeRecorder{},
 	}
 
 	// Verify no-op
@@ -46,6 +49,7 @@ func TestHandle_orphanedPod(t *testing.T) {
 				return nil, kerrors.NewNotFound("ReplicationController", name)
 			},
 		},
+		recorder: &record.FakeRecorder{},
 	}
 
 	err := controller.Handle(runningPod())
@@ -59,6 +63,7 @@ func TestHandle_orphanedPod(t *testing.T) {
 // transition of the deployment's status to running.
 func TestHandle_runningPod(t *testing.T) {
 	var updatedDeployment *kapi.ReplicationController
+	recorder := &controllertest.FakeEventRecorder{}
 
 	controller := &DeployerPodController{
 		deploymentClient: &deploymentClientImpl{
@@ -73,6 +78,7 @@ func TestHandle_runningPod(t *testing.T) {
 				return deployment, nil
 			},
 		},
+		recorder: recorder,
 	}
 
 	err := controller.Handle(runningPod())
@@ -88,12 +94,17 @@ func TestHandle_runningPod(t *testing.T) {
 	if e, a := deployapi.DeploymentStatusRunning, statusFor(updatedDeployment); e != a {
 		t.Fatalf("expected updated deployment status %s, got %s", e, a)
 	}
+
+	if !recorder.HasReason("deploymentRunning") {
+		t.Fatalf("expected a deploymentRunning event, got %v", recorder.Reasons)
+	}
 }
 
 // TestHandle_podTerminatedOk ensures that a successfully completed deployer
 // pod results in a transition of the deployment's status to complete.
 func TestHandle_podTerminatedOk(t *testing.T) {
 	var updatedDeployment *kapi.ReplicationController
+	recorder := &controllertest.FakeEventRecorder{}
 
 	controller := &DeployerPodController{
 		deploymentClient: &deploymentClientImpl{
@@ -108,6 +119,7 @@ func TestHandle_podTerminatedOk(t *testing.T) {
 				return deployment, nil
 			},
 		},
+		recorder: recorder,
 	}
 
 	err := controller.Handle(succeededPod())
@@ -123,12 +135,17 @@ func TestHandle_podTerminatedOk(t *testing.T) {
 	if e, a := deployapi.DeploymentStatusComplete, statusFor(updatedDeployment); e != a {
 		t.Fatalf("expected updated deployment status %s, got %s", e, a)
 	}
+
+	if !recorder.HasReason("deploymentCompleted") {
+		t.Fatalf("expected a deploymentCompleted event, got %v", recorder.Reasons)
+	}
 }
 
 // TestHandle_podTerminatedFail ensures that a failed deployer pod results in
 // a transition of the deployment's status to failed.
 func TestHandle_podTerminatedFail(t *testing.T) {
 	var updatedDeployment *kapi.ReplicationController
+	recorder := &controllertest.FakeEventRecorder{}
 
 	controller := &DeployerPodController{
 		deploymentClient: &deploymentClientImpl{
@@ -143,6 +160,7 @@ func TestHandle_podTerminatedFail(t *testing.T) {
 				return deployment, nil
 			},
 		},
+		recorder: recorder,
 	}
 
 	err := controller.Handle(failedPod())
@@ -158,6 +176,37 @@ func TestHandle_podTerminatedFail(t *testing.T) {
 	if e, a := deployapi.DeploymentStatusFailed, statusFor(updatedDeployment); e != a {
 		t.Fatalf("expected updated deployment status %s, got %s", e, a)
 	}
+
+	if !recorder.HasReason("deploymentFailed") {
+		t.Fatalf("expected a deploymentFailed event, got %v", recorder.Reasons)
+	}
+}
+
+// TestHandle_cancelledDeployment ensures that a deployer pod for a cancelled
+// deployment doesn't change the deployment's status.
+func TestHandle_cancelledDeployment(t *testing.T) {
+	controller := &DeployerPodController{
+		deploymentClient: &deploymentClientImpl{
+			getDeploymentFunc: func(namespace, name string) (*kapi.ReplicationController, error) {
+				config := deploytest.OkDeploymentConfig(1)
+				deployment, _ := deployutil.MakeDeployment(config, kapi.Codec)
+				deployment.Annotations[deployapi.DeploymentStatusAnnotation] = string(deployapi.DeploymentStatusFailed)
+				deployment.Annotations[deployapi.DeploymentCancelledAnnotation] = deployapi.DeploymentCancelledAnnotationValue
+				return deployment, nil
+			},
+			updateDeploymentFunc: func(namespace string, deployment *kapi.ReplicationController) (*kapi.ReplicationController, error) {
+				t.Fatalf("unexpected deployment update")
+				return nil, nil
+			},
+		},
+		recorder: &record.FakeRecorder{},
+	}
+
+	err := controller.Handle(succeededPod())
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 }
 
 func okPod() *kapi.Pod {
@@ -64,6 +64,7 @@ func (c *DeploymentController) Handle(deployment *kapi.ReplicationController) er
 			}
 		} else {
 			glog.V(2).Infof("Created pod %s for deployment %s", deploymentPod.Name, labelForDeployment(deployment))
+			c.recorder.Eventf(deployment, "deployerPodCreated", "Created deployer pod %s", deploymentPod.Name)
 		}
 
 		deployment.Annotations[deployapi.DeploymentPodAnnotation] = deploymentPod.Name
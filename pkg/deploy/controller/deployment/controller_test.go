@@ -11,6 +11,7 @@ import (
 	api "github.com/openshift/origin/pkg/api/latest"
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
 	deploytest "github.com/openshift/origin/pkg/deploy/api/test"
+	controllertest "github.com/openshift/origin/pkg/deploy/controller/test"
 	deployutil "github.com/openshift/origin/pkg/deploy/util"
 )
 
@@ -21,6 +22,7 @@ func TestHandle_createPodOk(t *testing.T) {
 		updatedDeployment *kapi.ReplicationController
 		createdPod        *kapi.Pod
 		expectedContainer = okContainer()
+		recorder          = &controllertest.FakeEventRecorder{}
 	)
 
 	controller := &DeploymentController{
@@ -42,7 +44,7 @@ func TestHandle_createPodOk(t *testing.T) {
 		makeContainer: func(strategy *deployapi.DeploymentStrategy) (*kapi.Container, error) {
 			return expectedContainer, nil
 		},
-		recorder: &record.FakeRecorder{},
+		recorder: recorder,
 	}
 
 	// Verify new -> pending
@@ -100,6 +102,10 @@ func TestHandle_createPodOk(t *testing.T) {
 	if e, a := expectedContainer.Env[0].Value, actualContainer.Env[0].Value; e != a {
 		t.Fatalf("expected container env value %s, got %s", expectedContainer.Env[0].Value, actualContainer.Env[0].Value)
 	}
+
+	if !recorder.HasReason("deployerPodCreated") {
+		t.Fatalf("expected a deployerPodCreated event, got %v", recorder.Reasons)
+	}
 }
 
 // TestHandle_makeContainerFail ensures that an internal (not API) failure to
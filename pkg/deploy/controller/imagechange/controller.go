@@ -54,9 +54,11 @@ func (c *ImageChangeController) Handle(imageRepo *imageapi.ImageStream) error {
 				continue
 			}
 
-			// Ensure a change occured
-			if len(latestEvent.DockerImageReference) > 0 &&
-				latestEvent.DockerImageReference != params.LastTriggeredImage {
+			// Ensure a change occured. The generator pins containers to the
+			// immutable image where possible, but configs triggered before that
+			// recorded the unpinned reference, which is the same image.
+			if image := imageapi.PinnedImageReference(latestEvent); len(image) > 0 &&
+				!imageapi.ReferencesTagEvent(params.LastTriggeredImage, latestEvent) {
 				// Mark the config for regeneration
 				configsToUpdate[config.Name] = config
 			}
@@ -89,6 +89,12 @@ func TestHandle_changeForUnregisteredTag(t *testing.T) {
 // image repo updates to ensure that the image change triggers match (or don't
 // match) properly.
 func TestHandle_matchScenarios(t *testing.T) {
+	digestImage := "sha256:3c87c572822935df60f0f5d3665bd376841a7fcfeb806b5f212de6a00e9a7b25"
+	pinnedImage := imageapi.PinnedImageReference(&imageapi.TagEvent{
+		DockerImageReference: "registry:8080/other/test-image:latest",
+		Image:                digestImage,
+	})
+
 	params := map[string]*deployapi.DeploymentTriggerImageChangeParams{
 		"params.1": {
 			Automatic:          true,
@@ -132,6 +138,29 @@ func TestHandle_matchScenarios(t *testing.T) {
 			Tag:                imageapi.DefaultImageTag,
 			LastTriggeredImage: "",
 		},
+		// This references a stream in another namespace
+		"params.7": {
+			Automatic:          true,
+			ContainerNames:     []string{"container-1"},
+			From:               kapi.ObjectReference{Namespace: "other", Name: "repoA"},
+			Tag:                imageapi.DefaultImageTag,
+			LastTriggeredImage: "",
+		},
+		"params.8": {
+			Automatic:          true,
+			ContainerNames:     []string{"container-1"},
+			From:               kapi.ObjectReference{Namespace: "other", Name: "repoA"},
+			Tag:                imageapi.DefaultImageTag,
+			LastTriggeredImage: pinnedImage,
+		},
+		// This was triggered before images were pinned
+		"params.9": {
+			Automatic:          true,
+			ContainerNames:     []string{"container-1"},
+			From:               kapi.ObjectReference{Namespace: "other", Name: "repoA"},
+			Tag:                imageapi.DefaultImageTag,
+			LastTriggeredImage: "registry:8080/other/test-image:latest",
+		},
 	}
 
 	tagHistoryFor := func(tag, dir, image string) map[string]imageapi.TagEventList {
@@ -171,6 +200,17 @@ func TestHandle_matchScenarios(t *testing.T) {
 				),
 			},
 		},
+		// This one lives in another namespace and records a content addressable image
+		"update.3": {
+			ObjectMeta: kapi.ObjectMeta{Name: "repoA", Namespace: "other"},
+			Status: imageapi.ImageStreamStatus{
+				Tags: tagHistoryFor(
+					imageapi.DefaultImageTag,
+					"registry:8080/other/test-image:latest",
+					digestImage,
+				),
+			},
+		},
 	}
 
 	scenarios := []struct {
@@ -194,6 +234,14 @@ func TestHandle_matchScenarios(t *testing.T) {
 		{"params.5", "update.1", false},
 		// Trigger repo reference doesn't match
 		{"params.6", "update.1", false},
+		// Trigger repo reference in another namespace
+		{"params.7", "update.3", true},
+		// Trigger repo reference in another namespace doesn't match the local repo
+		{"params.7", "update.1", false},
+		// Pinned image is equal to the last triggered image
+		{"params.8", "update.3", false},
+		// Unpinned last triggered image of the same image
+		{"params.9", "update.3", false},
 	}
 
 	for _, s := range scenarios {
@@ -0,0 +1,33 @@
+package test
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+)
+
+// FakeEventRecorder is an EventRecorder which remembers the reason and
+// message of every event it receives.
+type FakeEventRecorder struct {
+	Reasons  []string
+	Messages []string
+}
+
+func (r *FakeEventRecorder) Event(object runtime.Object, reason, message string) {
+	r.Reasons = append(r.Reasons, reason)
+	r.Messages = append(r.Messages, message)
+}
+
+func (r *FakeEventRecorder) Eventf(object runtime.Object, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// HasReason returns true if an event with reason was recorded.
+func (r *FakeEventRecorder) HasReason(reason string) bool {
+	for _, recorded := range r.Reasons {
+		if recorded == reason {
+			return true
+		}
+	}
+	return false
+}
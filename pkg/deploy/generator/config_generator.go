@@ -61,6 +61,10 @@ func (g *DeploymentConfigGenerator) Generate(ctx kapi.Context, name string) (*de
 			continue
 		}
 
+		// Pin containers to the immutable image where possible so that later
+		// changes to the tag don't alter what this deployment runs
+		image := imageapi.PinnedImageReference(latestEvent)
+
 		// Update containers
 		template := config.Template.ControllerTemplate.Template
 		names := util.NewStringSet(params.ContainerNames...)
@@ -70,12 +74,11 @@ func (g *DeploymentConfigGenerator) Generate(ctx kapi.Context, name string) (*de
 			if !names.Has(container.Name) {
 				continue
 			}
-			if len(latestEvent.DockerImageReference) > 0 &&
-				container.Image != latestEvent.DockerImageReference {
+			if len(image) > 0 && !imageapi.ReferencesTagEvent(container.Image, latestEvent) {
 				// Update the image
-				container.Image = latestEvent.DockerImageReference
+				container.Image = image
 				// Log the last triggered image ID
-				params.LastTriggeredImage = latestEvent.DockerImageReference
+				params.LastTriggeredImage = image
 				containerChanged = true
 			}
 		}
@@ -89,6 +92,12 @@ func (g *DeploymentConfigGenerator) Generate(ctx kapi.Context, name string) (*de
 					ImageTrigger: &deployapi.DeploymentCauseImageTrigger{
 						RepositoryName: latestEvent.DockerImageReference,
 						Tag:            params.Tag,
+						From: kapi.ObjectReference{
+							Kind:      "ImageStream",
+							Namespace: imageStream.Namespace,
+							Name:      imageStream.Name,
+						},
+						Image: image,
 					},
 				})
 		}
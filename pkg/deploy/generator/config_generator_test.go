@@ -195,6 +195,60 @@ func TestGenerate_fromConfigWithUpdatedImageRef(t *testing.T) {
 	}
 }
 
+func TestGenerate_pinsImageByID(t *testing.T) {
+	imageID := "sha256:3c87c572822935df60f0f5d3665bd376841a7fcfeb806b5f212de6a00e9a7b25"
+	pinned := imageapi.DockerImageReference{
+		Registry:  "registry:8080",
+		Namespace: "openshift",
+		Name:      "test-image",
+		ID:        imageID,
+	}.String()
+
+	generator := &DeploymentConfigGenerator{
+		Client: Client{
+			DCFn: func(ctx kapi.Context, id string) (*deployapi.DeploymentConfig, error) {
+				return deploytest.OkDeploymentConfig(1), nil
+			},
+			ISFn: func(ctx kapi.Context, name string) (*imageapi.ImageStream, error) {
+				stream := makeStream(
+					"test-image-stream",
+					imageapi.DefaultImageTag,
+					"registry:8080/openshift/test-image:latest",
+					imageID,
+				)
+				stream.Namespace = kapi.NamespaceDefault
+				return stream, nil
+			},
+		},
+	}
+
+	config, err := generator.Generate(kapi.NewDefaultContext(), "deploy1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if e, a := pinned, config.Template.ControllerTemplate.Template.Spec.Containers[0].Image; e != a {
+		t.Fatalf("Expected container image %s, got %s", e, a)
+	}
+	if e, a := pinned, config.Triggers[0].ImageChangeParams.LastTriggeredImage; e != a {
+		t.Fatalf("Expected LastTriggeredImage %s, got %s", e, a)
+	}
+
+	cause := config.Details.Causes[0].ImageTrigger
+	if e, a := pinned, cause.Image; e != a {
+		t.Fatalf("Expected cause image %s, got %s", e, a)
+	}
+	if e, a := "registry:8080/openshift/test-image:latest", cause.RepositoryName; e != a {
+		t.Fatalf("Expected cause repository %s, got %s", e, a)
+	}
+	if e, a := "test-image-stream", cause.From.Name; e != a {
+		t.Fatalf("Expected cause from name %s, got %s", e, a)
+	}
+	if e, a := kapi.NamespaceDefault, cause.From.Namespace; e != a {
+		t.Fatalf("Expected cause from namespace %s, got %s", e, a)
+	}
+}
+
 func TestGenerate_reportsInvalidErrorWhenMissingRepo(t *testing.T) {
 	generator := &DeploymentConfigGenerator{
 		Client: Client{
@@ -252,3 +306,36 @@ func makeStream(name, tag, dir, image string) *imageapi.ImageStream {
 		},
 	}
 }
+
+func TestGenerate_unpinnedImageIsUnchanged(t *testing.T) {
+	imageID := "sha256:3c87c572822935df60f0f5d3665bd376841a7fcfeb806b5f212de6a00e9a7b25"
+	unpinned := "registry:8080/openshift/test-image:latest"
+
+	generator := &DeploymentConfigGenerator{
+		Client: Client{
+			DCFn: func(ctx kapi.Context, id string) (*deployapi.DeploymentConfig, error) {
+				// deployed before images were pinned
+				config := deploytest.OkDeploymentConfig(1)
+				config.Template.ControllerTemplate.Template.Spec.Containers[0].Image = unpinned
+				config.Triggers[0].ImageChangeParams.LastTriggeredImage = unpinned
+				return config, nil
+			},
+			ISFn: func(ctx kapi.Context, name string) (*imageapi.ImageStream, error) {
+				stream := makeStream("test-image-stream", imageapi.DefaultImageTag, unpinned, imageID)
+				stream.Namespace = kapi.NamespaceDefault
+				return stream, nil
+			},
+		},
+	}
+
+	config, err := generator.Generate(kapi.NewDefaultContext(), "deploy1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.LatestVersion != 1 {
+		t.Fatalf("Expected config LatestVersion=1, got %d", config.LatestVersion)
+	}
+	if e, a := unpinned, config.Template.ControllerTemplate.Template.Spec.Containers[0].Image; e != a {
+		t.Fatalf("Expected container image %s, got %s", e, a)
+	}
+}
@@ -10,21 +10,29 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/fielderrors"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/golang/glog"
 
+	authorizationapi "github.com/openshift/origin/pkg/authorization/api"
+	"github.com/openshift/origin/pkg/authorization/registry/subjectaccessreview"
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
 	validation "github.com/openshift/origin/pkg/deploy/api/validation"
 )
 
 // REST is an implementation of RESTStorage for the api server.
 type REST struct {
-	registry Registry
+	registry                  Registry
+	subjectAccessReviewClient subjectaccessreview.Registry
 }
 
-// NewREST creates a new REST backed by the given registry.
-func NewREST(registry Registry) *REST {
+// NewREST creates a new REST backed by the given registry. The subjectAccessReviewClient
+// is used to verify that users may read image streams in other namespaces which are
+// referenced by image change triggers.
+func NewREST(registry Registry, subjectAccessReviewClient subjectaccessreview.Registry) *REST {
 	return &REST{
-		registry: registry,
+		registry:                  registry,
+		subjectAccessReviewClient: subjectAccessReviewClient,
 	}
 }
 
@@ -85,6 +93,9 @@ func (s *REST) Create(ctx kapi.Context, obj runtime.Object) (runtime.Object, err
 	if errs := validation.ValidateDeploymentConfig(deploymentConfig); len(errs) > 0 {
 		return nil, kerrors.NewInvalid("deploymentConfig", deploymentConfig.Name, errs)
 	}
+	if errs := s.verifyTriggerAccess(ctx, nil, deploymentConfig); len(errs) > 0 {
+		return nil, kerrors.NewInvalid("deploymentConfig", deploymentConfig.Name, errs)
+	}
 
 	err := s.registry.CreateDeploymentConfig(ctx, deploymentConfig)
 	if err != nil {
@@ -106,6 +117,16 @@ func (s *REST) Update(ctx kapi.Context, obj runtime.Object) (runtime.Object, boo
 		return nil, false, kerrors.NewConflict("deploymentConfig", deploymentConfig.Namespace, fmt.Errorf("DeploymentConfig.Namespace does not match the provided context"))
 	}
 
+	if hasCrossNamespaceTriggers(deploymentConfig) {
+		old, err := s.registry.GetDeploymentConfig(ctx, deploymentConfig.Name)
+		if err != nil {
+			return nil, false, err
+		}
+		if errs := s.verifyTriggerAccess(ctx, old, deploymentConfig); len(errs) > 0 {
+			return nil, false, kerrors.NewInvalid("deploymentConfig", deploymentConfig.Name, errs)
+		}
+	}
+
 	err := s.registry.UpdateDeploymentConfig(ctx, deploymentConfig)
 	if err != nil {
 		return nil, false, err
@@ -113,3 +134,76 @@ func (s *REST) Update(ctx kapi.Context, obj runtime.Object) (runtime.Object, boo
 	out, err := s.Get(ctx, deploymentConfig.Name)
 	return out, false, err
 }
+
+// crossNamespaceImageTrigger returns the namespace of the image stream referenced by
+// trigger if it differs from namespace, or false otherwise.
+func crossNamespaceImageTrigger(trigger deployapi.DeploymentTriggerPolicy, namespace string) (string, bool) {
+	if trigger.Type != deployapi.DeploymentTriggerOnImageChange || trigger.ImageChangeParams == nil {
+		return "", false
+	}
+	from := trigger.ImageChangeParams.From
+	if len(from.Name) == 0 || len(from.Namespace) == 0 || from.Namespace == namespace {
+		return "", false
+	}
+	return from.Namespace, true
+}
+
+// hasCrossNamespaceTriggers returns true if config has any image change triggers which
+// reference an image stream in another namespace.
+func hasCrossNamespaceTriggers(config *deployapi.DeploymentConfig) bool {
+	for _, trigger := range config.Triggers {
+		if _, ok := crossNamespaceImageTrigger(trigger, config.Namespace); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyTriggerAccess ensures the user on ctx may get every image stream in another
+// namespace that config newly references from an image change trigger. Triggers which
+// are unchanged from old are not verified again.
+func (s *REST) verifyTriggerAccess(ctx kapi.Context, old, config *deployapi.DeploymentConfig) fielderrors.ValidationErrorList {
+	errs := fielderrors.ValidationErrorList{}
+
+	existing := map[kapi.ObjectReference]bool{}
+	if old != nil {
+		for _, trigger := range old.Triggers {
+			if _, ok := crossNamespaceImageTrigger(trigger, old.Namespace); ok {
+				from := trigger.ImageChangeParams.From
+				existing[kapi.ObjectReference{Namespace: from.Namespace, Name: from.Name}] = true
+			}
+		}
+	}
+
+	for i, trigger := range config.Triggers {
+		namespace, ok := crossNamespaceImageTrigger(trigger, config.Namespace)
+		if !ok {
+			continue
+		}
+		from := trigger.ImageChangeParams.From
+		if existing[kapi.ObjectReference{Namespace: from.Namespace, Name: from.Name}] {
+			continue
+		}
+
+		field := fmt.Sprintf("triggers[%d].imageChange.from", i)
+		user, ok := kapi.UserFrom(ctx)
+		if !ok {
+			errs = append(errs, fielderrors.NewFieldForbidden(field, fmt.Sprintf("%s/%s", namespace, from.Name)))
+			continue
+		}
+
+		subjectAccessReview := &authorizationapi.SubjectAccessReview{
+			Verb:         "get",
+			Resource:     "imageStream",
+			User:         user.GetName(),
+			ResourceName: from.Name,
+		}
+		glog.V(1).Infof("Performing SubjectAccessReview for user %s to %s/%s", user.GetName(), namespace, from.Name)
+		resp, err := s.subjectAccessReviewClient.CreateSubjectAccessReview(kapi.WithNamespace(kapi.NewContext(), namespace), subjectAccessReview)
+		if err != nil || resp == nil || !resp.Allowed {
+			errs = append(errs, fielderrors.NewFieldForbidden(field, fmt.Sprintf("%s/%s", namespace, from.Name)))
+		}
+	}
+
+	return errs
+}
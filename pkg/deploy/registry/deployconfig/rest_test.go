@@ -7,9 +7,12 @@ import (
 	"testing"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/user"
 	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+
+	authorizationapi "github.com/openshift/origin/pkg/authorization/api"
 	"github.com/openshift/origin/pkg/deploy/api"
 	deploytest "github.com/openshift/origin/pkg/deploy/api/test"
 	"github.com/openshift/origin/pkg/deploy/registry/test"
@@ -298,3 +301,102 @@ func checkExpectedNamespaceError(t *testing.T, err error) {
 	}
 
 }
+
+type fakeSubjectAccessReviewRegistry struct {
+	allow            bool
+	request          *authorizationapi.SubjectAccessReview
+	requestNamespace string
+}
+
+func (f *fakeSubjectAccessReviewRegistry) CreateSubjectAccessReview(ctx kapi.Context, subjectAccessReview *authorizationapi.SubjectAccessReview) (*authorizationapi.SubjectAccessReviewResponse, error) {
+	f.request = subjectAccessReview
+	f.requestNamespace = kapi.NamespaceValue(ctx)
+	return &authorizationapi.SubjectAccessReviewResponse{Allowed: f.allow}, nil
+}
+
+func configWithImageTrigger(fromNamespace string) *api.DeploymentConfig {
+	config := deploytest.OkDeploymentConfig(0)
+	config.Namespace = kapi.NamespaceDefault
+	config.Triggers = []api.DeploymentTriggerPolicy{
+		{
+			Type: api.DeploymentTriggerOnImageChange,
+			ImageChangeParams: &api.DeploymentTriggerImageChangeParams{
+				Automatic:      true,
+				ContainerNames: []string{"container1"},
+				From:           kapi.ObjectReference{Namespace: fromNamespace, Name: "stream"},
+				Tag:            "latest",
+			},
+		},
+	}
+	return config
+}
+
+func TestVerifyTriggerAccess(t *testing.T) {
+	userCtx := kapi.WithUser(kapi.NewDefaultContext(), &user.DefaultInfo{Name: "user"})
+
+	tests := map[string]struct {
+		ctx          kapi.Context
+		old          *api.DeploymentConfig
+		config       *api.DeploymentConfig
+		allow        bool
+		expectReview bool
+		expectErrors bool
+	}{
+		"same namespace": {
+			ctx:    userCtx,
+			config: configWithImageTrigger(kapi.NamespaceDefault),
+		},
+		"other namespace allowed": {
+			ctx:          userCtx,
+			config:       configWithImageTrigger("other"),
+			allow:        true,
+			expectReview: true,
+		},
+		"other namespace denied": {
+			ctx:          userCtx,
+			config:       configWithImageTrigger("other"),
+			expectReview: true,
+			expectErrors: true,
+		},
+		"other namespace unchanged": {
+			ctx:    userCtx,
+			old:    configWithImageTrigger("other"),
+			config: configWithImageTrigger("other"),
+		},
+		"other namespace without user": {
+			ctx:          kapi.NewDefaultContext(),
+			config:       configWithImageTrigger("other"),
+			allow:        true,
+			expectErrors: true,
+		},
+	}
+
+	for name, test := range tests {
+		sar := &fakeSubjectAccessReviewRegistry{allow: test.allow}
+		storage := REST{subjectAccessReviewClient: sar}
+
+		errs := storage.verifyTriggerAccess(test.ctx, test.old, test.config)
+		if test.expectErrors != (len(errs) > 0) {
+			t.Errorf("%s: unexpected errors: %v", name, errs)
+		}
+		if !test.expectReview {
+			if sar.request != nil {
+				t.Errorf("%s: unexpected subject access review: %#v", name, sar.request)
+			}
+			continue
+		}
+		if sar.request == nil {
+			t.Errorf("%s: expected a subject access review", name)
+			continue
+		}
+		if e, a := "other", sar.requestNamespace; e != a {
+			t.Errorf("%s: expected review namespace %s, got %s", name, e, a)
+		}
+		if e, a := "stream", sar.request.ResourceName; e != a {
+			t.Errorf("%s: expected review resource name %s, got %s", name, e, a)
+		}
+		if e, a := "user", sar.request.User; e != a {
+			t.Errorf("%s: expected review user %s, got %s", name, e, a)
+		}
+	}
+}
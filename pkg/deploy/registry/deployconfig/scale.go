@@ -0,0 +1,144 @@
+package deployconfig
+
+import (
+	"fmt"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/golang/glog"
+
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	"github.com/openshift/origin/pkg/deploy/api/validation"
+	deployutil "github.com/openshift/origin/pkg/deploy/util"
+)
+
+// DeploymentClient defines a local interface to the deployments of a config for testability.
+type DeploymentClient interface {
+	ListDeployments(ctx kapi.Context) (*kapi.ReplicationControllerList, error)
+	UpdateDeployment(ctx kapi.Context, deployment *kapi.ReplicationController) (*kapi.ReplicationController, error)
+}
+
+// DeploymentClientImpl is a pluggable DeploymentClient.
+type DeploymentClientImpl struct {
+	ListDeploymentsFunc  func(ctx kapi.Context) (*kapi.ReplicationControllerList, error)
+	UpdateDeploymentFunc func(ctx kapi.Context, deployment *kapi.ReplicationController) (*kapi.ReplicationController, error)
+}
+
+func (i *DeploymentClientImpl) ListDeployments(ctx kapi.Context) (*kapi.ReplicationControllerList, error) {
+	return i.ListDeploymentsFunc(ctx)
+}
+
+func (i *DeploymentClientImpl) UpdateDeployment(ctx kapi.Context, deployment *kapi.ReplicationController) (*kapi.ReplicationController, error) {
+	return i.UpdateDeploymentFunc(ctx, deployment)
+}
+
+// ScaleREST implements the scale subresource of DeploymentConfigs. Scaling updates
+// both the replica count of the config template and of the active deployment so
+// that the next deployment does not revert the change.
+type ScaleREST struct {
+	registry    Registry
+	deployments DeploymentClient
+}
+
+// NewScaleREST creates a new ScaleREST backed by the given registry and deployments.
+func NewScaleREST(registry Registry, deployments DeploymentClient) *ScaleREST {
+	return &ScaleREST{
+		registry:    registry,
+		deployments: deployments,
+	}
+}
+
+// New creates a new DeploymentConfigScale for use with Update.
+func (s *ScaleREST) New() runtime.Object {
+	return &deployapi.DeploymentConfigScale{}
+}
+
+// Get returns the desired replica count of the DeploymentConfig specified by its id.
+func (s *ScaleREST) Get(ctx kapi.Context, id string) (runtime.Object, error) {
+	config, err := s.registry.GetDeploymentConfig(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return scaleFromConfig(config), nil
+}
+
+// Update sets the replica count of the config template and of the active deployment.
+// Scaling is rejected while a deployment of the config is in progress. If the active
+// deployment cannot be updated, the config template is restored to its previous value.
+func (s *ScaleREST) Update(ctx kapi.Context, obj runtime.Object) (runtime.Object, bool, error) {
+	scale, ok := obj.(*deployapi.DeploymentConfigScale)
+	if !ok {
+		return nil, false, fmt.Errorf("not a deploymentConfigScale: %#v", obj)
+	}
+	if errs := validation.ValidateDeploymentConfigScale(scale); len(errs) > 0 {
+		return nil, false, kerrors.NewInvalid("deploymentConfigScale", scale.Name, errs)
+	}
+
+	config, err := s.registry.GetDeploymentConfig(ctx, scale.Name)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(scale.ResourceVersion) > 0 && scale.ResourceVersion != config.ResourceVersion {
+		return nil, false, kerrors.NewConflict("deploymentConfig", config.Name, fmt.Errorf("the deployment config has been modified; please apply your changes to the latest version and try again"))
+	}
+
+	list, err := s.deployments.ListDeployments(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	var active *kapi.ReplicationController
+	deployments := deployutil.ConfigDeployments(list, config.Name)
+	for i := range deployments {
+		deployment := &deployments[i]
+		switch deployutil.DeploymentStatusFor(deployment) {
+		case deployapi.DeploymentStatusNew, deployapi.DeploymentStatusPending, deployapi.DeploymentStatusRunning:
+			return nil, false, kerrors.NewConflict("deploymentConfig", config.Name, fmt.Errorf("deployment %s is in progress", deployment.Name))
+		case deployapi.DeploymentStatusComplete:
+			if active == nil {
+				active = deployment
+			}
+		}
+	}
+
+	previous := config.Template.ControllerTemplate.Replicas
+	config.Template.ControllerTemplate.Replicas = scale.Replicas
+	if err := s.registry.UpdateDeploymentConfig(ctx, config); err != nil {
+		return nil, false, err
+	}
+
+	if active != nil && active.Spec.Replicas != scale.Replicas {
+		active.Spec.Replicas = scale.Replicas
+		if _, err := s.deployments.UpdateDeployment(ctx, active); err != nil {
+			if revertErr := s.revertReplicas(ctx, config.Name, previous); revertErr != nil {
+				glog.Errorf("Couldn't restore replicas of deploymentConfig %s/%s to %d: %v", config.Namespace, config.Name, previous, revertErr)
+			}
+			return nil, false, err
+		}
+	}
+
+	out, err := s.Get(ctx, config.Name)
+	return out, false, err
+}
+
+// revertReplicas restores the replica count of the config template.
+func (s *ScaleREST) revertReplicas(ctx kapi.Context, name string, replicas int) error {
+	config, err := s.registry.GetDeploymentConfig(ctx, name)
+	if err != nil {
+		return err
+	}
+	config.Template.ControllerTemplate.Replicas = replicas
+	return s.registry.UpdateDeploymentConfig(ctx, config)
+}
+
+func scaleFromConfig(config *deployapi.DeploymentConfig) *deployapi.DeploymentConfigScale {
+	return &deployapi.DeploymentConfigScale{
+		ObjectMeta: kapi.ObjectMeta{
+			Name:              config.Name,
+			Namespace:         config.Namespace,
+			ResourceVersion:   config.ResourceVersion,
+			CreationTimestamp: config.CreationTimestamp,
+		},
+		Replicas: config.Template.ControllerTemplate.Replicas,
+	}
+}
@@ -0,0 +1,163 @@
+package deployconfig
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+
+	"github.com/openshift/origin/pkg/deploy/api"
+	deploytest "github.com/openshift/origin/pkg/deploy/api/test"
+	"github.com/openshift/origin/pkg/deploy/registry/test"
+)
+
+func scaleDeployment(version int, status api.DeploymentStatus, replicas int) kapi.ReplicationController {
+	return kapi.ReplicationController{
+		ObjectMeta: kapi.ObjectMeta{
+			Name: fmt.Sprintf("config-%d", version),
+			Annotations: map[string]string{
+				api.DeploymentConfigAnnotation:  "config",
+				api.DeploymentVersionAnnotation: strconv.Itoa(version),
+				api.DeploymentStatusAnnotation:  string(status),
+			},
+		},
+		Spec: kapi.ReplicationControllerSpec{Replicas: replicas},
+	}
+}
+
+func scaleConfig(replicas int) *api.DeploymentConfig {
+	config := deploytest.OkDeploymentConfig(2)
+	config.Name = "config"
+	config.Template.ControllerTemplate.Replicas = replicas
+	return config
+}
+
+func TestScaleGet(t *testing.T) {
+	registry := test.NewDeploymentConfigRegistry()
+	registry.DeploymentConfig = scaleConfig(3)
+	storage := NewScaleREST(registry, &DeploymentClientImpl{})
+
+	obj, err := storage.Get(kapi.NewDefaultContext(), "config")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	scale := obj.(*api.DeploymentConfigScale)
+	if e, a := 3, scale.Replicas; e != a {
+		t.Fatalf("Expected replicas %d, got %d", e, a)
+	}
+	if e, a := "config", scale.Name; e != a {
+		t.Fatalf("Expected name %s, got %s", e, a)
+	}
+}
+
+func TestScaleUpdate(t *testing.T) {
+	registry := test.NewDeploymentConfigRegistry()
+	registry.DeploymentConfig = scaleConfig(1)
+
+	var updated *kapi.ReplicationController
+	storage := NewScaleREST(registry, &DeploymentClientImpl{
+		ListDeploymentsFunc: func(ctx kapi.Context) (*kapi.ReplicationControllerList, error) {
+			return &kapi.ReplicationControllerList{
+				Items: []kapi.ReplicationController{
+					scaleDeployment(1, api.DeploymentStatusComplete, 0),
+					scaleDeployment(2, api.DeploymentStatusComplete, 1),
+				},
+			}, nil
+		},
+		UpdateDeploymentFunc: func(ctx kapi.Context, deployment *kapi.ReplicationController) (*kapi.ReplicationController, error) {
+			updated = deployment
+			return deployment, nil
+		},
+	})
+
+	scale := &api.DeploymentConfigScale{ObjectMeta: kapi.ObjectMeta{Name: "config"}, Replicas: 5}
+	obj, _, err := storage.Update(kapi.NewDefaultContext(), scale)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if e, a := 5, obj.(*api.DeploymentConfigScale).Replicas; e != a {
+		t.Fatalf("Expected replicas %d, got %d", e, a)
+	}
+	if e, a := 5, registry.DeploymentConfig.Template.ControllerTemplate.Replicas; e != a {
+		t.Fatalf("Expected config replicas %d, got %d", e, a)
+	}
+	if updated == nil {
+		t.Fatalf("Expected the active deployment to be updated")
+	}
+	if e, a := "config-2", updated.Name; e != a {
+		t.Fatalf("Expected deployment %s to be updated, got %s", e, a)
+	}
+	if e, a := 5, updated.Spec.Replicas; e != a {
+		t.Fatalf("Expected deployment replicas %d, got %d", e, a)
+	}
+}
+
+func TestScaleUpdate_deploymentInProgress(t *testing.T) {
+	registry := test.NewDeploymentConfigRegistry()
+	registry.DeploymentConfig = scaleConfig(1)
+
+	storage := NewScaleREST(registry, &DeploymentClientImpl{
+		ListDeploymentsFunc: func(ctx kapi.Context) (*kapi.ReplicationControllerList, error) {
+			return &kapi.ReplicationControllerList{
+				Items: []kapi.ReplicationController{
+					scaleDeployment(1, api.DeploymentStatusComplete, 1),
+					scaleDeployment(2, api.DeploymentStatusRunning, 0),
+				},
+			}, nil
+		},
+		UpdateDeploymentFunc: func(ctx kapi.Context, deployment *kapi.ReplicationController) (*kapi.ReplicationController, error) {
+			t.Fatalf("Unexpected deployment update")
+			return nil, nil
+		},
+	})
+
+	scale := &api.DeploymentConfigScale{ObjectMeta: kapi.ObjectMeta{Name: "config"}, Replicas: 5}
+	_, _, err := storage.Update(kapi.NewDefaultContext(), scale)
+	if err == nil || !kerrors.IsConflict(err) {
+		t.Fatalf("Expected a conflict error, got %v", err)
+	}
+	if e, a := 1, registry.DeploymentConfig.Template.ControllerTemplate.Replicas; e != a {
+		t.Fatalf("Expected config replicas %d, got %d", e, a)
+	}
+}
+
+func TestScaleUpdate_revertsConfigOnDeploymentError(t *testing.T) {
+	registry := test.NewDeploymentConfigRegistry()
+	registry.DeploymentConfig = scaleConfig(1)
+
+	storage := NewScaleREST(registry, &DeploymentClientImpl{
+		ListDeploymentsFunc: func(ctx kapi.Context) (*kapi.ReplicationControllerList, error) {
+			return &kapi.ReplicationControllerList{
+				Items: []kapi.ReplicationController{
+					scaleDeployment(2, api.DeploymentStatusComplete, 1),
+				},
+			}, nil
+		},
+		UpdateDeploymentFunc: func(ctx kapi.Context, deployment *kapi.ReplicationController) (*kapi.ReplicationController, error) {
+			return nil, fmt.Errorf("update failed")
+		},
+	})
+
+	scale := &api.DeploymentConfigScale{ObjectMeta: kapi.ObjectMeta{Name: "config"}, Replicas: 5}
+	if _, _, err := storage.Update(kapi.NewDefaultContext(), scale); err == nil {
+		t.Fatalf("Expected an error")
+	}
+	if e, a := 1, registry.DeploymentConfig.Template.ControllerTemplate.Replicas; e != a {
+		t.Fatalf("Expected config replicas to be restored to %d, got %d", e, a)
+	}
+}
+
+func TestScaleUpdate_invalid(t *testing.T) {
+	registry := test.NewDeploymentConfigRegistry()
+	registry.DeploymentConfig = scaleConfig(1)
+	storage := NewScaleREST(registry, &DeploymentClientImpl{})
+
+	scale := &api.DeploymentConfigScale{ObjectMeta: kapi.ObjectMeta{Name: "config"}, Replicas: -1}
+	_, _, err := storage.Update(kapi.NewDefaultContext(), scale)
+	if err == nil || !kerrors.IsInvalid(err) {
+		t.Fatalf("Expected an invalid error, got %v", err)
+	}
+}
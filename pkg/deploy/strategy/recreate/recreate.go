@@ -9,6 +9,7 @@ import (
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/record"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
@@ -30,6 +31,8 @@ type RecreateDeploymentStrategy struct {
 	codec runtime.Codec
 	// hookExecutor can execute a lifecycle hook.
 	hookExecutor hookExecutor
+	// recorder records events for the deployment.
+	recorder record.EventRecorder
 
 	retryTimeout time.Duration
 	retryPeriod  time.Duration
@@ -51,6 +54,8 @@ func NewRecreateDeploymentStrategy(client kclient.Interface, codec runtime.Codec
 				},
 			},
 		},
+		// the deployer exits once the deployment is done, so events are sent synchronously
+		recorder:     stratsupport.NewEventRecorder(client, kapi.EventSource{Component: "deployer"}),
 		retryTimeout: 10 * time.Second,
 		retryPeriod:  1 * time.Second,
 	}
@@ -71,11 +76,13 @@ func (s *RecreateDeploymentStrategy) Deploy(deployment *kapi.ReplicationControll
 		if preHook != nil {
 		preHookLoop:
 			for {
+				s.recorder.Event(deployment, "hookStarted", "Running pre-deployment hook")
 				err := s.hookExecutor.Execute(preHook, deployment)
 				if err == nil {
 					glog.Info("Pre hook finished successfully")
 					break
 				}
+				s.recorder.Eventf(deployment, "hookFailed", "Pre-deployment hook failed (failure policy %s): %v", preHook.FailurePolicy, err)
 				switch preHook.FailurePolicy {
 				case deployapi.LifecycleHookFailurePolicyAbort:
 					return fmt.Errorf("Pre hook failed, aborting: %s", err)
@@ -91,7 +98,10 @@ func (s *RecreateDeploymentStrategy) Deploy(deployment *kapi.ReplicationControll
 	}
 
 	// Scale up the new deployment.
-	if err = s.updateReplicas(deployment.Namespace, deployment.Name, deploymentConfig.Template.ControllerTemplate.Replicas); err != nil {
+	replicas := deploymentConfig.Template.ControllerTemplate.Replicas
+	s.recorder.Eventf(deployment, "scalingUp", "Scaling %s up to %d", deployment.Name, replicas)
+	if err = s.updateReplicas(deployment.Namespace, deployment.Name, replicas); err != nil {
+		s.recorder.Eventf(deployment, "scaleUpFailed", "Couldn't scale %s up to %d: %v", deployment.Name, replicas, err)
 		return err
 	}
 
@@ -99,8 +109,10 @@ func (s *RecreateDeploymentStrategy) Deploy(deployment *kapi.ReplicationControll
 	glog.Infof("Found %d prior deployments to disable", len(oldDeployments))
 	allProcessed := true
 	for _, oldDeployment := range oldDeployments {
+		s.recorder.Eventf(deployment, "scalingDown", "Scaling prior deployment %s down to 0", oldDeployment.Name)
 		if err = s.updateReplicas(oldDeployment.Namespace, oldDeployment.Name, 0); err != nil {
 			glog.Errorf("%v", err)
+			s.recorder.Eventf(deployment, "scaleDownFailed", "Couldn't scale prior deployment %s down to 0: %v", oldDeployment.Name, err)
 			allProcessed = false
 		}
 	}
@@ -111,11 +123,13 @@ func (s *RecreateDeploymentStrategy) Deploy(deployment *kapi.ReplicationControll
 		if postHook != nil {
 		postHookLoop:
 			for {
+				s.recorder.Event(deployment, "hookStarted", "Running post-deployment hook")
 				err := s.hookExecutor.Execute(postHook, deployment)
 				if err == nil {
 					glog.Info("Post hook finished successfully")
 					break
 				}
+				s.recorder.Eventf(deployment, "hookFailed", "Post-deployment hook failed (failure policy %s): %v", postHook.FailurePolicy, err)
 				switch postHook.FailurePolicy {
 				case deployapi.LifecycleHookFailurePolicyIgnore, deployapi.LifecycleHookFailurePolicyAbort:
 					// Abort isn't supported here, so treat it like ignore.
@@ -6,10 +6,12 @@ import (
 	"time"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/record"
 
 	api "github.com/openshift/origin/pkg/api/latest"
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
 	deploytest "github.com/openshift/origin/pkg/deploy/api/test"
+	controllertest "github.com/openshift/origin/pkg/deploy/controller/test"
 	deployutil "github.com/openshift/origin/pkg/deploy/util"
 )
 
@@ -17,8 +19,10 @@ func TestRecreate_initialDeployment(t *testing.T) {
 	var updatedController *kapi.ReplicationController
 	deployment, _ := deployutil.MakeDeployment(deploytest.OkDeploymentConfig(1), kapi.Codec)
 
+	recorder := &controllertest.FakeEventRecorder{}
 	strategy := &RecreateDeploymentStrategy{
 		codec:        api.Codec,
+		recorder:     recorder,
 		retryTimeout: 1 * time.Second,
 		retryPeriod:  1 * time.Millisecond,
 		client: &testControllerClient{
@@ -50,6 +54,10 @@ func TestRecreate_initialDeployment(t *testing.T) {
 	if e, a := 1, updatedController.Spec.Replicas; e != a {
 		t.Fatalf("expected controller replicas to be %d, got %d", e, a)
 	}
+
+	if !recorder.HasReason("scalingUp") {
+		t.Fatalf("expected a scalingUp event, got %v", recorder.Reasons)
+	}
 }
 
 func TestRecreate_secondDeploymentWithSuccessfulRetries(t *testing.T) {
@@ -62,6 +70,7 @@ func TestRecreate_secondDeploymentWithSuccessfulRetries(t *testing.T) {
 
 	strategy := &RecreateDeploymentStrategy{
 		codec:        api.Codec,
+		recorder:     &record.FakeRecorder{},
 		retryTimeout: 1 * time.Second,
 		retryPeriod:  1 * time.Millisecond,
 		client: &testControllerClient{
@@ -120,6 +129,7 @@ func TestRecreate_secondDeploymentScaleUpRetries(t *testing.T) {
 
 	strategy := &RecreateDeploymentStrategy{
 		codec:        api.Codec,
+		recorder:     &record.FakeRecorder{},
 		retryTimeout: 1 * time.Millisecond,
 		retryPeriod:  1 * time.Millisecond,
 		client: &testControllerClient{
@@ -167,8 +177,10 @@ func TestRecreate_secondDeploymentScaleDownRetries(t *testing.T) {
 	newConfig := deploytest.OkDeploymentConfig(2)
 	newDeployment, _ := deployutil.MakeDeployment(newConfig, kapi.Codec)
 
+	recorder := &controllertest.FakeEventRecorder{}
 	strategy := &RecreateDeploymentStrategy{
 		codec:        api.Codec,
+		recorder:     recorder,
 		retryTimeout: 1 * time.Millisecond,
 		retryPeriod:  1 * time.Millisecond,
 		client: &testControllerClient{
@@ -216,6 +228,10 @@ func TestRecreate_secondDeploymentScaleDownRetries(t *testing.T) {
 	if len(updatedControllers) > 0 {
 		t.Fatalf("unexpected controller updates: %v", updatedControllers)
 	}
+
+	if !recorder.HasReason("scaleDownFailed") {
+		t.Fatalf("expected a scaleDownFailed event, got %v", recorder.Reasons)
+	}
 }
 
 func TestRecreate_deploymentPreHookSuccess(t *testing.T) {
@@ -226,6 +242,7 @@ func TestRecreate_deploymentPreHookSuccess(t *testing.T) {
 
 	strategy := &RecreateDeploymentStrategy{
 		codec:        api.Codec,
+		recorder:     &record.FakeRecorder{},
 		retryTimeout: 1 * time.Second,
 		retryPeriod:  1 * time.Millisecond,
 		client: &testControllerClient{
@@ -264,8 +281,10 @@ func TestRecreate_deploymentPreHookFailAbort(t *testing.T) {
 	config.Template.Strategy.RecreateParams = recreateParams(deployapi.LifecycleHookFailurePolicyAbort, "")
 	deployment, _ := deployutil.MakeDeployment(config, kapi.Codec)
 
+	recorder := &controllertest.FakeEventRecorder{}
 	strategy := &RecreateDeploymentStrategy{
 		codec:        api.Codec,
+		recorder:     recorder,
 		retryTimeout: 1 * time.Second,
 		retryPeriod:  1 * time.Millisecond,
 		client: &testControllerClient{
@@ -290,6 +309,10 @@ func TestRecreate_deploymentPreHookFailAbort(t *testing.T) {
 		t.Fatalf("expected a deploy error")
 	}
 	t.Logf("got expected error: %s", err)
+
+	if !recorder.HasReason("hookFailed") {
+		t.Fatalf("expected a hookFailed event, got %v", recorder.Reasons)
+	}
 }
 
 func TestRecreate_deploymentPreHookFailureIgnored(t *testing.T) {
@@ -300,6 +323,7 @@ func TestRecreate_deploymentPreHookFailureIgnored(t *testing.T) {
 
 	strategy := &RecreateDeploymentStrategy{
 		codec:        api.Codec,
+		recorder:     &record.FakeRecorder{},
 		retryTimeout: 1 * time.Second,
 		retryPeriod:  1 * time.Millisecond,
 		client: &testControllerClient{
@@ -342,6 +366,7 @@ func TestRecreate_deploymentPreHookFailureRetried(t *testing.T) {
 	errorCount := 2
 	strategy := &RecreateDeploymentStrategy{
 		codec:        api.Codec,
+		recorder:     &record.FakeRecorder{},
 		retryTimeout: 1 * time.Second,
 		retryPeriod:  1 * time.Millisecond,
 		client: &testControllerClient{
@@ -387,6 +412,7 @@ func TestRecreate_deploymentPostHookSuccess(t *testing.T) {
 
 	strategy := &RecreateDeploymentStrategy{
 		codec:        api.Codec,
+		recorder:     &record.FakeRecorder{},
 		retryTimeout: 1 * time.Second,
 		retryPeriod:  1 * time.Millisecond,
 		client: &testControllerClient{
@@ -428,6 +454,7 @@ func TestRecreate_deploymentPostHookAbortUnsupported(t *testing.T) {
 
 	strategy := &RecreateDeploymentStrategy{
 		codec:        api.Codec,
+		recorder:     &record.FakeRecorder{},
 		retryTimeout: 1 * time.Second,
 		retryPeriod:  1 * time.Millisecond,
 		client: &testControllerClient{
@@ -469,6 +496,7 @@ func TestRecreate_deploymentPostHookFailIgnore(t *testing.T) {
 
 	strategy := &RecreateDeploymentStrategy{
 		codec:        api.Codec,
+		recorder:     &record.FakeRecorder{},
 		retryTimeout: 1 * time.Second,
 		retryPeriod:  1 * time.Millisecond,
 		client: &testControllerClient{
@@ -511,6 +539,7 @@ func TestRecreate_deploymentPostHookFailureRetried(t *testing.T) {
 	errorCount := 2
 	strategy := &RecreateDeploymentStrategy{
 		codec:        api.Codec,
+		recorder:     &record.FakeRecorder{},
 		retryTimeout: 1 * time.Second,
 		retryPeriod:  1 * time.Millisecond,
 		client: &testControllerClient{
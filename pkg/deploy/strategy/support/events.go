@@ -0,0 +1,59 @@
+package support
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/record"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+)
+
+// eventRecorder is a record.EventRecorder which creates each event before returning.
+// Deployer processes exit as soon as the deployment is done, which would drop the
+// events an asynchronous record.EventBroadcaster has not sent yet.
+type eventRecorder struct {
+	events kclient.EventNamespacer
+	source kapi.EventSource
+}
+
+// NewEventRecorder returns a record.EventRecorder which synchronously creates events
+// from source with the events client.
+func NewEventRecorder(events kclient.EventNamespacer, source kapi.EventSource) record.EventRecorder {
+	return &eventRecorder{events: events, source: source}
+}
+
+// Event creates an event about object. Failures are logged, since events are informational.
+func (r *eventRecorder) Event(object runtime.Object, reason, message string) {
+	ref, err := kapi.GetReference(object)
+	if err != nil {
+		glog.Errorf("Could not construct reference to %#v: %v. Will not report event: %q %q", object, err, reason, message)
+		return
+	}
+
+	t := util.Now()
+	event := &kapi.Event{
+		ObjectMeta: kapi.ObjectMeta{
+			Name:      fmt.Sprintf("%v.%x", ref.Name, t.UnixNano()),
+			Namespace: ref.Namespace,
+		},
+		InvolvedObject: *ref,
+		Reason:         reason,
+		Message:        message,
+		Source:         r.source,
+		FirstTimestamp: t,
+		LastTimestamp:  t,
+		Count:          1,
+	}
+	if _, err := r.events.Events(event.Namespace).Create(event); err != nil {
+		glog.Errorf("Unable to write event %q %q about %s/%s: %v", reason, message, ref.Namespace, ref.Name, err)
+	}
+}
+
+// Eventf creates an event about object with a formatted message.
+func (r *eventRecorder) Eventf(object runtime.Object, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, reason, fmt.Sprintf(messageFmt, args...))
+}
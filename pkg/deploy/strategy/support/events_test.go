@@ -0,0 +1,28 @@
+package support
+
+import (
+	"strings"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	ktc "github.com/GoogleCloudPlatform/kubernetes/pkg/client/testclient"
+)
+
+// TestEventRecorderCreatesEvents tests that events are created before the recorder returns
+func TestEventRecorderCreatesEvents(t *testing.T) {
+	client := &ktc.Fake{}
+	recorder := NewEventRecorder(client, kapi.EventSource{Component: "deployer"})
+
+	deployment := &kapi.ReplicationController{}
+	deployment.Name = "config-1"
+	deployment.Namespace = "ns"
+	deployment.SelfLink = "/api/v1beta3/namespaces/ns/replicationcontrollers/config-1"
+	recorder.Eventf(deployment, "hookStarted", "Running %s hook", "pre")
+
+	if len(client.Actions) != 1 {
+		t.Fatalf("expected the event to be created, got %v", client.Actions)
+	}
+	if name, _ := client.Actions[0].Value.(string); !strings.HasPrefix(name, "config-1.") {
+		t.Errorf("expected an event about config-1, got %v", client.Actions[0])
+	}
+}
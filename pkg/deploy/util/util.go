@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"hash/adler32"
+	"sort"
 	"strconv"
 
 	"github.com/golang/glog"
@@ -18,7 +19,56 @@ import (
 
 // LatestDeploymentNameForConfig returns a stable identifier for config based on its version.
 func LatestDeploymentNameForConfig(config *deployapi.DeploymentConfig) string {
-	return config.Name + "-" + strconv.Itoa(config.LatestVersion)
+	return DeploymentNameForConfigVersion(config.Name, config.LatestVersion)
+}
+
+// DeploymentNameForConfigVersion returns the name of the deployment created for
+// the config with the given name at the given version.
+func DeploymentNameForConfigVersion(name string, version int) string {
+	return name + "-" + strconv.Itoa(version)
+}
+
+// DeploymentStatusFor gets the DeploymentStatus for deployment from its annotations.
+func DeploymentStatusFor(deployment *api.ReplicationController) deployapi.DeploymentStatus {
+	return deployapi.DeploymentStatus(deployment.Annotations[deployapi.DeploymentStatusAnnotation])
+}
+
+// DeploymentVersionFor returns the config version on which deployment is
+// based, or -1 if the version can't be determined.
+func DeploymentVersionFor(deployment *api.ReplicationController) int {
+	version, err := strconv.Atoi(deployment.Annotations[deployapi.DeploymentVersionAnnotation])
+	if err != nil {
+		return -1
+	}
+	return version
+}
+
+// IsDeploymentCancelled returns true if deployment was cancelled by a user.
+func IsDeploymentCancelled(deployment *api.ReplicationController) bool {
+	return deployment.Annotations[deployapi.DeploymentCancelledAnnotation] == deployapi.DeploymentCancelledAnnotationValue
+}
+
+// ConfigDeployments returns the deployments from list which belong to the
+// config with the given name, ordered by version with the newest first.
+func ConfigDeployments(list *api.ReplicationControllerList, configName string) []api.ReplicationController {
+	deployments := []api.ReplicationController{}
+	for _, controller := range list.Items {
+		if controller.Annotations[deployapi.DeploymentConfigAnnotation] != configName {
+			continue
+		}
+		deployments = append(deployments, controller)
+	}
+	sort.Sort(byLatestVersionDesc(deployments))
+	return deployments
+}
+
+// byLatestVersionDesc sorts deployments by version, newest first.
+type byLatestVersionDesc []api.ReplicationController
+
+func (d byLatestVersionDesc) Len() int      { return len(d) }
+func (d byLatestVersionDesc) Swap(i, j int) { d[i], d[j] = d[j], d[i] }
+func (d byLatestVersionDesc) Less(i, j int) bool {
+	return DeploymentVersionFor(&d[j]) < DeploymentVersionFor(&d[i])
 }
 
 func DeployerPodNameForDeployment(deployment *api.ReplicationController) string {
@@ -141,3 +141,24 @@ func TestMakeDeploymentOk(t *testing.T) {
 		t.Fatalf("expected selector DeploymentLabel=%s, got %s", e, a)
 	}
 }
+
+func TestConfigDeployments(t *testing.T) {
+	list := &kapi.ReplicationControllerList{}
+	for _, version := range []int{2, 3, 1} {
+		deployment, _ := MakeDeployment(deploytest.OkDeploymentConfig(version), kapi.Codec)
+		list.Items = append(list.Items, *deployment)
+	}
+	list.Items = append(list.Items, kapi.ReplicationController{
+		ObjectMeta: kapi.ObjectMeta{Name: "unrelated"},
+	})
+
+	deployments := ConfigDeployments(list, "config")
+	if e, a := 3, len(deployments); e != a {
+		t.Fatalf("expected %d deployments, got %d", e, a)
+	}
+	for i, expected := range []int{3, 2, 1} {
+		if actual := DeploymentVersionFor(&deployments[i]); actual != expected {
+			t.Errorf("expected deployment %d to have version %d, got %d", i, expected, actual)
+		}
+	}
+}
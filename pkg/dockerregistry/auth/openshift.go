@@ -12,6 +12,7 @@ import (
 	registryauth "github.com/docker/distribution/registry/auth"
 	authorizationapi "github.com/openshift/origin/pkg/authorization/api"
 	"github.com/openshift/origin/pkg/dockerregistry"
+	imageapi "github.com/openshift/origin/pkg/image/api"
 	"golang.org/x/net/context"
 )
 
@@ -93,8 +94,12 @@ func (ac *AccessController) Authorized(ctx context.Context, accessRecords ...reg
 
 	authParts := strings.SplitN(req.Header.Get("Authorization"), " ", 2)
 	if len(authParts) != 2 || strings.ToLower(authParts[0]) != "basic" {
-		challenge.err = ErrTokenRequired
-		return nil, challenge
+		// Anonymous requests may only pull from public image streams
+		if err := verifyAnonymousAccess(accessRecords); err != nil {
+			challenge.err = ErrTokenRequired
+			return nil, challenge
+		}
+		return ctx, nil
 	}
 	basicToken := authParts[1]
 
@@ -124,34 +129,112 @@ func (ac *AccessController) Authorized(ctx context.Context, accessRecords ...reg
 	for _, access := range accessRecords {
 		log.Debugf("%s:%s:%s", access.Resource.Type, access.Resource.Name, access.Action)
 
-		if access.Resource.Type != "repository" {
-			continue
-		}
+		switch access.Resource.Type {
+		case "repository":
+			namespace, name, err := repositoryName(access.Resource.Name)
+			if err != nil {
+				challenge.err = err
+				return nil, challenge
+			}
+			verb, ok := repositoryVerbs[access.Action]
+			if !ok {
+				challenge.err = fmt.Errorf("Unkown action: %s", access.Action)
+				return nil, challenge
+			}
+			err = VerifyOpenShiftAccess(namespace, name, verb, bearerToken)
+			if err != nil && access.Action == "pull" && verifyPublicPull(namespace, name) == nil {
+				err = nil
+			}
+			if err != nil {
+				challenge.err = err
+				return nil, challenge
+			}
 
-		repoParts := strings.SplitN(access.Resource.Name, "/", 2)
-		if len(repoParts) != 2 {
-			challenge.err = ErrNamespaceRequired
-			return nil, challenge
+		case "registry":
+			if access.Resource.Name != "catalog" {
+				continue
+			}
+			if err := VerifyCatalogAccess(bearerToken); err != nil {
+				challenge.err = err
+				return nil, challenge
+			}
 		}
+	}
+	return WithBearerToken(ctx, bearerToken), nil
+}
 
-		verb := ""
-		switch access.Action {
-		case "push":
-			verb = "create"
-		case "pull":
-			verb = "get"
-		default:
-			challenge.err = fmt.Errorf("Unkown action: %s", access.Action)
-			return nil, challenge
-		}
+// repositoryVerbs maps the actions on a repository of the registry to the verbs
+// authorized on the imagestreams/layers resource of the image stream:
+//
+//	pull: get
+//	push: update
+//	*:    delete, which the registry requires to delete from a repository
+var repositoryVerbs = map[string]string{
+	"pull": "get",
+	"push": "update",
+	"*":    "delete",
+}
 
-		err = VerifyOpenShiftAccess(repoParts[0], repoParts[1], verb, bearerToken)
+// repositoryName returns the namespace and image stream name of a repository.
+func repositoryName(repository string) (string, string, error) {
+	repoParts := strings.SplitN(repository, "/", 2)
+	if len(repoParts) != 2 {
+		return "", "", ErrNamespaceRequired
+	}
+	return repoParts[0], repoParts[1], nil
+}
+
+// verifyAnonymousAccess returns an error unless every access is a pull from an image
+// stream which allows public pulls.
+func verifyAnonymousAccess(accessRecords []registryauth.Access) error {
+	if len(accessRecords) == 0 {
+		return ErrTokenRequired
+	}
+	for _, access := range accessRecords {
+		if access.Resource.Type != "repository" || access.Action != "pull" {
+			return ErrTokenRequired
+		}
+		namespace, name, err := repositoryName(access.Resource.Name)
 		if err != nil {
-			challenge.err = err
-			return nil, challenge
+			return err
+		}
+		if err := verifyPublicPull(namespace, name); err != nil {
+			return err
 		}
 	}
-	return WithBearerToken(ctx, bearerToken), nil
+	return nil
+}
+
+// verifyPublicPull returns an error unless the image stream, or the project when the
+// image stream does not set the PublicPullAnnotation, allows anyone to pull its images.
+func verifyPublicPull(namespace, name string) error {
+	osClient, err := dockerregistry.NewRegistryOpenShiftClient()
+	if err != nil {
+		return err
+	}
+	stream, err := osClient.ImageStreams(namespace).Get(name)
+	if err == nil {
+		if public, ok := stream.Annotations[imageapi.PublicPullAnnotation]; ok {
+			if public != "true" {
+				return ErrOpenShiftAccessDenied
+			}
+			return nil
+		}
+	}
+
+	kClient, err := dockerregistry.NewRegistryKubernetesClient()
+	if err != nil {
+		return err
+	}
+	project, err := kClient.Namespaces().Get(namespace)
+	if err != nil {
+		log.Errorf("Get project failed with error: %s", err)
+		return ErrOpenShiftAccessDenied
+	}
+	if project.Annotations[imageapi.PublicPullAnnotation] != "true" {
+		return ErrOpenShiftAccessDenied
+	}
+	return nil
 }
 
 func VerifyOpenShiftUser(user, bearerToken string) error {
@@ -172,16 +255,47 @@ func VerifyOpenShiftUser(user, bearerToken string) error {
 }
 
 func VerifyOpenShiftAccess(namespace, imageRepo, verb, bearerToken string) error {
+	sar := authorizationapi.SubjectAccessReview{
+		Verb:         verb,
+		Resource:     "imagestreams/layers",
+		ResourceName: imageRepo,
+	}
+	return verifyAccess(namespace, sar, bearerToken)
+}
+
+// VerifyCatalogAccess returns an error unless bearerToken grants access to list the
+// image streams of all namespaces, which is required to list the repositories of the
+// registry.
+func VerifyCatalogAccess(bearerToken string) error {
+	sar := authorizationapi.SubjectAccessReview{
+		Verb:     "list",
+		Resource: "imagestreams",
+	}
+	return verifyAccess("", sar, bearerToken)
+}
+
+// VerifyImagePruneAccess returns an error unless bearerToken grants access to
+// delete images, which is required to prune layers from the registry.
+func VerifyImagePruneAccess(bearerToken string) error {
+	sar := authorizationapi.SubjectAccessReview{
+		Verb:     "delete",
+		Resource: "images",
+	}
+	return verifyAccess("", sar, bearerToken)
+}
+
+// verifyAccess returns an error unless the subject access review sar is allowed in
+// namespace, or in all namespaces if namespace is empty, for bearerToken.
+func verifyAccess(namespace string, sar authorizationapi.SubjectAccessReview, bearerToken string) error {
 	client, err := dockerregistry.NewUserOpenShiftClient(bearerToken)
 	if err != nil {
 		return err
 	}
-	sar := authorizationapi.SubjectAccessReview{
-		Verb:         verb,
-		Resource:     "imageStreams",
-		ResourceName: imageRepo,
+	reviews := client.RootSubjectAccessReviews()
+	if len(namespace) > 0 {
+		reviews = client.SubjectAccessReviews(namespace)
 	}
-	response, err := client.SubjectAccessReviews(namespace).Create(&sar)
+	response, err := reviews.Create(&sar)
 	if err != nil {
 		log.Errorf("OpenShift client error: %s", err)
 		return ErrOpenShiftAccessDenied
@@ -1,10 +1,12 @@
 package auth
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/docker/distribution/registry/auth"
@@ -52,6 +54,55 @@ func TestVerifyOpenShiftAccess(t *testing.T) {
 	}
 }
 
+// TestVerifyImagePruneAccess tests that pruning layers requires access to delete images
+// in all namespaces.
+func TestVerifyImagePruneAccess(t *testing.T) {
+	var path string
+	var review map[string]interface{}
+	server := simulateAccessReview(t, &path, &review)
+	defer server.Close()
+
+	if err := VerifyImagePruneAccess("magic bearer token"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(path, "/namespaces/") || review["verb"] != "delete" || review["resource"] != "images" {
+		t.Errorf("expected a review of deleting images in all namespaces, got %s %v", path, review)
+	}
+}
+
+// TestVerifyCatalogAccess tests that listing the repositories of the registry requires
+// access to list the image streams of all namespaces.
+func TestVerifyCatalogAccess(t *testing.T) {
+	var path string
+	var review map[string]interface{}
+	server := simulateAccessReview(t, &path, &review)
+	defer server.Close()
+
+	if err := VerifyCatalogAccess("magic bearer token"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(path, "/namespaces/") || review["verb"] != "list" || review["resource"] != "imagestreams" {
+		t.Errorf("expected a review of listing image streams in all namespaces, got %s %v", path, review)
+	}
+}
+
+// simulateAccessReview simulates an OpenShift master allowing every subject access review,
+// and records the path and the content of the last review.
+func simulateAccessReview(t *testing.T, path *string, review *map[string]interface{}) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*path = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(review); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintln(w, `{"allowed": true, "reason": "authorized!", "kind": "SubjectAccessReviewResponse", "apiVersion": "v1beta1"}`)
+	}))
+
+	os.Setenv("OPENSHIFT_MASTER", server.URL)
+	os.Setenv("OPENSHIFT_INSECURE", "true")
+	return server
+}
+
 // TestAccessController tests complete integration of the v2 registry auth package.
 func TestAccessController(t *testing.T) {
 	options := map[string]interface{}{
@@ -208,3 +259,87 @@ func simulateOpenShiftMaster(code int, body string) *httptest.Server {
 	os.Setenv("OPENSHIFT_INSECURE", "true")
 	return server
 }
+
+// TestAnonymousPull tests that requests without a token may only pull from image streams
+// which allow public pulls.
+func TestAnonymousPull(t *testing.T) {
+	accessController, err := newAccessController(map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		streamAnnotations  string
+		projectAnnotations string
+		action             string
+		expectedError      error
+	}{
+		"private": {
+			action:        "pull",
+			expectedError: ErrTokenRequired,
+		},
+		"public image stream": {
+			streamAnnotations: `{"openshift.io/image.publicPull": "true"}`,
+			action:            "pull",
+		},
+		"public project": {
+			projectAnnotations: `{"openshift.io/image.publicPull": "true"}`,
+			action:             "pull",
+		},
+		"private image stream in a public project": {
+			streamAnnotations:  `{"openshift.io/image.publicPull": "false"}`,
+			projectAnnotations: `{"openshift.io/image.publicPull": "true"}`,
+			action:             "pull",
+			expectedError:      ErrTokenRequired,
+		},
+		"push to a public image stream": {
+			streamAnnotations: `{"openshift.io/image.publicPull": "true"}`,
+			action:            "push",
+			expectedError:     ErrTokenRequired,
+		},
+	}
+	for name, test := range tests {
+		streamAnnotations, projectAnnotations := test.streamAnnotations, test.projectAnnotations
+		if len(streamAnnotations) == 0 {
+			streamAnnotations = "{}"
+		}
+		if len(projectAnnotations) == 0 {
+			projectAnnotations = "{}"
+		}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch {
+			case strings.Contains(r.URL.Path, "/imageStreams/bar"):
+				fmt.Fprintf(w, `{"kind": "ImageStream", "apiVersion": "v1beta1", "metadata": {"name": "bar", "namespace": "foo", "annotations": %s}}`, streamAnnotations)
+			case strings.HasSuffix(r.URL.Path, "/namespaces/foo"):
+				fmt.Fprintf(w, `{"kind": "Namespace", "apiVersion": "v1beta3", "metadata": {"name": "foo", "annotations": %s}}`, projectAnnotations)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		os.Setenv("OPENSHIFT_MASTER", server.URL)
+		os.Setenv("OPENSHIFT_INSECURE", "true")
+
+		req, err := http.NewRequest("GET", server.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx := context.WithValue(nil, "http.request", req)
+		access := auth.Access{
+			Resource: auth.Resource{Type: "repository", Name: "foo/bar"},
+			Action:   test.action,
+		}
+		_, err = accessController.Authorized(ctx, access)
+		server.Close()
+
+		if test.expectedError == nil {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", name, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != test.expectedError.Error() {
+			t.Errorf("%s: expected error %v, got %v", name, test.expectedError, err)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
@@ -11,34 +12,55 @@ import (
 	"path"
 	"strings"
 
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/manifest"
 	"github.com/fsouza/go-dockerclient"
 	"github.com/golang/glog"
 
 	imageapi "github.com/openshift/origin/pkg/image/api"
 )
 
+// schema1SignedManifestMediaType is the media type of a signed schema 1 manifest.
+const schema1SignedManifestMediaType = "application/vnd.docker.distribution.manifest.v1+prettyjws"
+
 // Client includes methods for accessing a Docker registry by name.
 type Client interface {
-	// Connect to a Docker registry by name. Pass "" for the Docker Hub
-	Connect(registry string, allowInsecure bool) (Connection, error)
+	// Connect to a Docker registry by name. Pass "" for the Docker Hub. The connection
+	// authenticates with credentials when the registry asks for them; nil credentials
+	// access the registry anonymously.
+	Connect(registry string, allowInsecure bool, credentials Credentials) (Connection, error)
 }
 
-// Connection allows you to retrieve data from a Docker V1 registry.
+// Connection allows you to retrieve data from a Docker registry. The V2 API is used
+// when the registry supports it, and the V1 API otherwise.
 type Connection interface {
 	// ImageTags will return a map of the tags for the image by namespace (if not
-	// specified, will be "library") and name.
+	// specified, will be "library") and name. The map values are image IDs, which
+	// are manifest digests for a V2 registry.
 	ImageTags(namespace, name string) (map[string]string, error)
 	// ImageByID will return the requested image by namespace (if not specified,
 	// will be "library"), name, and ID.
-	ImageByID(namespace, name, id string) (*docker.Image, error)
+	ImageByID(namespace, name, id string) (*Image, error)
 	// ImageByTag will return the requested image by namespace (if not specified,
 	// will be "library"), name, and tag (if not specified, "latest").
-	ImageByTag(namespace, name, tag string) (*docker.Image, error)
+	ImageByTag(namespace, name, tag string) (*Image, error)
+	// ImageLayer will return the content of the layer with digest dgst in the
+	// repository by namespace (if not specified, will be "library") and name. Only
+	// registries with the V2 API address layers by digest. The caller must close
+	// the content.
+	ImageLayer(namespace, name, dgst string) (io.ReadCloser, error)
+}
+
+// Image is a Docker image retrieved from a registry.
+type Image struct {
+	docker.Image
+	// Manifest is the raw V2 schema 1 manifest of the image, or empty if the image
+	// was retrieved with the V1 API. The ID of an image with a manifest is the
+	// digest of the manifest.
+	Manifest []byte
 }
 
-// NewClient returns a client object which allows public access to
-// a Docker registry.
-// TODO: accept a docker auth config
+// NewClient returns a client object which allows access to a Docker registry.
 func NewClient() Client {
 	return &client{
 		connections: make(map[string]*connection),
@@ -52,17 +74,21 @@ type client struct {
 
 // Connect accepts the name of a registry in the common form Docker provides and will
 // create a connection to the registry. Callers may provide a host, a host:port, or
-// a fully qualified URL. When not providing a URL, the default scheme will be "https"
-func (c *client) Connect(name string, allowInsecure bool) (Connection, error) {
+// a fully qualified URL. When not providing a URL, the default scheme will be "https".
+// Only anonymous connections are reused.
+func (c *client) Connect(name string, allowInsecure bool, credentials Credentials) (Connection, error) {
 	target, err := normalizeRegistryName(name)
 	if err != nil {
 		return nil, err
 	}
+	if credentials != nil && credentials != NoCredentials {
+		return newConnection(*target, allowInsecure, credentials), nil
+	}
 	prefix := target.String()
 	if conn, ok := c.connections[prefix]; ok && conn.allowInsecure == allowInsecure {
 		return conn, nil
 	}
-	conn := newConnection(*target, allowInsecure)
+	conn := newConnection(*target, allowInsecure, NoCredentials)
 	c.connections[prefix] = conn
 	return conn, nil
 }
@@ -120,9 +146,13 @@ type connection struct {
 	cached map[string]*repository
 
 	allowInsecure bool
+	credentials   Credentials
+
+	// isV2 is set once the registry has been checked for the V2 API
+	isV2 *bool
 }
 
-func newConnection(url url.URL, allowInsecure bool) *connection {
+func newConnection(url url.URL, allowInsecure bool, credentials Credentials) *connection {
 	client := http.DefaultClient
 	if allowInsecure {
 		tr := &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
@@ -134,6 +164,7 @@ func newConnection(url url.URL, allowInsecure bool) *connection {
 		cached: make(map[string]*repository),
 
 		allowInsecure: allowInsecure,
+		credentials:   credentials,
 	}
 }
 
@@ -152,6 +183,12 @@ func (c *connection) ImageTags(namespace, name string) (map[string]string, error
 		return nil, fmt.Errorf("image name must be specified")
 	}
 
+	if v2, err := c.checkV2(); err != nil {
+		return nil, err
+	} else if v2 {
+		return c.getTagsV2(fmt.Sprintf("%s/%s", namespace, name))
+	}
+
 	repo, err := c.getCachedRepository(fmt.Sprintf("%s/%s", namespace, name))
 	if err != nil {
 		return nil, err
@@ -161,7 +198,7 @@ func (c *connection) ImageTags(namespace, name string) (map[string]string, error
 }
 
 // ImageByID returns the specified image within the named Docker image repository
-func (c *connection) ImageByID(namespace, name, imageID string) (*docker.Image, error) {
+func (c *connection) ImageByID(namespace, name, imageID string) (*Image, error) {
 	if len(namespace) == 0 {
 		namespace = "library"
 	}
@@ -169,6 +206,13 @@ func (c *connection) ImageByID(namespace, name, imageID string) (*docker.Image,
 		return nil, fmt.Errorf("image name must be specified")
 	}
 
+	if v2, err := c.checkV2(); err != nil {
+		return nil, err
+	} else if v2 {
+		name := fmt.Sprintf("%s/%s", namespace, name)
+		return c.getManifestV2(name, imageID, NewImageNotFoundError(name, imageID, ""))
+	}
+
 	repo, err := c.getCachedRepository(fmt.Sprintf("%s/%s", namespace, name))
 	if err != nil {
 		return nil, err
@@ -178,7 +222,7 @@ func (c *connection) ImageByID(namespace, name, imageID string) (*docker.Image,
 }
 
 // ImageByTag returns the specified image within the named Docker image repository
-func (c *connection) ImageByTag(namespace, name, tag string) (*docker.Image, error) {
+func (c *connection) ImageByTag(namespace, name, tag string) (*Image, error) {
 	if len(namespace) == 0 {
 		namespace = "library"
 	}
@@ -190,6 +234,13 @@ func (c *connection) ImageByTag(namespace, name, tag string) (*docker.Image, err
 		searchTag = imageapi.DefaultImageTag
 	}
 
+	if v2, err := c.checkV2(); err != nil {
+		return nil, err
+	} else if v2 {
+		name := fmt.Sprintf("%s/%s", namespace, name)
+		return c.getManifestV2(name, searchTag, errTagNotFound{len(tag) == 0, searchTag, name})
+	}
+
 	repo, err := c.getCachedRepository(fmt.Sprintf("%s/%s", namespace, name))
 	if err != nil {
 		return nil, err
@@ -203,6 +254,47 @@ func (c *connection) ImageByTag(namespace, name, tag string) (*docker.Image, err
 	return c.getImage(repo, imageID, tag)
 }
 
+// ImageLayer returns the content of the layer with digest dgst within the named
+// Docker image repository
+func (c *connection) ImageLayer(namespace, name, dgst string) (io.ReadCloser, error) {
+	if len(namespace) == 0 {
+		namespace = "library"
+	}
+	if len(name) == 0 {
+		return nil, fmt.Errorf("image name must be specified")
+	}
+
+	if v2, err := c.checkV2(); err != nil {
+		return nil, err
+	} else if !v2 {
+		return nil, fmt.Errorf("the registry %q does not support retrieving layers by digest", c.url.String())
+	}
+
+	name = fmt.Sprintf("%s/%s", namespace, name)
+	endpoint := c.url
+	endpoint.Path = path.Join(endpoint.Path, fmt.Sprintf("/v2/%s/blobs/%s", name, dgst))
+	req, err := http.NewRequest("GET", endpoint.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, convertConnectionError(c.url.String(), fmt.Errorf("error getting layer %s of %s: %v", dgst, name, err))
+	}
+	switch code := resp.StatusCode; {
+	case code == http.StatusUnauthorized, code == http.StatusForbidden:
+		resp.Body.Close()
+		return nil, errUnauthorized{c.url.String()}
+	case code == http.StatusNotFound:
+		resp.Body.Close()
+		return nil, errLayerNotFound{dgst, name}
+	case code >= 300 || code < 200:
+		resp.Body.Close()
+		return nil, fmt.Errorf("error retrieving layer %s of %s: server returned %d", dgst, name, code)
+	}
+	return resp.Body, nil
+}
+
 func (c *connection) getCachedRepository(name string) (*repository, error) {
 	if cached, ok := c.cached[name]; ok {
 		return cached, nil
@@ -224,7 +316,10 @@ func (c *connection) getRepository(name string) (*repository, error) {
 		return nil, fmt.Errorf("error creating request: %v", err)
 	}
 	req.Header.Add("X-Docker-Token", "true")
-	resp, err := c.client.Do(req)
+	if username, password := c.credentials.Basic(&c.url); len(username) > 0 {
+		req.SetBasicAuth(username, password)
+	}
+	resp, err := c.do(req)
 	if err != nil {
 		// if we tried https and were rejected, try http
 		if c.url.Scheme == "https" && c.allowInsecure {
@@ -240,6 +335,8 @@ func (c *connection) getRepository(name string) (*repository, error) {
 	c.url.Host = resp.Request.URL.Host
 
 	switch code := resp.StatusCode; {
+	case code == http.StatusUnauthorized, code == http.StatusForbidden:
+		return nil, errUnauthorized{c.url.String()}
 	case code == http.StatusNotFound:
 		return nil, errRepositoryNotFound{name}
 	case code >= 300 || resp.StatusCode < 200:
@@ -261,11 +358,13 @@ func (c *connection) getTags(repo *repository) (map[string]string, error) {
 		return nil, fmt.Errorf("error creating request: %v", err)
 	}
 	req.Header.Add("Authorization", "Token "+repo.token)
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, convertConnectionError(c.url.String(), fmt.Errorf("error getting image tags for %s: %v", repo.name, err))
 	}
 	switch code := resp.StatusCode; {
+	case code == http.StatusUnauthorized, code == http.StatusForbidden:
+		return nil, errUnauthorized{c.url.String()}
 	case code == http.StatusNotFound:
 		return nil, errRepositoryNotFound{repo.name}
 	case code >= 300 || resp.StatusCode < 200:
@@ -286,11 +385,13 @@ func (c *connection) getTag(repo *repository, tag, userTag string) (string, erro
 		return "", fmt.Errorf("error creating request: %v", err)
 	}
 	req.Header.Add("Authorization", "Token "+repo.token)
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return "", convertConnectionError(c.url.String(), fmt.Errorf("error getting image id for %s:%s: %v", repo.name, tag, err))
 	}
 	switch code := resp.StatusCode; {
+	case code == http.StatusUnauthorized, code == http.StatusForbidden:
+		return "", errUnauthorized{c.url.String()}
 	case code == http.StatusNotFound:
 		return "", errTagNotFound{len(userTag) == 0, tag, repo.name}
 	case code >= 300 || resp.StatusCode < 200:
@@ -303,7 +404,7 @@ func (c *connection) getTag(repo *repository, tag, userTag string) (string, erro
 	return imageID, nil
 }
 
-func (c *connection) getImage(repo *repository, image, userTag string) (*docker.Image, error) {
+func (c *connection) getImage(repo *repository, image, userTag string) (*Image, error) {
 	endpoint := repo.endpoint
 	endpoint.Path = path.Join(endpoint.Path, fmt.Sprintf("/v1/images/%s/json", image))
 	req, err := http.NewRequest("GET", endpoint.String(), nil)
@@ -311,11 +412,13 @@ func (c *connection) getImage(repo *repository, image, userTag string) (*docker.
 		return nil, fmt.Errorf("error creating request: %v", err)
 	}
 	req.Header.Add("Authorization", "Token "+repo.token)
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, convertConnectionError(c.url.String(), fmt.Errorf("error getting json for image %q: %v", image, err))
 	}
 	switch code := resp.StatusCode; {
+	case code == http.StatusUnauthorized, code == http.StatusForbidden:
+		return nil, errUnauthorized{c.url.String()}
 	case code == http.StatusNotFound:
 		return nil, NewImageNotFoundError(repo.name, image, userTag)
 	case code >= 300 || resp.StatusCode < 200:
@@ -326,7 +429,285 @@ func (c *connection) getImage(repo *repository, image, userTag string) (*docker.
 	if err != nil {
 		return nil, fmt.Errorf("can't read image body from %s: %v", req.URL, err)
 	}
-	return unmarshalDockerImage(body)
+	dockerImage, err := unmarshalDockerImage(body)
+	if err != nil {
+		return nil, err
+	}
+	return &Image{Image: *dockerImage}, nil
+}
+
+// checkV2 returns true if the registry supports the V2 API. The result is remembered
+// for the life of the connection.
+func (c *connection) checkV2() (bool, error) {
+	if c.isV2 != nil {
+		return *c.isV2, nil
+	}
+	base := c.url
+	base.Path = path.Join(base.Path, "/v2") + "/"
+	req, err := http.NewRequest("GET", base.String(), nil)
+	if err != nil {
+		return false, fmt.Errorf("error creating request: %v", err)
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		// if we tried https and were rejected, try http
+		if c.url.Scheme == "https" && c.allowInsecure {
+			glog.V(4).Infof("Failed to get https, trying http: %v", err)
+			c.url.Scheme = "http"
+			return c.checkV2()
+		}
+		return false, convertConnectionError(c.url.String(), fmt.Errorf("error checking the registry API version: %v", err))
+	}
+	defer resp.Body.Close()
+
+	// the V2 API answers with its version, even when authentication is required
+	v2 := false
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusUnauthorized:
+		v2 = resp.Header.Get("Docker-Distribution-API-Version") == "registry/2.0"
+	}
+	glog.V(4).Infof("Registry %s supports the V2 API: %t", c.url.String(), v2)
+	c.isV2 = &v2
+	return v2, nil
+}
+
+// getTagsV2 returns the tags of the named repository, mapped to the digests of their
+// manifests.
+func (c *connection) getTagsV2(name string) (map[string]string, error) {
+	endpoint := c.url
+	endpoint.Path = path.Join(endpoint.Path, fmt.Sprintf("/v2/%s/tags/list", name))
+	req, err := http.NewRequest("GET", endpoint.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, convertConnectionError(c.url.String(), fmt.Errorf("error getting image tags for %s: %v", name, err))
+	}
+	defer resp.Body.Close()
+	switch code := resp.StatusCode; {
+	case code == http.StatusUnauthorized, code == http.StatusForbidden:
+		return nil, errUnauthorized{c.url.String()}
+	case code == http.StatusNotFound:
+		return nil, errRepositoryNotFound{name}
+	case code >= 300 || code < 200:
+		return nil, fmt.Errorf("error retrieving tags: server returned %d", code)
+	}
+	list := struct {
+		Tags []string `json:"tags"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("error decoding image %s tags: %v", name, err)
+	}
+
+	tags := make(map[string]string)
+	for _, tag := range list.Tags {
+		image, err := c.getManifestV2(name, tag, errTagNotFound{false, tag, name})
+		switch {
+		case IsTagNotFound(err):
+			// the tag was removed since it was listed
+			continue
+		case err != nil:
+			return nil, err
+		}
+		tags[tag] = image.ID
+	}
+	return tags, nil
+}
+
+// getManifestV2 returns the image described by the schema 1 manifest of the named
+// repository with reference, a tag or a digest. notFound is returned if there is no
+// such manifest.
+func (c *connection) getManifestV2(name, reference string, notFound error) (*Image, error) {
+	endpoint := c.url
+	endpoint.Path = path.Join(endpoint.Path, fmt.Sprintf("/v2/%s/manifests/%s", name, reference))
+	req, err := http.NewRequest("GET", endpoint.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Add("Accept", schema1SignedManifestMediaType)
+	req.Header.Add("Accept", manifest.ManifestMediaType)
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, convertConnectionError(c.url.String(), fmt.Errorf("error getting manifest %s of %s: %v", reference, name, err))
+	}
+	defer resp.Body.Close()
+	switch code := resp.StatusCode; {
+	case code == http.StatusUnauthorized, code == http.StatusForbidden:
+		return nil, errUnauthorized{c.url.String()}
+	case code == http.StatusNotFound:
+		return nil, notFound
+	case code >= 300 || code < 200:
+		return nil, fmt.Errorf("error retrieving manifest %s of %s: server returned %d", reference, name, code)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("can't read manifest body from %s: %v", req.URL, err)
+	}
+
+	signed := &manifest.SignedManifest{}
+	if err := json.Unmarshal(body, signed); err != nil {
+		return nil, fmt.Errorf("error decoding manifest %s of %s: %v", reference, name, err)
+	}
+	if signed.SchemaVersion != 1 {
+		return nil, fmt.Errorf("manifest %s of %s has unsupported schema version %d", reference, name, signed.SchemaVersion)
+	}
+	if len(signed.History) == 0 {
+		return nil, fmt.Errorf("manifest %s of %s has no image history", reference, name)
+	}
+	// the configuration of the image is the V1 compatibility data of its top layer
+	image, err := unmarshalDockerImage([]byte(signed.History[0].V1Compatibility))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding the configuration in manifest %s of %s: %v", reference, name, err)
+	}
+
+	id := resp.Header.Get("Docker-Content-Digest")
+	if len(id) == 0 {
+		// the digest of a signed manifest covers its payload without signatures
+		payload, err := signed.Payload()
+		if err != nil {
+			payload = body
+		}
+		dgst, err := digest.FromBytes(payload)
+		if err != nil {
+			return nil, fmt.Errorf("error computing the digest of manifest %s of %s: %v", reference, name, err)
+		}
+		id = dgst.String()
+	}
+	image.ID = id
+	return &Image{Image: *image, Manifest: body}, nil
+}
+
+// do sends req and answers an authentication challenge from the registry with the
+// credentials of the connection, retrying req with basic auth or with a bearer token
+// obtained from the token server named in the challenge. The response to the first
+// attempt is returned if the challenge cannot be answered.
+func (c *connection) do(req *http.Request) (*http.Response, error) {
+	resp, err := c.client.Do(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	scheme, params := parseChallenge(resp.Header.Get("WWW-Authenticate"))
+	username, password := c.credentials.Basic(&c.url)
+	retry, err := http.NewRequest(req.Method, req.URL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+	for k, v := range req.Header {
+		retry.Header[k] = v
+	}
+
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if len(username) == 0 {
+			return resp, nil
+		}
+		retry.SetBasicAuth(username, password)
+	case "bearer":
+		token, err := c.getBearerToken(params, username, password)
+		if IsUnauthorized(err) {
+			return resp, nil
+		}
+		if err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+		retry.Header.Set("Authorization", "Bearer "+token)
+	default:
+		return resp, nil
+	}
+	resp.Body.Close()
+	glog.V(4).Infof("Retrying %s with %s authentication", req.URL, scheme)
+	return c.client.Do(retry)
+}
+
+// getBearerToken requests a token for the service and scope of a bearer challenge
+// from the realm of the challenge, authenticating as username if it is set.
+func (c *connection) getBearerToken(params map[string]string, username, password string) (string, error) {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || len(realm.Host) == 0 {
+		return "", fmt.Errorf("the registry %q asked for a token from an invalid realm %q", c.url.String(), params["realm"])
+	}
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if value, ok := params[key]; ok {
+			query.Set(key, value)
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequest("GET", realm.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %v", err)
+	}
+	if len(username) > 0 {
+		req.SetBasicAuth(username, password)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", convertConnectionError(realm.String(), fmt.Errorf("error getting a token from %s: %v", realm.Host, err))
+	}
+	defer resp.Body.Close()
+	switch code := resp.StatusCode; {
+	case code == http.StatusUnauthorized, code == http.StatusForbidden:
+		return "", errUnauthorized{c.url.String()}
+	case code >= 300 || code < 200:
+		return "", fmt.Errorf("error getting a token from %s: server returned %d", realm.Host, code)
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("error decoding token from %s: %v", realm.Host, err)
+	}
+	if len(token.Token) == 0 {
+		token.Token = token.AccessToken
+	}
+	if len(token.Token) == 0 {
+		return "", fmt.Errorf("no token was returned from %s", realm.Host)
+	}
+	return token.Token, nil
+}
+
+// parseChallenge splits a WWW-Authenticate header into its scheme and parameters, for
+// instance: Bearer realm="https://auth.docker.io/token",service="registry.docker.io"
+func parseChallenge(header string) (string, map[string]string) {
+	params := make(map[string]string)
+	parts := strings.SplitN(strings.TrimSpace(header), " ", 2)
+	if len(parts) == 1 {
+		return parts[0], params
+	}
+	rest := parts[1]
+	for {
+		rest = strings.TrimLeft(rest, " ,")
+		i := strings.Index(rest, "=")
+		if i == -1 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(rest[:i]))
+		rest = rest[i+1:]
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end == -1 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			end := strings.Index(rest, ",")
+			if end == -1 {
+				value, rest = rest, ""
+			} else {
+				value, rest = rest[:end], rest[end+1:]
+			}
+		}
+		params[key] = value
+	}
+	return parts[0], params
 }
 
 type errTagNotFound struct {
@@ -367,6 +748,15 @@ func (e errImageNotFound) Error() string {
 	return fmt.Sprintf("the image %q in repository %q with tag %q was not found and may have been deleted", e.image, e.repository, e.tag)
 }
 
+type errLayerNotFound struct {
+	layer      string
+	repository string
+}
+
+func (e errLayerNotFound) Error() string {
+	return fmt.Sprintf("the layer %q in repository %q was not found", e.layer, e.repository)
+}
+
 type errRegistryNotFound struct {
 	registry string
 }
@@ -375,6 +765,19 @@ func (e errRegistryNotFound) Error() string {
 	return fmt.Sprintf("the registry %q could not be reached", e.registry)
 }
 
+type errUnauthorized struct {
+	registry string
+}
+
+// NewUnauthorizedError returns an error for a registry which refused access.
+func NewUnauthorizedError(registry string) error {
+	return errUnauthorized{registry}
+}
+
+func (e errUnauthorized) Error() string {
+	return fmt.Sprintf("the registry %q requires credentials which were not provided or were rejected", e.registry)
+}
+
 func IsRegistryNotFound(err error) bool {
 	_, ok := err.(errRegistryNotFound)
 	return ok
@@ -395,8 +798,20 @@ func IsTagNotFound(err error) bool {
 	return ok
 }
 
+func IsLayerNotFound(err error) bool {
+	_, ok := err.(errLayerNotFound)
+	return ok
+}
+
+// IsUnauthorized returns true if the registry refused access with the credentials
+// provided, or without credentials.
+func IsUnauthorized(err error) bool {
+	_, ok := err.(errUnauthorized)
+	return ok
+}
+
 func IsNotFound(err error) bool {
-	return IsRegistryNotFound(err) || IsRepositoryNotFound(err) || IsImageNotFound(err) || IsTagNotFound(err)
+	return IsRegistryNotFound(err) || IsRepositoryNotFound(err) || IsImageNotFound(err) || IsTagNotFound(err) || IsLayerNotFound(err)
 }
 
 func unmarshalDockerImage(body []byte) (*docker.Image, error) {
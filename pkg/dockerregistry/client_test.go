@@ -1,22 +1,26 @@
 package dockerregistry
 
 import (
+	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"reflect"
 	"strings"
 	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 )
 
 func TestConnect(t *testing.T) {
 	c := NewClient()
-	conn, err := c.Connect("docker.io", false)
+	conn, err := c.Connect("docker.io", false, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 	for _, s := range []string{"index.docker.io", "https://docker.io", "https://index.docker.io"} {
-		otherConn, err := c.Connect(s, false)
+		otherConn, err := c.Connect(s, false, nil)
 		if err != nil {
 			t.Errorf("%s: can't connect: ", s, err)
 			continue
@@ -26,18 +30,18 @@ func TestConnect(t *testing.T) {
 		}
 	}
 
-	otherConn, err := c.Connect("index.docker.io:443", false)
+	otherConn, err := c.Connect("index.docker.io:443", false, nil)
 	if err != nil || reflect.DeepEqual(otherConn, conn) {
 		t.Errorf("should not have reused index.docker.io:443: %v", err)
 	}
 
-	if _, err := c.Connect("http://ba%3/", false); err == nil {
+	if _, err := c.Connect("http://ba%3/", false, nil); err == nil {
 		t.Error("Unexpected non-error")
 	}
 }
 
 func TestHTTPFallback(t *testing.T) {
-	called := make(chan struct{}, 2)
+	called := make(chan struct{}, 3)
 	var uri *url.URL
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		called <- struct{}{}
@@ -49,19 +53,21 @@ func TestHTTPFallback(t *testing.T) {
 		w.WriteHeader(http.StatusOK)
 	}))
 	uri, _ = url.Parse(server.URL)
-	conn, err := NewClient().Connect(uri.Host, true)
+	conn, err := NewClient().Connect(uri.Host, true, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 	if _, err := conn.ImageTags("foo", "bar"); !IsRepositoryNotFound(err) {
 		t.Error(err)
 	}
+	// the API version check, the repository and its tags
+	<-called
 	<-called
 	<-called
 }
 
 func TestInsecureHTTPS(t *testing.T) {
-	called := make(chan struct{}, 2)
+	called := make(chan struct{}, 3)
 	var uri *url.URL
 	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		called <- struct{}{}
@@ -73,19 +79,21 @@ func TestInsecureHTTPS(t *testing.T) {
 		w.WriteHeader(http.StatusOK)
 	}))
 	uri, _ = url.Parse(server.URL)
-	conn, err := NewClient().Connect(uri.Host, true)
+	conn, err := NewClient().Connect(uri.Host, true, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 	if _, err := conn.ImageTags("foo", "bar"); !IsRepositoryNotFound(err) {
 		t.Error(err)
 	}
+	// the API version check, the repository and its tags
+	<-called
 	<-called
 	<-called
 }
 
 func TestRegistryNotFound(t *testing.T) {
-	conn, err := NewClient().Connect("localhost:65000", false)
+	conn, err := NewClient().Connect("localhost:65000", false, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -94,8 +102,186 @@ func TestRegistryNotFound(t *testing.T) {
 	}
 }
 
+func testCredentials(registry string) Credentials {
+	return NewCredentialsForSecrets([]kapi.Secret{{
+		Data: map[string][]byte{
+			".dockercfg": []byte(fmt.Sprintf(`{%q:{"auth":"dXNlcjpwYXNz","email":"user@example.com"}}`, registry)),
+		},
+	}})
+}
+
+func TestBasicAuth(t *testing.T) {
+	var uri *url.URL
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "user" || password != "pass" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/tags") {
+			w.Write([]byte(`{"latest":"id"}`))
+			return
+		}
+		w.Header().Set("X-Docker-Endpoints", uri.Host)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	uri, _ = url.Parse(server.URL)
+
+	conn, err := NewClient().Connect(server.URL, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.ImageTags("foo", "bar"); !IsUnauthorized(err) {
+		t.Errorf("expected an unauthorized error, got %v", err)
+	}
+
+	conn, err = NewClient().Connect(server.URL, false, testCredentials(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tags, err := conn.ImageTags("foo", "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tags["latest"] != "id" {
+		t.Errorf("unexpected tags: %v", tags)
+	}
+}
+
+func TestBearerAuth(t *testing.T) {
+	var uri *url.URL
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if username, password, ok := r.BasicAuth(); !ok || username != "user" || password != "pass" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if r.URL.Query().Get("service") != "registry" || r.URL.Query().Get("scope") != "repository:foo/bar:pull" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`{"token":"secret"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="http://%s/token",service="registry",scope="repository:foo/bar:pull"`, uri.Host))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/tags") {
+			w.Write([]byte(`{"latest":"id"}`))
+			return
+		}
+		w.Header().Set("X-Docker-Endpoints", uri.Host)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	uri, _ = url.Parse(server.URL)
+
+	conn, err := NewClient().Connect(server.URL, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.ImageTags("foo", "bar"); !IsUnauthorized(err) {
+		t.Errorf("expected an unauthorized error, got %v", err)
+	}
+
+	conn, err = NewClient().Connect(server.URL, false, testCredentials(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tags, err := conn.ImageTags("foo", "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tags["latest"] != "id" {
+		t.Errorf("unexpected tags: %v", tags)
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:foo/bar:pull,push"`)
+	if scheme != "Bearer" {
+		t.Errorf("unexpected scheme %q", scheme)
+	}
+	expected := map[string]string{
+		"realm":   "https://auth.docker.io/token",
+		"service": "registry.docker.io",
+		"scope":   "repository:foo/bar:pull,push",
+	}
+	if !reflect.DeepEqual(expected, params) {
+		t.Errorf("expected %v, got %v", expected, params)
+	}
+}
+
+func TestV2(t *testing.T) {
+	manifest := `{"schemaVersion":1,"name":"foo/bar","tag":"latest","architecture":"amd64","fsLayers":[{"blobSum":"sha256:a3ed95caeb02ffe68cdd9fd84406680ae93d633cb16422d00e8a7c22955b46d4"}],"history":[{"v1Compatibility":"{\"id\":\"layer\",\"architecture\":\"amd64\",\"config\":{\"Entrypoint\":[\"/bin/sh\"]}}"}]}`
+	dgst := "sha256:4bd26aef1ce78b4f05ede83496276f11e3343441574ca1ce89dffd146c708c16"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Docker-Distribution-API-Version", "registry/2.0")
+		switch r.URL.Path {
+		case "/v2/":
+		case "/v2/foo/bar/tags/list":
+			w.Write([]byte(`{"name":"foo/bar","tags":["latest"]}`))
+		case "/v2/foo/bar/manifests/latest", "/v2/foo/bar/manifests/" + dgst:
+			w.Header().Set("Docker-Content-Digest", dgst)
+			w.Write([]byte(manifest))
+		case "/v2/foo/bar/blobs/sha256:a3ed95caeb02ffe68cdd9fd84406680ae93d633cb16422d00e8a7c22955b46d4":
+			w.Write([]byte("layer"))
+		default:
+			if strings.HasPrefix(r.URL.Path, "/v1/") {
+				t.Errorf("unexpected V1 request %s", r.URL.Path)
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	conn, err := NewClient().Connect(server.URL, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tags, err := conn.ImageTags("foo", "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(map[string]string{"latest": dgst}, tags) {
+		t.Errorf("unexpected tags: %v", tags)
+	}
+
+	image, err := conn.ImageByTag("foo", "bar", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if image.ID != dgst || string(image.Manifest) != manifest || image.Config == nil || !reflect.DeepEqual(image.Config.Entrypoint, []string{"/bin/sh"}) {
+		t.Errorf("unexpected image: %#v", image)
+	}
+	if _, err := conn.ImageByID("foo", "bar", dgst); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := conn.ImageByTag("foo", "bar", "missing"); !IsTagNotFound(err) {
+		t.Errorf("expected a tag not found error, got %v", err)
+	}
+	if _, err := conn.ImageTags("foo", "missing"); !IsRepositoryNotFound(err) {
+		t.Errorf("expected a repository not found error, got %v", err)
+	}
+
+	layer, err := conn.ImageLayer("foo", "bar", "sha256:a3ed95caeb02ffe68cdd9fd84406680ae93d633cb16422d00e8a7c22955b46d4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer layer.Close()
+	if content, err := ioutil.ReadAll(layer); err != nil || string(content) != "layer" {
+		t.Errorf("unexpected layer content %q: %v", content, err)
+	}
+	if _, err := conn.ImageLayer("foo", "bar", "sha256:missing"); !IsLayerNotFound(err) {
+		t.Errorf("expected a layer not found error, got %v", err)
+	}
+}
+
 func TestImage(t *testing.T) {
-	conn, err := NewClient().Connect("", false)
+	conn, err := NewClient().Connect("", false, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
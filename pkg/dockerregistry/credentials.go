@@ -0,0 +1,63 @@
+package dockerregistry
+
+import (
+	"encoding/json"
+	"net/url"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/credentialprovider"
+	"github.com/golang/glog"
+)
+
+// DockerCfgKey is the key of the secret data holding the contents of a .dockercfg file.
+const DockerCfgKey = ".dockercfg"
+
+// Credentials supplies the username and password used to authenticate to a registry.
+type Credentials interface {
+	// Basic returns the username and password for the registry at url, or two empty
+	// strings if there are none.
+	Basic(url *url.URL) (string, string)
+}
+
+// NoCredentials accesses registries anonymously.
+var NoCredentials Credentials = noCredentials{}
+
+type noCredentials struct{}
+
+func (noCredentials) Basic(url *url.URL) (string, string) {
+	return "", ""
+}
+
+// NewCredentialsForSecrets returns the credentials stored in the .dockercfg of each
+// of secrets. Secrets without a .dockercfg, or with an invalid one, are ignored.
+func NewCredentialsForSecrets(secrets []kapi.Secret) Credentials {
+	hosts := make(hostCredentials)
+	for _, secret := range secrets {
+		data, ok := secret.Data[DockerCfgKey]
+		if !ok {
+			continue
+		}
+		cfg := credentialprovider.DockerConfig{}
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			glog.V(2).Infof("Ignoring invalid %s in secret %s/%s: %v", DockerCfgKey, secret.Namespace, secret.Name, err)
+			continue
+		}
+		for registry, entry := range cfg {
+			target, err := normalizeRegistryName(registry)
+			if err != nil {
+				glog.V(2).Infof("Ignoring invalid registry %q in secret %s/%s: %v", registry, secret.Namespace, secret.Name, err)
+				continue
+			}
+			hosts[target.Host] = entry
+		}
+	}
+	return hosts
+}
+
+// hostCredentials holds the credentials of each registry host.
+type hostCredentials map[string]credentialprovider.DockerConfigEntry
+
+func (c hostCredentials) Basic(url *url.URL) (string, string) {
+	entry := c[url.Host]
+	return entry.Username, entry.Password
+}
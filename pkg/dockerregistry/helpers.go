@@ -23,6 +23,31 @@ func NewUserOpenShiftClient(bearerToken string) (*osclient.Client, error) {
 }
 
 func NewRegistryOpenShiftClient() (*osclient.Client, error) {
+	config, err := registryClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	client, err := osclient.New(config)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating OpenShift client: %s", err)
+	}
+	return client, nil
+}
+
+func NewRegistryKubernetesClient() (*kclient.Client, error) {
+	config, err := registryClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	client, err := kclient.New(config)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating Kubernetes client: %s", err)
+	}
+	return client, nil
+}
+
+// registryClientConfig returns the client config for the credentials of the registry.
+func registryClientConfig() (*kclient.Config, error) {
 	config, err := openShiftClientConfig()
 	if err != nil {
 		return nil, err
@@ -39,11 +64,7 @@ func NewRegistryOpenShiftClient() (*osclient.Client, error) {
 		config.TLSClientConfig.CertData = []byte(certData)
 		config.TLSClientConfig.KeyData = []byte(certKeyData)
 	}
-	client, err := osclient.New(config)
-	if err != nil {
-		return nil, fmt.Errorf("Error creating OpenShift client: %s", err)
-	}
-	return client, nil
+	return config, nil
 }
 
 func openShiftClientConfig() (*kclient.Config, error) {
@@ -10,6 +10,7 @@ import (
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	log "github.com/Sirupsen/logrus"
 	"github.com/docker/distribution"
 	"github.com/docker/distribution/digest"
@@ -31,6 +32,9 @@ type repository struct {
 	distribution.Repository
 
 	registryClient *client.Client
+	quotaClient    kclient.ResourceQuotasNamespacer
+	secrets        kclient.SecretsNamespacer
+	upstream       dockerregistry.Client
 	registryAddr   string
 	namespace      string
 	name           string
@@ -47,6 +51,10 @@ func newRepository(repo distribution.Repository, options map[string]interface{})
 	if err != nil {
 		return nil, err
 	}
+	kubeClient, err := dockerregistry.NewRegistryKubernetesClient()
+	if err != nil {
+		return nil, err
+	}
 
 	nameParts := strings.SplitN(repo.Name(), "/", 2)
 	if len(nameParts) != 2 {
@@ -56,6 +64,9 @@ func newRepository(repo distribution.Repository, options map[string]interface{})
 	return &repository{
 		Repository:     repo,
 		registryClient: registryClient,
+		quotaClient:    kubeClient,
+		secrets:        kubeClient,
+		upstream:       dockerregistry.NewClient(),
 		registryAddr:   registryAddr,
 		namespace:      nameParts[0],
 		name:           nameParts[1],
@@ -69,13 +80,16 @@ func (r *repository) Manifests() distribution.ManifestService {
 
 // Tags lists the tags under the named repository.
 func (r *repository) Tags(ctx context.Context) ([]string, error) {
-	imageStream, err := r.getImageStream(ctx)
+	imageStream, err := r.getImageStream()
 	if err != nil {
 		return []string{}, nil
 	}
 	tags := []string{}
-	for tag := range imageStream.Status.Tags {
-		tags = append(tags, tag)
+	for tag, history := range imageStream.Status.Tags {
+		// a tag whose import failed has no images
+		if len(history.Items) > 0 {
+			tags = append(tags, tag)
+		}
 	}
 
 	return tags, nil
@@ -92,17 +106,17 @@ func (r *repository) Exists(ctx context.Context, dgst digest.Digest) (bool, erro
 
 // ExistsByTag returns true if the manifest with tag `tag` exists.
 func (r *repository) ExistsByTag(ctx context.Context, tag string) (bool, error) {
-	imageStream, err := r.getImageStream(ctx)
+	imageStream, err := r.getImageStream()
 	if err != nil {
 		return false, err
 	}
-	_, found := imageStream.Status.Tags[tag]
-	return found, nil
+	history, found := imageStream.Status.Tags[tag]
+	return found && len(history.Items) > 0, nil
 }
 
 // Get retrieves the manifest with digest `dgst`.
 func (r *repository) Get(ctx context.Context, dgst digest.Digest) (*manifest.SignedManifest, error) {
-	_, err := r.getImageStreamImage(ctx, dgst)
+	_, err := r.getImageStreamImage(dgst)
 	if err != nil {
 		return nil, err
 	}
@@ -118,7 +132,7 @@ func (r *repository) Get(ctx context.Context, dgst digest.Digest) (*manifest.Sig
 // Get retrieves the named manifest, if it exists.
 func (r *repository) GetByTag(ctx context.Context, tag string) (*manifest.SignedManifest, error) {
 	var image *imageapi.Image
-	if imageStreamTag, err := r.getImageStreamTag(ctx, tag); err == nil {
+	if imageStreamTag, err := r.getImageStreamTag(tag); err == nil {
 		image = &imageStreamTag.Image
 	} else {
 		// TODO remove when docker 1.6 is out
@@ -140,7 +154,7 @@ func (r *repository) GetByTag(ctx context.Context, tag string) (*manifest.Signed
 			log.Errorf("GetByTag: unable to parse digest: %s", dgstErr)
 			return nil, err
 		}
-		imageStreamImage, err := r.getImageStreamImage(ctx, dgst)
+		imageStreamImage, err := r.getImageStreamImage(dgst)
 		if err != nil {
 			log.Errorf("GetByTag: getImageStreamImage returned error: %s", err)
 			return nil, err
@@ -175,6 +189,16 @@ func (r *repository) Put(ctx context.Context, manifest *manifest.SignedManifest)
 		return err
 	}
 
+	size, err := imageSize(r.Repository.Layers(), manifest)
+	if err != nil {
+		log.Errorf("Error calculating the size of image %s: %s", dgst, err)
+		return err
+	}
+	if err := r.checkQuota(dgst, size); err != nil {
+		log.Errorf("Rejecting image %s: %s", dgst, err)
+		return err
+	}
+
 	// Upload to openshift
 	ism := imageapi.ImageStreamMapping{
 		ObjectMeta: kapi.ObjectMeta{
@@ -187,7 +211,12 @@ func (r *repository) Put(ctx context.Context, manifest *manifest.SignedManifest)
 				Name: dgst.String(),
 			},
 			DockerImageReference: fmt.Sprintf("%s/%s/%s@%s", r.registryAddr, r.namespace, r.name, dgst.String()),
-			DockerImageManifest:  string(payload),
+			// the signed manifest lets the signatures of the image be verified
+			DockerImageManifest: string(manifest.Raw),
+			// the size of the stored layers counts towards the image storage quota
+			DockerImageMetadata: imageapi.DockerImage{
+				Size: size,
+			},
 		},
 	}
 
@@ -250,13 +279,20 @@ func (r *repository) Delete(ctx context.Context, dgst digest.Digest) error {
 	return r.registryClient.Images().Delete(dgst.String())
 }
 
-// getImageStream retrieves the ImageStream for r.
-func (r *repository) getImageStream(ctx context.Context) (*imageapi.ImageStream, error) {
-	client, err := getUserOpenShiftClient(ctx)
+// checkQuota returns an error if storing the image dgst of size bytes exceeds the
+// image quota of the project of r.
+func (r *repository) checkQuota(dgst digest.Digest, size int64) error {
+	quota, err := getImageQuota(r.quotaClient, r.namespace)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return client.ImageStreams(r.namespace).Get(r.name)
+	return quota.check(r.registryClient, r.registryClient, r.registryAddr, r.namespace, r.name, dgst.String(), size)
+}
+
+// getImageStream retrieves the ImageStream for r. This uses the registry's credentials,
+// since the access controller has verified the user may pull from the image stream.
+func (r *repository) getImageStream() (*imageapi.ImageStream, error) {
+	return r.registryClient.ImageStreams(r.namespace).Get(r.name)
 }
 
 // getImage retrieves the Image with digest `dgst`. This uses the registry's
@@ -268,31 +304,33 @@ func (r *repository) getImage(dgst digest.Digest) (*imageapi.Image, error) {
 
 // getImageStreamTag retrieves the Image with tag `tag` for the ImageStream
 // associated with r.
-func (r *repository) getImageStreamTag(ctx context.Context, tag string) (*imageapi.ImageStreamTag, error) {
-	client, err := getUserOpenShiftClient(ctx)
-	if err != nil {
-		return nil, err
-	}
-	return client.ImageStreamTags(r.namespace).Get(r.name, tag)
+func (r *repository) getImageStreamTag(tag string) (*imageapi.ImageStreamTag, error) {
+	return r.registryClient.ImageStreamTags(r.namespace).Get(r.name, tag)
 }
 
 // getImageStreamImage retrieves the Image with digest `dgst` for the ImageStream
-// associated with r. This ensures the user has access to the image.
-func (r *repository) getImageStreamImage(ctx context.Context, dgst digest.Digest) (*imageapi.ImageStreamImage, error) {
-	client, err := getUserOpenShiftClient(ctx)
-	if err != nil {
-		return nil, err
-	}
-	return client.ImageStreamImages(r.namespace).Get(r.name, dgst.String())
+// associated with r. This ensures the image belongs to the image stream.
+func (r *repository) getImageStreamImage(dgst digest.Digest) (*imageapi.ImageStreamImage, error) {
+	return r.registryClient.ImageStreamImages(r.namespace).Get(r.name, dgst.String())
 }
 
-// manifestFromImage converts an Image to a SignedManifest.
+// manifestFromImage converts an Image to a SignedManifest. The manifest of an image
+// imported from an upstream registry is pulled through if the image does not carry it.
 func (r *repository) manifestFromImage(image *imageapi.Image) (*manifest.SignedManifest, error) {
 	dgst, err := digest.ParseDigest(image.Name)
 	if err != nil {
 		return nil, err
 	}
 
+	// images pushed to the registry, or imported from a V2 registry, carry their signed manifest
+	if sm, ok := signedManifest(image); ok {
+		return sm, nil
+	}
+
+	if ref, err := imageapi.ParseDockerImageReference(image.DockerImageReference); err == nil && ref.Registry != r.registryAddr {
+		return r.pullManifest(image, dgst)
+	}
+
 	// Fetch the signatures for the manifest
 	signatures, err := r.Signatures().Get(dgst)
 	if err != nil {
@@ -317,6 +355,21 @@ func (r *repository) manifestFromImage(image *imageapi.Image) (*manifest.SignedM
 	return &sm, err
 }
 
+// signedManifest returns the manifest of image if it includes its signatures.
+func signedManifest(image *imageapi.Image) (*manifest.SignedManifest, bool) {
+	if len(image.DockerImageManifest) == 0 {
+		return nil, false
+	}
+	sm := &manifest.SignedManifest{}
+	if err := json.Unmarshal([]byte(image.DockerImageManifest), sm); err != nil {
+		return nil, false
+	}
+	if signatures, err := sm.Signatures(); err != nil || len(signatures) == 0 {
+		return nil, false
+	}
+	return sm, true
+}
+
 func getUserOpenShiftClient(ctx context.Context) (*client.Client, error) {
 	bearerToken, ok := auth.BearerTokenFrom(ctx)
 	if !ok {
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"encoding/json"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	log "github.com/Sirupsen/logrus"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/manifest"
+	"github.com/openshift/origin/pkg/dockerregistry"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+)
+
+// pullthroughLayerService serves the layers of a repository. A layer missing from the
+// registry is pulled from the external repositories the images of the image stream
+// were imported from, with the pull secrets of its namespace, and stored in the registry
+// before it is served.
+type pullthroughLayerService struct {
+	distribution.LayerService
+
+	repo *repository
+}
+
+// Layers returns the layers of r, pulling missing layers through from upstream registries.
+func (r *repository) Layers() distribution.LayerService {
+	return pullthroughLayerService{
+		LayerService: r.Repository.Layers(),
+		repo:         r,
+	}
+}
+
+// Fetch returns the layer dgst, pulling it from an upstream registry if it is unknown.
+func (s pullthroughLayerService) Fetch(dgst digest.Digest) (distribution.Layer, error) {
+	layer, err := s.LayerService.Fetch(dgst)
+	if _, ok := err.(distribution.ErrUnknownLayer); !ok {
+		return layer, err
+	}
+
+	stream, streamErr := s.repo.registryClient.ImageStreams(s.repo.namespace).Get(s.repo.name)
+	if streamErr != nil {
+		log.Errorf("Error retrieving image stream %s/%s to pull layer %s: %s", s.repo.namespace, s.repo.name, dgst, streamErr)
+		return nil, err
+	}
+	credentials, credentialsErr := s.repo.credentials()
+	if credentialsErr != nil {
+		log.Errorf("Error retrieving the pull secrets of %s to pull layer %s: %s", s.repo.namespace, dgst, credentialsErr)
+		return nil, err
+	}
+	insecure := stream.Annotations[imageapi.InsecureRepositoryAnnotation] == "true"
+	for _, upstream := range s.repo.upstreamRepositories(stream) {
+		layer, pullErr := s.pull(upstream, insecure, credentials, dgst)
+		if pullErr != nil {
+			log.Infof("Unable to pull layer %s from %s: %s", dgst, upstream.String(), pullErr)
+			continue
+		}
+		log.Infof("Pulled layer %s from %s", dgst, upstream.String())
+		return layer, nil
+	}
+	return nil, err
+}
+
+// pull copies the layer dgst of the upstream repository into the registry.
+func (s pullthroughLayerService) pull(upstream imageapi.DockerImageReference, insecure bool, credentials dockerregistry.Credentials, dgst digest.Digest) (distribution.Layer, error) {
+	conn, err := s.repo.upstream.Connect(upstream.Registry, insecure, credentials)
+	if err != nil {
+		return nil, err
+	}
+	content, err := conn.ImageLayer(upstream.Namespace, upstream.Name, dgst.String())
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+
+	upload, err := s.LayerService.Upload()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := upload.ReadFrom(content); err != nil {
+		upload.Cancel()
+		return nil, err
+	}
+	layer, err := upload.Finish(dgst)
+	if err != nil {
+		upload.Cancel()
+		return nil, err
+	}
+	return layer, nil
+}
+
+// credentials returns the credentials in the pull secrets of the namespace of r, which
+// upstream registries are accessed with.
+func (r *repository) credentials() (dockerregistry.Credentials, error) {
+	secrets, err := r.secrets.Secrets(r.namespace).List(labels.Everything(), fields.Everything())
+	if err != nil {
+		return nil, err
+	}
+	return dockerregistry.NewCredentialsForSecrets(secrets.Items), nil
+}
+
+// upstreamRepositories returns the repositories in external registries which the
+// images in the tag history of stream were imported from.
+func (r *repository) upstreamRepositories(stream *imageapi.ImageStream) []imageapi.DockerImageReference {
+	seen := util.NewStringSet()
+	upstreams := []imageapi.DockerImageReference{}
+	for _, history := range stream.Status.Tags {
+		for _, event := range history.Items {
+			ref, err := imageapi.ParseDockerImageReference(event.DockerImageReference)
+			if err != nil || ref.Registry == r.registryAddr {
+				continue
+			}
+			ref.Tag, ref.ID = "", ""
+			if seen.Has(ref.String()) {
+				continue
+			}
+			seen.Insert(ref.String())
+			upstreams = append(upstreams, ref)
+		}
+	}
+	return upstreams
+}
+
+// pullManifest retrieves the signed manifest dgst of image from the upstream registry
+// the image was imported from.
+func (r *repository) pullManifest(image *imageapi.Image, dgst digest.Digest) (*manifest.SignedManifest, error) {
+	ref, err := imageapi.ParseDockerImageReference(image.DockerImageReference)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := r.registryClient.ImageStreams(r.namespace).Get(r.name)
+	if err != nil {
+		return nil, err
+	}
+	credentials, err := r.credentials()
+	if err != nil {
+		return nil, err
+	}
+	insecure := stream.Annotations[imageapi.InsecureRepositoryAnnotation] == "true"
+	conn, err := r.upstream.Connect(ref.Registry, insecure, credentials)
+	if err != nil {
+		return nil, err
+	}
+	upstreamImage, err := conn.ImageByID(ref.Namespace, ref.Name, dgst.String())
+	if err != nil {
+		return nil, err
+	}
+	if len(upstreamImage.Manifest) == 0 {
+		return nil, distribution.ErrUnknownManifestRevision{Name: r.Name(), Revision: dgst}
+	}
+	log.Infof("Pulled manifest %s from %s", dgst, ref.String())
+
+	sm := &manifest.SignedManifest{}
+	if err := json.Unmarshal(upstreamImage.Manifest, sm); err != nil {
+		return nil, err
+	}
+	return sm, nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	ktc "github.com/GoogleCloudPlatform/kubernetes/pkg/client/testclient"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/manifest"
+	"github.com/docker/libtrust"
+
+	imageapi "github.com/openshift/origin/pkg/image/api"
+)
+
+func TestUpstreamRepositories(t *testing.T) {
+	stream := &imageapi.ImageStream{
+		Status: imageapi.ImageStreamStatus{
+			Tags: map[string]imageapi.TagEventList{
+				"latest": {
+					Items: []imageapi.TagEvent{
+						{DockerImageReference: "registry:5000/ns/app@sha256:pushed"},
+						{DockerImageReference: "docker.io/library/centos@sha256:one"},
+					},
+				},
+				"7": {
+					Items: []imageapi.TagEvent{
+						{DockerImageReference: "docker.io/library/centos:7"},
+						{DockerImageReference: "mirror:5000/ns/centos@sha256:two"},
+					},
+				},
+			},
+		},
+	}
+	r := &repository{registryAddr: "registry:5000"}
+
+	upstreams := map[string]bool{}
+	for _, ref := range r.upstreamRepositories(stream) {
+		upstreams[ref.String()] = true
+	}
+	expected := map[string]bool{"docker.io/library/centos": true, "mirror:5000/ns/centos": true}
+	if !reflect.DeepEqual(expected, upstreams) {
+		t.Errorf("expected upstream repositories %v, got %v", expected, upstreams)
+	}
+}
+
+func TestPullManifestFromImportedImage(t *testing.T) {
+	key, err := libtrust.GenerateECP256PrivateKey()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	m := &manifest.Manifest{
+		Versioned: manifest.Versioned{SchemaVersion: 1},
+		Name:      "library/centos",
+		Tag:       "latest",
+		FSLayers:  []manifest.FSLayer{{BlobSum: "sha256:a3ed95caeb02ffe68cdd9fd84406680ae93d633cb16422d00e8a7c22955b46d4"}},
+		History:   []manifest.History{{V1Compatibility: `{"id":"layer"}`}},
+	}
+	signed, err := manifest.Sign(m, key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	payload, err := signed.Payload()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dgst, err := digest.FromBytes(payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	image := &imageapi.Image{
+		DockerImageReference: "docker.io/library/centos@" + dgst.String(),
+		DockerImageManifest:  string(signed.Raw),
+	}
+	image.Name = dgst.String()
+
+	r := &repository{registryAddr: "registry:5000"}
+	sm, err := r.manifestFromImage(image)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(sm.Raw) != string(signed.Raw) {
+		t.Errorf("expected the signed manifest of the image, got %s", sm.Raw)
+	}
+}
+
+func TestCredentialsFromPullSecrets(t *testing.T) {
+	fake := &ktc.Fake{
+		ReactFn: func(action ktc.FakeAction) (runtime.Object, error) {
+			return &kapi.SecretList{Items: []kapi.Secret{{
+				ObjectMeta: kapi.ObjectMeta{Name: "pull", Namespace: "ns"},
+				Data: map[string][]byte{
+					".dockercfg": []byte(`{"registry.example.com":{"auth":"dXNlcjpwYXNz","email":"user@example.com"}}`),
+				},
+			}}}, nil
+		},
+	}
+	r := &repository{secrets: fake, namespace: "ns", name: "app"}
+	credentials, err := r.credentials()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fake.Actions) != 1 || fake.Actions[0].Action != "list-secrets" {
+		t.Errorf("expected the secrets to be listed: %#v", fake.Actions)
+	}
+	if username, password := credentials.Basic(&url.URL{Scheme: "https", Host: "registry.example.com"}); username != "user" || password != "pass" {
+		t.Errorf("unexpected credentials: %s %s", username, password)
+	}
+}
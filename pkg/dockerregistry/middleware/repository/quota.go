@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest"
+	"github.com/openshift/origin/pkg/client"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+)
+
+// imageQuota holds the limits on the images pushed to a project, taken from the hard
+// limits of the resource quotas of the project. A limit of zero is not enforced.
+type imageQuota struct {
+	maxImageSize       int64
+	maxImagesPerStream int64
+	maxStorage         int64
+}
+
+// getImageQuota returns the lowest hard limit on each image resource set by the
+// resource quotas of namespace.
+func getImageQuota(quotas kclient.ResourceQuotasNamespacer, namespace string) (imageQuota, error) {
+	list, err := quotas.ResourceQuotas(namespace).List(labels.Everything())
+	if err != nil {
+		return imageQuota{}, err
+	}
+	quota := imageQuota{}
+	for _, item := range list.Items {
+		for resource, limit := range item.Spec.Hard {
+			switch resource {
+			case imageapi.ResourceImageSize:
+				quota.maxImageSize = lowest(quota.maxImageSize, limit.Value())
+			case imageapi.ResourceImagesPerStream:
+				quota.maxImagesPerStream = lowest(quota.maxImagesPerStream, limit.Value())
+			case imageapi.ResourceImageStorage:
+				quota.maxStorage = lowest(quota.maxStorage, limit.Value())
+			}
+		}
+	}
+	return quota, nil
+}
+
+// lowest returns the lower of the limits current and next, where a current limit of
+// zero is unset.
+func lowest(current, next int64) int64 {
+	if current == 0 || next < current {
+		return next
+	}
+	return current
+}
+
+// quotaExceededError is returned when pushing an image would exceed the quota of a project.
+type quotaExceededError struct {
+	namespace string
+	resource  kapi.ResourceName
+	requested int64
+	limit     int64
+}
+
+func (e quotaExceededError) Error() string {
+	return fmt.Sprintf("exceeded quota in project %s: %s=%d requested, %d allowed", e.namespace, e.resource, e.requested, e.limit)
+}
+
+// check returns a quotaExceededError if pushing the image named image, of size bytes, to
+// the image stream namespace/name exceeds q. Only the images whose pull spec starts
+// with registryAddr count towards the storage used by the project.
+func (q imageQuota) check(streams client.ImageStreamsNamespacer, images client.ImagesInterfacer, registryAddr, namespace, name, image string, size int64) error {
+	if q.maxImageSize > 0 && size > q.maxImageSize {
+		return quotaExceededError{namespace, imageapi.ResourceImageSize, size, q.maxImageSize}
+	}
+	if q.maxImagesPerStream == 0 && q.maxStorage == 0 {
+		return nil
+	}
+
+	list, err := streams.ImageStreams(namespace).List(labels.Everything(), fields.Everything())
+	if err != nil {
+		return err
+	}
+
+	if q.maxImagesPerStream > 0 {
+		streamImages := util.NewStringSet(image)
+		for _, stream := range list.Items {
+			if stream.Name != name {
+				continue
+			}
+			for _, history := range stream.Status.Tags {
+				for _, event := range history.Items {
+					streamImages.Insert(event.Image)
+				}
+			}
+		}
+		if count := int64(len(streamImages)); count > q.maxImagesPerStream {
+			return quotaExceededError{namespace, imageapi.ResourceImagesPerStream, count, q.maxImagesPerStream}
+		}
+	}
+
+	if q.maxStorage > 0 {
+		prefix := fmt.Sprintf("%s/%s/", registryAddr, namespace)
+		pushed := util.NewStringSet()
+		for _, stream := range list.Items {
+			for _, history := range stream.Status.Tags {
+				for _, event := range history.Items {
+					if strings.HasPrefix(event.DockerImageReference, prefix) {
+						pushed.Insert(event.Image)
+					}
+				}
+			}
+		}
+		used := int64(0)
+		if !pushed.Has(image) {
+			used = size
+		}
+		for _, pushedName := range pushed.List() {
+			pushedImage, err := images.Images().Get(pushedName)
+			if err != nil {
+				return err
+			}
+			used += pushedImage.DockerImageMetadata.Size
+		}
+		if used > q.maxStorage {
+			return quotaExceededError{namespace, imageapi.ResourceImageStorage, used, q.maxStorage}
+		}
+	}
+	return nil
+}
+
+// imageSize returns the total size in bytes of the layers of m, counting each layer
+// once. It returns an ErrManifestVerification if a layer has not been uploaded.
+func imageSize(layers distribution.LayerService, m *manifest.SignedManifest) (int64, error) {
+	seen := util.NewStringSet()
+	size := int64(0)
+	for _, fsLayer := range m.FSLayers {
+		if seen.Has(fsLayer.BlobSum.String()) {
+			continue
+		}
+		seen.Insert(fsLayer.BlobSum.String())
+
+		layer, err := layers.Fetch(fsLayer.BlobSum)
+		if err != nil {
+			if _, ok := err.(distribution.ErrUnknownLayer); ok {
+				return 0, distribution.ErrManifestVerification{err}
+			}
+			return 0, err
+		}
+		size += layer.Length()
+		layer.Close()
+	}
+	return size, nil
+}
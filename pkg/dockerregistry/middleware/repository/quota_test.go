@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
+	ktc "github.com/GoogleCloudPlatform/kubernetes/pkg/client/testclient"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/manifest"
+
+	"github.com/openshift/origin/pkg/client"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+)
+
+func TestGetImageQuota(t *testing.T) {
+	quotas := &kapi.ResourceQuotaList{
+		Items: []kapi.ResourceQuota{
+			{
+				Spec: kapi.ResourceQuotaSpec{
+					Hard: kapi.ResourceList{
+						kapi.ResourcePods:             resource.MustParse("10"),
+						imageapi.ResourceImageSize:    resource.MustParse("200"),
+						imageapi.ResourceImageStorage: resource.MustParse("1Ki"),
+					},
+				},
+			},
+			{
+				Spec: kapi.ResourceQuotaSpec{
+					Hard: kapi.ResourceList{
+						imageapi.ResourceImageSize:       resource.MustParse("100"),
+						imageapi.ResourceImagesPerStream: resource.MustParse("5"),
+					},
+				},
+			},
+		},
+	}
+	fake := &ktc.Fake{
+		ReactFn: func(action ktc.FakeAction) (runtime.Object, error) {
+			return quotas, nil
+		},
+	}
+
+	quota, err := getImageQuota(fake, "ns")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, a := (imageQuota{maxImageSize: 100, maxImagesPerStream: 5, maxStorage: 1024}), quota; e != a {
+		t.Errorf("expected %#v, got %#v", e, a)
+	}
+}
+
+func TestImageQuotaCheck(t *testing.T) {
+	stream := func(name string, events ...imageapi.TagEvent) imageapi.ImageStream {
+		return imageapi.ImageStream{
+			ObjectMeta: kapi.ObjectMeta{Namespace: "ns", Name: name},
+			Status: imageapi.ImageStreamStatus{
+				Tags: map[string]imageapi.TagEventList{"latest": {Items: events}},
+			},
+		}
+	}
+	pushed := func(name, image string) imageapi.TagEvent {
+		return imageapi.TagEvent{DockerImageReference: "registry:5000/ns/" + name + "@" + image, Image: image}
+	}
+	imported := imageapi.TagEvent{DockerImageReference: "docker.io/library/centos@sha256:imported", Image: "sha256:imported"}
+	streams := &imageapi.ImageStreamList{
+		Items: []imageapi.ImageStream{
+			stream("app", pushed("app", "sha256:one"), pushed("app", "sha256:two")),
+			stream("other", pushed("other", "sha256:three"), imported),
+		},
+	}
+	sizes := map[string]int64{"sha256:one": 100, "sha256:two": 200, "sha256:three": 300, "sha256:imported": 1000}
+	fake := &client.Fake{
+		ReactFn: func(action ktc.FakeAction) (runtime.Object, error) {
+			switch action.Action {
+			case "list-imagestreams":
+				return streams, nil
+			case "get-image":
+				image := &imageapi.Image{}
+				image.Name = action.Value.(string)
+				image.DockerImageMetadata.Size = sizes[image.Name]
+				return image, nil
+			}
+			return nil, nil
+		},
+	}
+
+	tests := map[string]struct {
+		quota    imageQuota
+		stream   string
+		image    string
+		size     int64
+		resource kapi.ResourceName
+	}{
+		"no quota": {
+			stream: "app",
+			image:  "sha256:new",
+			size:   10000,
+		},
+		"image size": {
+			quota:    imageQuota{maxImageSize: 1000},
+			stream:   "app",
+			image:    "sha256:new",
+			size:     1001,
+			resource: imageapi.ResourceImageSize,
+		},
+		"images per stream": {
+			quota:    imageQuota{maxImagesPerStream: 2},
+			stream:   "app",
+			image:    "sha256:new",
+			resource: imageapi.ResourceImagesPerStream,
+		},
+		"image already in the stream": {
+			quota:  imageQuota{maxImagesPerStream: 2},
+			stream: "app",
+			image:  "sha256:one",
+		},
+		"new stream": {
+			quota:  imageQuota{maxImagesPerStream: 1},
+			stream: "new",
+			image:  "sha256:new",
+		},
+		"storage": {
+			quota:    imageQuota{maxStorage: 1000},
+			stream:   "other",
+			image:    "sha256:new",
+			size:     401,
+			resource: imageapi.ResourceImageStorage,
+		},
+		"storage ignores imported images": {
+			quota:  imageQuota{maxStorage: 1000},
+			stream: "other",
+			image:  "sha256:new",
+			size:   400,
+		},
+		"storage counts a pushed image once": {
+			quota:  imageQuota{maxStorage: 600},
+			stream: "other",
+			image:  "sha256:one",
+			size:   100,
+		},
+	}
+
+	for name, test := range tests {
+		err := test.quota.check(fake, fake, "registry:5000", "ns", test.stream, test.image, test.size)
+		if len(test.resource) == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", name, err)
+			}
+			continue
+		}
+		quotaErr, ok := err.(quotaExceededError)
+		if !ok {
+			t.Errorf("%s: expected a quota error, got %v", name, err)
+			continue
+		}
+		if quotaErr.resource != test.resource {
+			t.Errorf("%s: expected the %s quota to be exceeded, got %v", name, test.resource, err)
+		}
+	}
+}
+
+type fakeLayerService struct {
+	distribution.LayerService
+	lengths map[digest.Digest]int64
+}
+
+func (s fakeLayerService) Fetch(dgst digest.Digest) (distribution.Layer, error) {
+	length, ok := s.lengths[dgst]
+	if !ok {
+		return nil, distribution.ErrUnknownLayer{FSLayer: manifest.FSLayer{BlobSum: dgst}}
+	}
+	return fakeLayer{length: length}, nil
+}
+
+type fakeLayer struct {
+	distribution.Layer
+	length int64
+}
+
+func (l fakeLayer) Length() int64 {
+	return l.length
+}
+
+func (l fakeLayer) Close() error {
+	return nil
+}
+
+func TestImageSize(t *testing.T) {
+	layers := fakeLayerService{lengths: map[digest.Digest]int64{"sha256:a": 10, "sha256:b": 20}}
+	m := &manifest.SignedManifest{}
+	m.FSLayers = []manifest.FSLayer{{BlobSum: "sha256:a"}, {BlobSum: "sha256:b"}, {BlobSum: "sha256:a"}}
+
+	size, err := imageSize(layers, m)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if size != 30 {
+		t.Errorf("expected a size of 30, got %d", size)
+	}
+
+	m.FSLayers = append(m.FSLayers, manifest.FSLayer{BlobSum: "sha256:c"})
+	if _, err := imageSize(layers, m); err == nil {
+		t.Errorf("expected an error for a layer which was not uploaded")
+	} else if _, ok := err.(distribution.ErrManifestVerification); !ok {
+		t.Errorf("expected a manifest verification error, got %v", err)
+	}
+}
@@ -0,0 +1,216 @@
+package prune
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/registry/api/v2"
+	storagedriver "github.com/docker/distribution/registry/storage/driver"
+)
+
+// LayersPath is the path of the registry endpoint which deletes layers.
+const LayersPath = "/admin/layers"
+
+// storageRoot is the root of the registry storage layout.
+const storageRoot = "/docker/registry/v2"
+
+// DeleteLayersRequest lists the layers to delete. Each layer digest is mapped
+// to the repositories the layer is linked into.
+type DeleteLayersRequest struct {
+	Layers map[string][]string `json:"layers"`
+}
+
+// DeleteLayersResponse reports the layers which could not be deleted.
+type DeleteLayersResponse struct {
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Authorizer verifies that the bearer token of a request grants access to
+// delete layers.
+type Authorizer func(bearerToken string) error
+
+// handler deletes layers from the storage of the registry.
+type handler struct {
+	driver    storagedriver.StorageDriver
+	authorize Authorizer
+}
+
+// NewHandler returns a handler for LayersPath which deletes layers using driver.
+func NewHandler(driver storagedriver.StorageDriver, authorize Authorizer) http.Handler {
+	return &handler{driver: driver, authorize: authorize}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "DELETE" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	authParts := strings.SplitN(req.Header.Get("Authorization"), " ", 2)
+	if len(authParts) != 2 || strings.ToLower(authParts[0]) != "bearer" {
+		http.Error(w, "authorization header with bearer token required", http.StatusUnauthorized)
+		return
+	}
+	if err := h.authorize(authParts[1]); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	deleteRequest := DeleteLayersRequest{}
+	if err := json.Unmarshal(body, &deleteRequest); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response := DeleteLayersResponse{}
+	for layer, repos := range deleteRequest.Layers {
+		if err := h.deleteLayer(layer, repos); err != nil {
+			log.Errorf("Error deleting layer %s: %v", layer, err)
+			response.Errors = append(response.Errors, err.Error())
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if len(response.Errors) > 0 {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	json.NewEncoder(w).Encode(&response)
+}
+
+// deleteLayer removes the links to layer from repos, and then the layer data.
+func (h *handler) deleteLayer(layer string, repos []string) error {
+	dgst, err := digest.ParseDigest(layer)
+	if err != nil {
+		return fmt.Errorf("invalid layer digest %q: %v", layer, err)
+	}
+
+	for _, repo := range repos {
+		if err := v2.ValidateRespositoryName(repo); err != nil {
+			return fmt.Errorf("invalid repository name %q: %v", repo, err)
+		}
+		linkPath, err := layerLinkPath(repo, dgst)
+		if err != nil {
+			return err
+		}
+		log.Infof("Deleting layer link %s", linkPath)
+		if err := h.delete(linkPath); err != nil {
+			return err
+		}
+	}
+
+	blobPath, err := blobPath(dgst)
+	if err != nil {
+		return err
+	}
+	log.Infof("Deleting layer %s", blobPath)
+	return h.delete(blobPath)
+}
+
+// delete removes path, ignoring paths which do not exist.
+func (h *handler) delete(path string) error {
+	err := h.driver.Delete(path)
+	if _, ok := err.(storagedriver.PathNotFoundError); ok {
+		return nil
+	}
+	return err
+}
+
+// DeleteLayers asks the registry at registryURL to delete layers, authenticating
+// with bearerToken.
+func DeleteLayers(client *http.Client, registryURL, bearerToken string, layers map[string][]string) error {
+	body, err := json.Marshal(&DeleteLayersRequest{Layers: layers})
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(registryURL, "http://") && !strings.HasPrefix(registryURL, "https://") {
+		registryURL = "http://" + registryURL
+	}
+
+	req, err := http.NewRequest("DELETE", strings.TrimSuffix(registryURL, "/")+LayersPath, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+bearerToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	data, _ := ioutil.ReadAll(resp.Body)
+	response := DeleteLayersResponse{}
+	if err := json.Unmarshal(data, &response); err == nil && len(response.Errors) > 0 {
+		return fmt.Errorf("the registry failed to delete some layers: %s", strings.Join(response.Errors, "; "))
+	}
+	return fmt.Errorf("the registry returned %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+}
+
+// blobPath returns the storage path of the data of the blob with digest dgst.
+func blobPath(dgst digest.Digest) (string, error) {
+	components, err := digestPathComponents(dgst, true)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(append([]string{storageRoot, "blobs"}, components...)...), nil
+}
+
+// layerLinkPath returns the storage path of the link to the layer with digest
+// dgst in the repository named name.
+func layerLinkPath(name string, dgst digest.Digest) (string, error) {
+	components, err := digestPathComponents(dgst, false)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(append([]string{storageRoot, "repositories", name, "_layers"}, components...)...), nil
+}
+
+// blobAlgorithmReplacer mirrors the sanitization of digest algorithms done by
+// the registry storage when it lays out paths.
+var blobAlgorithmReplacer = strings.NewReplacer(
+	"+", "/",
+	".", "/",
+	";", "/",
+)
+
+// digestPathComponents returns the path components of dgst in the layout used
+// by the registry storage.
+func digestPathComponents(dgst digest.Digest, multilevel bool) ([]string, error) {
+	if err := dgst.Validate(); err != nil {
+		return nil, err
+	}
+
+	hex := dgst.Hex()
+	prefix := []string{blobAlgorithmReplacer.Replace(dgst.Algorithm())}
+	suffix := []string{}
+	if multilevel {
+		suffix = append(suffix, hex[:2])
+	}
+	suffix = append(suffix, hex)
+
+	if tsi, err := digest.ParseTarSum(dgst.String()); err == nil {
+		version := tsi.Version
+		if len(version) == 0 {
+			version = "v0"
+		}
+		prefix = []string{"tarsum", version, tsi.Algorithm}
+	}
+
+	return append(prefix, suffix...), nil
+}
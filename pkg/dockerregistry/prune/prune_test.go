@@ -0,0 +1,86 @@
+package prune
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/registry/storage/driver/inmemory"
+)
+
+const (
+	testLayer  = "sha256:3c87c572822935df60f0f5d3665bd376841a7fcfeb806b5f212de6a00e9a7b25"
+	otherLayer = "tarsum.dev+sha256:b7c4d4a3c3e8e1c97bb2a2cbb4b1a0e1e4d4f7bfb7c8f2bb9f8a5e0f1c2d3e4f"
+)
+
+func TestPaths(t *testing.T) {
+	blob, err := blobPath(digest.Digest(testLayer))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, a := "/docker/registry/v2/blobs/sha256/3c/3c87c572822935df60f0f5d3665bd376841a7fcfeb806b5f212de6a00e9a7b25", blob; e != a {
+		t.Errorf("expected blob path %s, got %s", e, a)
+	}
+
+	link, err := layerLinkPath("ns/name", digest.Digest(otherLayer))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, a := "/docker/registry/v2/repositories/ns/name/_layers/tarsum/dev/sha256/b7c4d4a3c3e8e1c97bb2a2cbb4b1a0e1e4d4f7bfb7c8f2bb9f8a5e0f1c2d3e4f", link; e != a {
+		t.Errorf("expected link path %s, got %s", e, a)
+	}
+}
+
+func TestDeleteLayers(t *testing.T) {
+	driver := inmemory.New()
+	blob, _ := blobPath(digest.Digest(testLayer))
+	link, _ := layerLinkPath("ns/name", digest.Digest(testLayer))
+	for _, p := range []string{blob + "/data", link + "/link"} {
+		if err := driver.PutContent(p, []byte("content")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	server := httptest.NewServer(NewHandler(driver, func(token string) error {
+		if token != "token" {
+			return errors.New("denied")
+		}
+		return nil
+	}))
+	defer server.Close()
+
+	if err := DeleteLayers(http.DefaultClient, server.URL, "other", map[string][]string{testLayer: {"ns/name"}}); err == nil {
+		t.Fatalf("expected an error for an unauthorized token")
+	}
+	if _, err := driver.GetContent(blob + "/data"); err != nil {
+		t.Fatalf("expected the layer to remain after an unauthorized request: %v", err)
+	}
+
+	if err := DeleteLayers(http.DefaultClient, server.URL, "token", map[string][]string{testLayer: {"ns/name"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, p := range []string{blob + "/data", link + "/link"} {
+		if _, err := driver.GetContent(p); err == nil {
+			t.Errorf("expected %s to be deleted", p)
+		}
+	}
+
+	// deleting a layer which no longer exists succeeds
+	if err := DeleteLayers(http.DefaultClient, server.URL, "token", map[string][]string{testLayer: {"ns/name"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDeleteLayersInvalid(t *testing.T) {
+	server := httptest.NewServer(NewHandler(inmemory.New(), func(string) error { return nil }))
+	defer server.Close()
+
+	if err := DeleteLayers(http.DefaultClient, server.URL, "token", map[string][]string{"invalid": nil}); err == nil {
+		t.Errorf("expected an error for an invalid digest")
+	}
+	if err := DeleteLayers(http.DefaultClient, server.URL, "token", map[string][]string{testLayer: {"../../blobs"}}); err == nil {
+		t.Errorf("expected an error for an invalid repository")
+	}
+}
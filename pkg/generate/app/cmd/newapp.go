@@ -37,9 +37,10 @@ type AppConfig struct {
 
 	TypeOfBuild string
 
-	dockerResolver      app.Resolver
-	imageStreamResolver app.Resolver
-	templateResolver    app.Resolver
+	dockerResolver         app.Resolver
+	dockerRegistryResolver *app.DockerRegistryResolver
+	imageStreamResolver    app.Resolver
+	templateResolver       app.Resolver
 
 	searcher app.Searcher
 	detector app.Detector
@@ -62,7 +63,7 @@ type errlist interface {
 
 // NewAppConfig returns a new AppConfig
 func NewAppConfig(typer runtime.ObjectTyper) *AppConfig {
-	dockerResolver := app.DockerRegistryResolver{
+	dockerResolver := &app.DockerRegistryResolver{
 		Client: dockerregistry.NewClient(),
 	}
 	return &AppConfig{
@@ -70,9 +71,10 @@ func NewAppConfig(typer runtime.ObjectTyper) *AppConfig {
 			Detectors: source.DefaultDetectors,
 			Tester:    dockerfile.NewTester(),
 		},
-		dockerResolver: dockerResolver,
-		searcher:       &simpleSearcher{dockerResolver},
-		typer:          typer,
+		dockerResolver:         dockerResolver,
+		dockerRegistryResolver: dockerResolver,
+		searcher:               &simpleSearcher{dockerResolver},
+		typer:                  typer,
 	}
 }
 
@@ -84,6 +86,12 @@ func (c *AppConfig) SetDockerClient(dockerclient *docker.Client) {
 	}
 }
 
+// SetDockerRegistryCredentials sets the credentials used to look up images in Docker
+// registries which require authentication
+func (c *AppConfig) SetDockerRegistryCredentials(credentials dockerregistry.Credentials) {
+	c.dockerRegistryResolver.Credentials = credentials
+}
+
 // SetOpenShiftClient sets the passed OpenShift client in the application configuration
 func (c *AppConfig) SetOpenShiftClient(osclient client.Interface, originNamespace string) {
 	c.osclient = osclient
@@ -120,6 +120,9 @@ func (r DockerClientResolver) lookup(value string) (*ComponentMatch, error) {
 
 type DockerRegistryResolver struct {
 	Client dockerregistry.Client
+	// Credentials are used when a registry requires authentication. If nil, registries
+	// are accessed anonymously.
+	Credentials dockerregistry.Credentials
 
 	AllowInsecure bool
 }
@@ -130,7 +133,7 @@ func (r DockerRegistryResolver) Resolve(value string) (*ComponentMatch, error) {
 		return nil, err
 	}
 	glog.V(4).Infof("checking Docker registry for %q", ref.String())
-	connection, err := r.Client.Connect(ref.Registry, r.AllowInsecure)
+	connection, err := r.Client.Connect(ref.Registry, r.AllowInsecure, r.Credentials)
 	if err != nil {
 		if dockerregistry.IsRegistryNotFound(err) {
 			return nil, ErrNoMatch{value: value}
@@ -139,7 +142,7 @@ func (r DockerRegistryResolver) Resolve(value string) (*ComponentMatch, error) {
 	}
 	image, err := connection.ImageByTag(ref.Namespace, ref.Name, ref.Tag)
 	if err != nil {
-		if dockerregistry.IsNotFound(err) {
+		if dockerregistry.IsNotFound(err) || dockerregistry.IsUnauthorized(err) {
 			return nil, ErrNoMatch{value: value, qualifier: err.Error()}
 		}
 		return nil, ErrNoMatch{value: value, qualifier: fmt.Sprintf("can't connect to %q: %v", ref.Registry, err)}
@@ -149,7 +152,7 @@ func (r DockerRegistryResolver) Resolve(value string) (*ComponentMatch, error) {
 	}
 	glog.V(4).Infof("found image: %#v", image)
 	dockerImage := &imageapi.DockerImage{}
-	if err = kapi.Scheme.Convert(image, dockerImage); err != nil {
+	if err = kapi.Scheme.Convert(&image.Image, dockerImage); err != nil {
 		return nil, err
 	}
 
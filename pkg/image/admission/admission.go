@@ -0,0 +1,165 @@
+package admission
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/admission"
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	apierrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
+	"github.com/openshift/origin/pkg/client"
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+)
+
+type signaturePolicy struct {
+	namespaces kclient.NamespacesInterface
+	pods       kclient.PodsNamespacer
+	images     client.ImagesInterfacer
+	// infraImages are the pull specs of the images OpenShift runs its deployer and build
+	// pods with, which are referenced by tag
+	infraImages util.StringSet
+}
+
+// NewSignaturePolicy returns an admission control plugin which rejects pods and
+// deployment configs in a project with the TrustedSignersAnnotation whose images were
+// not signed by one of the keys the project trusts. Images must be referenced by their
+// ID, so that the signers of the image can be looked up. Containers running one of
+// infraImages, the images of the OpenShift infrastructure pods, are not checked.
+func NewSignaturePolicy(namespaces kclient.NamespacesInterface, pods kclient.PodsNamespacer, images client.ImagesInterfacer, infraImages []string) admission.Interface {
+	return &signaturePolicy{
+		namespaces:  namespaces,
+		pods:        pods,
+		images:      images,
+		infraImages: util.NewStringSet(infraImages...),
+	}
+}
+
+// Admit rejects pods and deployment configs which run images that are not signed by
+// a key trusted by their project.
+func (p *signaturePolicy) Admit(a admission.Attributes) error {
+	if a.GetOperation() != "CREATE" && a.GetOperation() != "UPDATE" {
+		return nil
+	}
+
+	var kind, name string
+	var containers []kapi.Container
+	switch obj := a.GetObject().(type) {
+	case *kapi.Pod:
+		kind, name = "pod", obj.Name
+		containers = obj.Spec.Containers
+		if a.GetOperation() == "UPDATE" {
+			// only the images of a pod may change, and most updates are status updates
+			existing, err := p.pods.Pods(a.GetNamespace()).Get(obj.Name)
+			if err != nil {
+				return apierrors.NewForbidden(kind, name, err)
+			}
+			containers = changedContainers(existing.Spec.Containers, obj.Spec.Containers)
+		}
+	case *deployapi.DeploymentConfig:
+		kind, name = "deploymentConfig", obj.Name
+		containers = untriggeredContainers(obj)
+	default:
+		return nil
+	}
+	if len(containers) == 0 {
+		return nil
+	}
+
+	namespace, err := p.namespaces.Namespaces().Get(a.GetNamespace())
+	if err != nil {
+		return apierrors.NewForbidden(kind, name, err)
+	}
+	trusted := trustedSigners(namespace)
+	if len(trusted) == 0 {
+		return nil
+	}
+	for _, container := range containers {
+		if p.infraImages.Has(container.Image) {
+			continue
+		}
+		if err := p.verify(container.Image, trusted); err != nil {
+			return apierrors.NewForbidden(kind, name, err)
+		}
+	}
+	return nil
+}
+
+// verify returns an error unless the image referenced by pullSpec was signed by one
+// of the trusted keys.
+func (p *signaturePolicy) verify(pullSpec string, trusted util.StringSet) error {
+	ref, err := imageapi.ParseDockerImageReference(pullSpec)
+	if err != nil {
+		return err
+	}
+	if len(ref.ID) == 0 {
+		return fmt.Errorf("image %s must be referenced by its ID to verify its signatures", pullSpec)
+	}
+	image, err := p.images.Images().Get(ref.ID)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("the signatures of image %s are unknown", pullSpec)
+		}
+		return err
+	}
+	for _, signer := range image.DockerImageSigners {
+		if trusted.Has(signer) {
+			return nil
+		}
+	}
+	return fmt.Errorf("image %s is not signed by a key trusted by the project", pullSpec)
+}
+
+// changedContainers returns the containers of updated whose image differs from the
+// container with the same name in existing.
+func changedContainers(existing, updated []kapi.Container) []kapi.Container {
+	images := map[string]string{}
+	for _, container := range existing {
+		images[container.Name] = container.Image
+	}
+	changed := []kapi.Container{}
+	for _, container := range updated {
+		if image, ok := images[container.Name]; !ok || image != container.Image {
+			changed = append(changed, container)
+		}
+	}
+	return changed
+}
+
+// trustedSigners returns the IDs of the keys the namespace trusts to sign images.
+func trustedSigners(namespace *kapi.Namespace) util.StringSet {
+	trusted := util.NewStringSet()
+	for _, signer := range strings.Split(namespace.Annotations[imageapi.TrustedSignersAnnotation], ",") {
+		if signer = strings.TrimSpace(signer); len(signer) > 0 {
+			trusted.Insert(signer)
+		}
+	}
+	return trusted
+}
+
+// untriggeredContainers returns the containers of the template of config whose image is
+// not set by an automatic image change trigger. The images set by a trigger are verified
+// when the pods of the deployment are created.
+func untriggeredContainers(config *deployapi.DeploymentConfig) []kapi.Container {
+	triggered := util.NewStringSet()
+	for _, trigger := range config.Triggers {
+		if trigger.Type == deployapi.DeploymentTriggerOnImageChange && trigger.ImageChangeParams != nil && trigger.ImageChangeParams.Automatic {
+			triggered.Insert(trigger.ImageChangeParams.ContainerNames...)
+		}
+	}
+
+	template := config.Template.ControllerTemplate.Template
+	if template == nil {
+		return nil
+	}
+	containers := []kapi.Container{}
+	for _, container := range template.Spec.Containers {
+		if !triggered.Has(container.Name) {
+			containers = append(containers, container)
+		}
+	}
+	return containers
+}
@@ -0,0 +1,180 @@
+package admission
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/admission"
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	apierrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	ktc "github.com/GoogleCloudPlatform/kubernetes/pkg/client/testclient"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	"github.com/openshift/origin/pkg/client"
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+)
+
+func newPolicy(trusted string) admission.Interface {
+	kubeClient := &ktc.Fake{
+		ReactFn: func(action ktc.FakeAction) (runtime.Object, error) {
+			name := action.Value.(string)
+			if action.Action == "get-pod" {
+				// the existing pod runs an unsigned image
+				pod := &kapi.Pod{}
+				pod.Name = name
+				pod.Spec.Containers = []kapi.Container{{Name: "app", Image: "registry/ns/app@sha256:unsigned"}}
+				return pod, nil
+			}
+			namespace := &kapi.Namespace{}
+			namespace.Name = name
+			if len(trusted) > 0 {
+				namespace.Annotations = map[string]string{imageapi.TrustedSignersAnnotation: trusted}
+			}
+			return namespace, nil
+		},
+	}
+	signers := map[string][]string{
+		"sha256:signed":   {"KEY1"},
+		"sha256:unsigned": nil,
+	}
+	osClient := &client.Fake{
+		ReactFn: func(action ktc.FakeAction) (runtime.Object, error) {
+			name := action.Value.(string)
+			keys, ok := signers[name]
+			if !ok {
+				return &imageapi.Image{}, apierrors.NewNotFound("image", name)
+			}
+			image := &imageapi.Image{DockerImageSigners: keys}
+			image.Name = name
+			return image, nil
+		},
+	}
+	return NewSignaturePolicy(kubeClient, kubeClient, osClient, []string{"openshift/origin-deployer:v1.0"})
+}
+
+func podWithImages(images ...string) *kapi.Pod {
+	pod := &kapi.Pod{}
+	pod.Name = "pod"
+	for _, image := range images {
+		pod.Spec.Containers = append(pod.Spec.Containers, kapi.Container{Name: image, Image: image})
+	}
+	return pod
+}
+
+func podWithContainer(name, image string) *kapi.Pod {
+	pod := &kapi.Pod{}
+	pod.Name = "pod"
+	pod.Spec.Containers = []kapi.Container{{Name: name, Image: image}}
+	return pod
+}
+
+func TestSignaturePolicyPods(t *testing.T) {
+	tests := map[string]struct {
+		trusted   string
+		pod       *kapi.Pod
+		operation string
+		forbidden bool
+	}{
+		"no trusted signers": {
+			pod: podWithImages("registry/ns/app:latest"),
+		},
+		"signed image": {
+			trusted: "KEY2, KEY1",
+			pod:     podWithImages("registry/ns/app@sha256:signed"),
+		},
+		"unsigned image": {
+			trusted:   "KEY1",
+			pod:       podWithImages("registry/ns/app@sha256:signed", "registry/ns/app@sha256:unsigned"),
+			forbidden: true,
+		},
+		"untrusted signer": {
+			trusted:   "KEY2",
+			pod:       podWithImages("registry/ns/app@sha256:signed"),
+			forbidden: true,
+		},
+		"unknown image": {
+			trusted:   "KEY1",
+			pod:       podWithImages("registry/ns/app@sha256:unknown"),
+			forbidden: true,
+		},
+		"image referenced by tag": {
+			trusted:   "KEY1",
+			pod:       podWithImages("registry/ns/app:latest"),
+			forbidden: true,
+		},
+		"infrastructure image": {
+			trusted: "KEY1",
+			pod:     podWithImages("openshift/origin-deployer:v1.0"),
+		},
+		"pod claiming to be a deployer pod": {
+			trusted: "KEY1",
+			pod: func() *kapi.Pod {
+				pod := podWithImages("openshift/origin-deployer:v1.0", "registry/ns/app@sha256:unsigned")
+				pod.GenerateName = "deploy-config-1"
+				pod.Annotations = map[string]string{deployapi.DeploymentAnnotation: "config-1"}
+				return pod
+			}(),
+			forbidden: true,
+		},
+		"update without image changes": {
+			trusted:   "KEY1",
+			pod:       podWithContainer("app", "registry/ns/app@sha256:unsigned"),
+			operation: "UPDATE",
+		},
+		"update of an image": {
+			trusted:   "KEY1",
+			pod:       podWithContainer("app", "registry/ns/app@sha256:unknown"),
+			operation: "UPDATE",
+			forbidden: true,
+		},
+		"delete": {
+			trusted:   "KEY1",
+			pod:       podWithImages("registry/ns/app:latest"),
+			operation: "DELETE",
+		},
+	}
+
+	for name, test := range tests {
+		operation := test.operation
+		if len(operation) == 0 {
+			operation = "CREATE"
+		}
+		err := newPolicy(test.trusted).Admit(admission.NewAttributesRecord(test.pod, "ns", "pods", operation))
+		if test.forbidden {
+			if !apierrors.IsForbidden(err) {
+				t.Errorf("%s: expected a forbidden error, got %v", name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+	}
+}
+
+func TestSignaturePolicyDeploymentConfigs(t *testing.T) {
+	config := &deployapi.DeploymentConfig{
+		Triggers: []deployapi.DeploymentTriggerPolicy{
+			{
+				Type: deployapi.DeploymentTriggerOnImageChange,
+				ImageChangeParams: &deployapi.DeploymentTriggerImageChangeParams{
+					Automatic:      true,
+					ContainerNames: []string{"registry/ns/app:latest"},
+				},
+			},
+		},
+	}
+	config.Name = "config"
+	config.Template.ControllerTemplate.Template = &kapi.PodTemplateSpec{
+		Spec: podWithImages("registry/ns/app:latest", "registry/ns/app@sha256:signed").Spec,
+	}
+
+	if err := newPolicy("KEY1").Admit(admission.NewAttributesRecord(config, "ns", "deploymentConfigs", "UPDATE")); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	config.Triggers[0].ImageChangeParams.Automatic = false
+	if err := newPolicy("KEY1").Admit(admission.NewAttributesRecord(config, "ns", "deploymentConfigs", "UPDATE")); !apierrors.IsForbidden(err) {
+		t.Errorf("expected a forbidden error for the image of a manual trigger, got %v", err)
+	}
+}
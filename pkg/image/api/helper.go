@@ -238,7 +238,7 @@ func LatestTaggedImage(stream *ImageStream, tag string) (*TagEvent, error) {
 	}
 	// find the most recent tag event with an image reference
 	if stream.Status.Tags != nil {
-		if history, ok := stream.Status.Tags[tag]; ok {
+		if history, ok := stream.Status.Tags[tag]; ok && len(history.Items) > 0 {
 			return &history.Items[0], nil
 		}
 	}
@@ -246,6 +246,58 @@ func LatestTaggedImage(stream *ImageStream, tag string) (*TagEvent, error) {
 	return nil, fmt.Errorf("no image recorded for %s/%s:%s", stream.Namespace, stream.Name, tag)
 }
 
+// PinnedImageReference returns a pull spec for the image recorded by event. When
+// the event references a content addressable image, the returned pull spec refers
+// to that image by its immutable ID rather than by a mutable tag. Otherwise the
+// recorded DockerImageReference is returned unchanged.
+func PinnedImageReference(event *TagEvent) string {
+	if len(event.Image) == 0 {
+		return event.DockerImageReference
+	}
+	if _, err := digest.ParseDigest(event.Image); err != nil {
+		return event.DockerImageReference
+	}
+	ref, err := ParseDockerImageReference(event.DockerImageReference)
+	if err != nil {
+		return event.DockerImageReference
+	}
+	ref.Tag = ""
+	ref.ID = event.Image
+	return ref.String()
+}
+
+// LimitTagHistory drops the oldest events from the history of each tag of stream so
+// that at most max events remain. A max of zero or less keeps every event. Returns true
+// if any event was dropped.
+func LimitTagHistory(stream *ImageStream, max int) bool {
+	if max <= 0 {
+		return false
+	}
+	changed := false
+	tags := make(map[string]TagEventList)
+	for tag, history := range stream.Status.Tags {
+		if len(history.Items) > max {
+			history.Items = history.Items[:max]
+			changed = true
+		}
+		tags[tag] = history
+	}
+	if changed {
+		stream.Status.Tags = tags
+	}
+	return changed
+}
+
+// ReferencesTagEvent returns true if pullSpec refers to the image recorded by event, either
+// pinned to its immutable ID or as the recorded DockerImageReference. Deployment configs
+// triggered before images were pinned recorded the latter.
+func ReferencesTagEvent(pullSpec string, event *TagEvent) bool {
+	if len(pullSpec) == 0 {
+		return false
+	}
+	return pullSpec == PinnedImageReference(event) || pullSpec == event.DockerImageReference
+}
+
 // AddTagEventToImageStream attempts to update the given image stream with a tag event. It will
 // collapse duplicate entries - returning true if a change was made or false if no change
 // occurred.
@@ -256,7 +308,8 @@ func AddTagEventToImageStream(stream *ImageStream, tag string, next TagEvent) bo
 
 	tags, ok := stream.Status.Tags[tag]
 	if !ok || len(tags.Items) == 0 {
-		stream.Status.Tags[tag] = TagEventList{Items: []TagEvent{next}}
+		tags.Items = []TagEvent{next}
+		stream.Status.Tags[tag] = tags
 		return true
 	}
 
@@ -267,7 +320,15 @@ func AddTagEventToImageStream(stream *ImageStream, tag string, next TagEvent) bo
 		if next.Image == previous.Image {
 			return false
 		}
-		previous.Image = next.Image
+		// the image behind a reference is recorded once it is known, and forgotten
+		// when it is no longer known. A new image behind the same reference is a
+		// new event.
+		if len(previous.Image) == 0 || len(next.Image) == 0 {
+			previous.Image = next.Image
+			stream.Status.Tags[tag] = tags
+			return true
+		}
+		tags.Items = append([]TagEvent{next}, tags.Items...)
 		stream.Status.Tags[tag] = tags
 		return true
 	}
@@ -283,3 +344,25 @@ func AddTagEventToImageStream(stream *ImageStream, tag string, next TagEvent) bo
 	stream.Status.Tags[tag] = tags
 	return true
 }
+
+// UpdateTrackingTags sets next as the current image of every tag in stream which
+// tracks updatedTag, that is every spec tag set from the ImageStreamTag
+// <stream>:<updatedTag> of the same stream. It returns the number of tags changed.
+func UpdateTrackingTags(stream *ImageStream, updatedTag string, next TagEvent) int {
+	updated := 0
+	for tag, tagRef := range stream.Spec.Tags {
+		if tag == updatedTag || tagRef.From == nil || tagRef.From.Kind != "ImageStreamTag" {
+			continue
+		}
+		if len(tagRef.From.Namespace) > 0 && tagRef.From.Namespace != stream.Namespace {
+			continue
+		}
+		if tagRef.From.Name != stream.Name+":"+updatedTag {
+			continue
+		}
+		if AddTagEventToImageStream(stream, tag, next) {
+			updated++
+		}
+	}
+	return updated
+}
@@ -589,6 +589,72 @@ func TestLatestTaggedImage(t *testing.T) {
 	}
 }
 
+func TestPinnedImageReference(t *testing.T) {
+	os.Setenv("OPENSHIFT_REAL_PULL_BY_ID", "1")
+	dockerPullSpecGenerator = nil
+	defer func() {
+		os.Unsetenv("OPENSHIFT_REAL_PULL_BY_ID")
+		dockerPullSpecGenerator = nil
+	}()
+
+	tests := []struct {
+		event    TagEvent
+		expected string
+	}{
+		{
+			event:    TagEvent{DockerImageReference: "registry/ns/foo:latest"},
+			expected: "registry/ns/foo:latest",
+		},
+		{
+			event: TagEvent{
+				DockerImageReference: "registry/ns/foo:latest",
+				Image:                "sha256:3c87c572822935df60f0f5d3665bd376841a7fcfeb806b5f212de6a00e9a7b25",
+			},
+			expected: "registry/ns/foo@sha256:3c87c572822935df60f0f5d3665bd376841a7fcfeb806b5f212de6a00e9a7b25",
+		},
+		{
+			// v1 image IDs can't be pulled by ID
+			event: TagEvent{
+				DockerImageReference: "registry/ns/foo:latest",
+				Image:                "3c87c572822935df60f0f5d3665bd376841a7fcfeb806b5f212de6a00e9a7b25",
+			},
+			expected: "registry/ns/foo:latest",
+		},
+	}
+
+	for i, test := range tests {
+		if e, a := test.expected, PinnedImageReference(&test.event); e != a {
+			t.Errorf("%d: expected %q, got %q", i, e, a)
+		}
+	}
+}
+
+func TestReferencesTagEvent(t *testing.T) {
+	os.Setenv("OPENSHIFT_REAL_PULL_BY_ID", "1")
+	dockerPullSpecGenerator = nil
+	defer func() {
+		os.Unsetenv("OPENSHIFT_REAL_PULL_BY_ID")
+		dockerPullSpecGenerator = nil
+	}()
+
+	event := &TagEvent{
+		DockerImageReference: "registry/ns/foo:latest",
+		Image:                "sha256:3c87c572822935df60f0f5d3665bd376841a7fcfeb806b5f212de6a00e9a7b25",
+	}
+	tests := map[string]bool{
+		"": false,
+		"registry/ns/foo@sha256:3c87c572822935df60f0f5d3665bd376841a7fcfeb806b5f212de6a00e9a7b25": true,
+		"registry/ns/foo:latest": true,
+		"registry/ns/foo@sha256:0000000000000000000000000000000000000000000000000000000000000000": false,
+		"registry/ns/bar:latest": false,
+	}
+	for pullSpec, expected := range tests {
+		if e, a := expected, ReferencesTagEvent(pullSpec, event); e != a {
+			t.Errorf("%q: expected %t, got %t", pullSpec, e, a)
+		}
+	}
+}
+
 func TestAddTagEventToImageStream(t *testing.T) {
 	tests := map[string]struct {
 		tags           map[string]TagEventList
@@ -678,6 +744,34 @@ func TestAddTagEventToImageStream(t *testing.T) {
 							DockerImageReference: "ref",
 							Image:                "newimage",
 						},
+						{
+							DockerImageReference: "ref",
+							Image:                "image",
+						},
+					},
+				},
+			},
+			expectedUpdate: true,
+		},
+		"same ref, image not yet known": {
+			tags: map[string]TagEventList{
+				"latest": {
+					Items: []TagEvent{
+						{
+							DockerImageReference: "ref",
+						},
+					},
+				},
+			},
+			nextRef:   "ref",
+			nextImage: "image",
+			expectedTags: map[string]TagEventList{
+				"latest": {
+					Items: []TagEvent{
+						{
+							DockerImageReference: "ref",
+							Image:                "image",
+						},
 					},
 				},
 			},
@@ -751,3 +845,37 @@ func TestAddTagEventToImageStream(t *testing.T) {
 		}
 	}
 }
+
+func TestUpdateTrackingTags(t *testing.T) {
+	stream := &ImageStream{
+		ObjectMeta: kapi.ObjectMeta{Namespace: "ns", Name: "stream"},
+		Spec: ImageStreamSpec{
+			Tags: map[string]TagReference{
+				"stable":  {From: &kapi.ObjectReference{Kind: "ImageStreamTag", Name: "stream:latest"}},
+				"same-ns": {From: &kapi.ObjectReference{Kind: "ImageStreamTag", Namespace: "ns", Name: "stream:latest"}},
+				"pinned":  {From: &kapi.ObjectReference{Kind: "ImageStreamImage", Name: "stream@id"}},
+				"other":   {From: &kapi.ObjectReference{Kind: "ImageStreamTag", Namespace: "other", Name: "stream:latest"}},
+				"prod":    {From: &kapi.ObjectReference{Kind: "ImageStreamTag", Name: "stream:stable"}},
+			},
+		},
+	}
+	next := TagEvent{DockerImageReference: "registry/ns/stream@id", Image: "id"}
+
+	if e, a := 2, UpdateTrackingTags(stream, "latest", next); e != a {
+		t.Errorf("expected %d tags to be updated, got %d", e, a)
+	}
+	for _, tag := range []string{"stable", "same-ns"} {
+		if event, err := LatestTaggedImage(stream, tag); err != nil || !reflect.DeepEqual(*event, next) {
+			t.Errorf("expected %s to track latest, got %#v: %v", tag, event, err)
+		}
+	}
+	for _, tag := range []string{"pinned", "other", "prod"} {
+		if _, ok := stream.Status.Tags[tag]; ok {
+			t.Errorf("expected %s to be unchanged", tag)
+		}
+	}
+
+	if e, a := 0, UpdateTrackingTags(stream, "latest", next); e != a {
+		t.Errorf("expected no tags to be updated for the same event, got %d", a)
+	}
+}
@@ -26,6 +26,8 @@ type Image struct {
 	DockerImageMetadataVersion string `json:"dockerImageMetadataVersion,omitempty"`
 	// The raw JSON of the manifest
 	DockerImageManifest string `json:"rawManifest,omitempty"`
+	// The IDs of the keys whose signatures of the manifest were verified when the image was created
+	DockerImageSigners []string `json:"dockerImageSigners,omitempty"`
 }
 
 // ImageRepositoryList is a list of ImageRepository objects.
@@ -117,6 +119,31 @@ type ImageStreamStatus struct {
 // TagEventList contains a historical record of images associated with a tag.
 type TagEventList struct {
 	Items []TagEvent `json:"items"`
+	// Conditions is an array of conditions that apply to the tag, such as a failed import
+	Conditions []TagEventCondition `json:"conditions,omitempty"`
+}
+
+// TagEventConditionType is a type of condition on a tag.
+type TagEventConditionType string
+
+// These are the valid conditions of a tag.
+const (
+	// ImportSuccess with status False means the last import of the tag failed.
+	ImportSuccess TagEventConditionType = "ImportSuccess"
+)
+
+// TagEventCondition contains condition information for a tag event.
+type TagEventCondition struct {
+	// Type of the condition, currently only ImportSuccess
+	Type TagEventConditionType `json:"type"`
+	// Status of the condition, one of True, False, Unknown
+	Status kapi.ConditionStatus `json:"status"`
+	// The last time the condition changed
+	LastTransitionTime util.Time `json:"lastTransitionTime,omitempty"`
+	// A brief machine readable explanation for the condition
+	Reason string `json:"reason,omitempty"`
+	// A human readable description of the condition
+	Message string `json:"message,omitempty"`
 }
 
 // TagEvent is used by ImageRepositoryStatus to keep a historical record of images associated with a tag.
@@ -170,11 +197,53 @@ type ImageRepositoryTag struct {
 // ImageStreamTag exists to allow calls to `osc get imageStreamTag ...` to function.
 type ImageStreamTag struct {
 	Image `json:",inline"`
+
+	// Tag is the spec tag this image stream tag is set from. It is required when
+	// creating or updating an image stream tag.
+	Tag *TagReference `json:"tag,omitempty"`
 }
 
 // DefaultImageTag is used when an image tag is needed and the configuration does not specify a tag to use.
 const DefaultImageTag = "latest"
 
+const (
+	// DockerImageRepositoryCheckAnnotation records when the upstream images of an image stream,
+	// or of one of its spec tags, were last imported.
+	DockerImageRepositoryCheckAnnotation = "openshift.io/image.dockerRepositoryCheck"
+	// DockerImageRepositoryCheckErrorAnnotation records why the last import recorded by
+	// DockerImageRepositoryCheckAnnotation failed permanently. It is removed when an import succeeds.
+	DockerImageRepositoryCheckErrorAnnotation = "openshift.io/image.dockerRepositoryCheckError"
+	// ImportIntervalAnnotation may be set on an image stream or one of its spec tags to a
+	// duration such as "30m" to import the upstream images again that often.
+	ImportIntervalAnnotation = "openshift.io/image.importInterval"
+	// MaxTagHistoryAnnotation may be set on an image stream to the number of images kept in
+	// the history of each of its tags, overriding the default of the server.
+	MaxTagHistoryAnnotation = "openshift.io/image.maxTagHistory"
+	// InsecureRepositoryAnnotation may be set true on an image stream to allow insecure access
+	// to pull content.
+	InsecureRepositoryAnnotation = "openshift.io/image.insecureRepository"
+	// TrustedSignersAnnotation may be set on a project to a comma separated list of the IDs
+	// of the libtrust keys it trusts. Pods and deployment configs in the project may then
+	// only run images whose manifest was signed by one of those keys.
+	TrustedSignersAnnotation = "openshift.io/image.trustedSigners"
+	// PublicPullAnnotation may be set true on an image stream, or on a project for all of its
+	// image streams, to allow anyone to pull its images from the integrated registry without
+	// credentials. An image stream may set it false to opt out of a public project.
+	PublicPullAnnotation = "openshift.io/image.publicPull"
+)
+
+const (
+	// ResourceImageSize is the resource quota on the size in bytes of each image pushed to
+	// the integrated registry.
+	ResourceImageSize kapi.ResourceName = "openshift.io/image-size"
+	// ResourceImagesPerStream is the resource quota on the number of images in the tag
+	// history of each image stream.
+	ResourceImagesPerStream kapi.ResourceName = "openshift.io/images-per-stream"
+	// ResourceImageStorage is the resource quota on the total size in bytes of the images
+	// pushed to the integrated registry in a project.
+	ResourceImageStorage kapi.ResourceName = "openshift.io/image-storage"
+)
+
 // ImageStreamImage exists to allow calls to `osc get imageStreamImage ...` to function.
 type ImageStreamImage struct {
 	Image `json:",inline"`
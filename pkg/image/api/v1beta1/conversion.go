@@ -19,6 +19,7 @@ func init() {
 
 			out.DockerImageReference = in.DockerImageReference
 			out.DockerImageManifest = in.DockerImageManifest
+			out.DockerImageSigners = in.DockerImageSigners
 
 			version := in.DockerImageMetadataVersion
 			if len(version) == 0 {
@@ -40,6 +41,7 @@ func init() {
 
 			out.DockerImageReference = in.DockerImageReference
 			out.DockerImageManifest = in.DockerImageManifest
+			out.DockerImageSigners = in.DockerImageSigners
 
 			version := in.DockerImageMetadataVersion
 			if len(version) == 0 {
@@ -78,6 +80,9 @@ func init() {
 				if err := s.Convert(&curr.Items, &newTagEventList.Items, 0); err != nil {
 					return err
 				}
+				if err := s.Convert(&curr.Conditions, &newTagEventList.Conditions, 0); err != nil {
+					return err
+				}
 				(*out)[curr.Tag] = newTagEventList
 			}
 
@@ -96,6 +101,9 @@ func init() {
 				if err := s.Convert(&newTagEventList.Items, &oldTagEventList.Items, 0); err != nil {
 					return err
 				}
+				if err := s.Convert(&newTagEventList.Conditions, &oldTagEventList.Conditions, 0); err != nil {
+					return err
+				}
 
 				*out = append(*out, *oldTagEventList)
 			}
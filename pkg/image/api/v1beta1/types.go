@@ -27,6 +27,8 @@ type Image struct {
 	DockerImageMetadataVersion string `json:"dockerImageMetadataVersion,omitempty"`
 	// The raw JSON of the manifest
 	DockerImageManifest string `json:"dockerImageManifest,omitempty"`
+	// The IDs of the keys whose signatures of the manifest were verified when the image was created
+	DockerImageSigners []string `json:"dockerImageSigners,omitempty"`
 }
 
 // ImageRepositoryList is a list of ImageRepository objects.
@@ -94,6 +96,14 @@ type NamedTagReference struct {
 	From                 *kapi.ObjectReference `json:"from,omitempty"`
 }
 
+// TagReference points a tag at an image, either by DockerImageReference or by
+// the ImageStreamTag or ImageStreamImage it is set from.
+type TagReference struct {
+	Annotations          map[string]string     `json:"annotations,omitempty"`
+	DockerImageReference string                `json:"dockerImageReference,omitempty"`
+	From                 *kapi.ObjectReference `json:"from,omitempty"`
+}
+
 // ImageRepositoryStatus contains information about the state of this image repository.
 //
 // ImageRepositoryStatus is DEPRECATED; use ImageStreamStatus instead.
@@ -120,6 +130,31 @@ type ImageStreamStatus struct {
 type NamedTagEventList struct {
 	Tag   string     `json:"tag"`
 	Items []TagEvent `json:"items"`
+	// Conditions is an array of conditions that apply to the tag, such as a failed import
+	Conditions []TagEventCondition `json:"conditions,omitempty"`
+}
+
+// TagEventConditionType is a type of condition on a tag.
+type TagEventConditionType string
+
+// These are the valid conditions of a tag.
+const (
+	// ImportSuccess with status False means the last import of the tag failed.
+	ImportSuccess TagEventConditionType = "ImportSuccess"
+)
+
+// TagEventCondition contains condition information for a tag event.
+type TagEventCondition struct {
+	// Type of the condition, currently only ImportSuccess
+	Type TagEventConditionType `json:"type"`
+	// Status of the condition, one of True, False, Unknown
+	Status kapi.ConditionStatus `json:"status"`
+	// The last time the condition changed
+	LastTransitionTime util.Time `json:"lastTransitionTime,omitempty"`
+	// A brief machine readable explanation for the condition
+	Reason string `json:"reason,omitempty"`
+	// A human readable description of the condition
+	Message string `json:"message,omitempty"`
 }
 
 // TagEvent is used by ImageRepositoryStatus to keep a historical record of images associated with a tag.
@@ -172,6 +207,10 @@ type ImageRepositoryTag struct {
 // ImageStreamTag exists to allow calls to `osc get imageStreamTag ...` to function.
 type ImageStreamTag struct {
 	Image `json:",inline"`
+
+	// Tag is the spec tag this image stream tag is set from. It is required when
+	// creating or updating an image stream tag.
+	Tag *TagReference `json:"tag,omitempty"`
 }
 
 // ImageStreamImage exists to allow calls to `osc get imageStreamImage ...` to function.
@@ -20,6 +20,7 @@ func init() {
 
 			out.DockerImageReference = in.DockerImageReference
 			out.DockerImageManifest = in.DockerImageManifest
+			out.DockerImageSigners = in.DockerImageSigners
 
 			version := in.DockerImageMetadataVersion
 			if len(version) == 0 {
@@ -41,6 +42,7 @@ func init() {
 
 			out.DockerImageReference = in.DockerImageReference
 			out.DockerImageManifest = in.DockerImageManifest
+			out.DockerImageSigners = in.DockerImageSigners
 
 			version := in.DockerImageMetadataVersion
 			if len(version) == 0 {
@@ -70,6 +72,9 @@ func init() {
 				if err := s.Convert(&curr.Items, &newTagEventList.Items, 0); err != nil {
 					return err
 				}
+				if err := s.Convert(&curr.Conditions, &newTagEventList.Conditions, 0); err != nil {
+					return err
+				}
 				(*out)[curr.Tag] = newTagEventList
 			}
 
@@ -88,6 +93,9 @@ func init() {
 				if err := s.Convert(&newTagEventList.Items, &oldTagEventList.Items, 0); err != nil {
 					return err
 				}
+				if err := s.Convert(&newTagEventList.Conditions, &oldTagEventList.Conditions, 0); err != nil {
+					return err
+				}
 
 				*out = append(*out, *oldTagEventList)
 			}
@@ -97,7 +105,8 @@ func init() {
 		func(in *[]NamedTagReference, out *map[string]newer.TagReference, s conversion.Scope) error {
 			for _, curr := range *in {
 				r := newer.TagReference{
-					Annotations: curr.Annotations,
+					Annotations:          curr.Annotations,
+					DockerImageReference: curr.DockerImageReference,
 				}
 				if err := s.Convert(&curr.From, &r.From, 0); err != nil {
 					return err
@@ -116,8 +125,9 @@ func init() {
 			for _, tag := range allTags {
 				newTagReference := (*in)[tag]
 				oldTagReference := NamedTagReference{
-					Name:        tag,
-					Annotations: newTagReference.Annotations,
+					Name:                 tag,
+					Annotations:          newTagReference.Annotations,
+					DockerImageReference: newTagReference.DockerImageReference,
 				}
 				if err := s.Convert(&newTagReference.From, &oldTagReference.From, 0); err != nil {
 					return err
@@ -218,6 +228,12 @@ func init() {
 			}
 			return s.Convert(&in.Items, &out.Items, 0)
 		},
+		func(in *newer.TagReference, out *TagReference, s conversion.Scope) error {
+			return s.DefaultConvert(in, out, conversion.IgnoreMissingFields)
+		},
+		func(in *TagReference, out *newer.TagReference, s conversion.Scope) error {
+			return s.DefaultConvert(in, out, conversion.IgnoreMissingFields)
+		},
 		func(in *newer.ImageRepositoryTag, out *ImageStreamTag, s conversion.Scope) error {
 			return s.Convert(&in.Image, &out.Image, conversion.IgnoreMissingFields)
 		},
@@ -44,3 +44,41 @@ func TestRoundTripVersionedObject(t *testing.T) {
 		t.Errorf("unable to round trip object: %s", util.ObjectDiff(i, image))
 	}
 }
+
+func TestRoundTripTagReferences(t *testing.T) {
+	stream := &newer.ImageStream{
+		ObjectMeta: kapi.ObjectMeta{Name: "foo", Namespace: "bar"},
+		Spec: newer.ImageStreamSpec{
+			Tags: map[string]newer.TagReference{
+				"7": {DockerImageReference: "centos:7", Annotations: map[string]string{"a": "b"}},
+			},
+		},
+	}
+	data, err := kapi.Scheme.EncodeToVersion(stream, "v1beta3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	obj, err := kapi.Scheme.Decode(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, a := stream.Spec.Tags, obj.(*newer.ImageStream).Spec.Tags; !reflect.DeepEqual(e, a) {
+		t.Errorf("unable to round trip spec tags: %s", util.ObjectDiff(e, a))
+	}
+
+	tag := &newer.ImageStreamTag{
+		Image: newer.Image{ObjectMeta: kapi.ObjectMeta{Name: "foo:7", Namespace: "bar"}},
+		Tag:   &newer.TagReference{DockerImageReference: "centos:7"},
+	}
+	data, err = kapi.Scheme.EncodeToVersion(tag, "v1beta3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	obj, err = kapi.Scheme.Decode(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, a := tag.Tag, obj.(*newer.ImageStreamTag).Tag; !reflect.DeepEqual(e, a) {
+		t.Errorf("unable to round trip the tag reference: %s", util.ObjectDiff(e, a))
+	}
+}
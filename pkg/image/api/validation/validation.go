@@ -2,6 +2,7 @@ package validation
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/validation"
@@ -52,6 +53,11 @@ func ValidateImageStream(stream *api.ImageStream) fielderrors.ValidationErrorLis
 			result = append(result, fielderrors.NewFieldInvalid("spec.dockerImageRepository", stream.Spec.DockerImageRepository, err.Error()))
 		}
 	}
+	if value, ok := stream.Annotations[api.MaxTagHistoryAnnotation]; ok {
+		if max, err := strconv.Atoi(value); err != nil || max < 1 {
+			result = append(result, fielderrors.NewFieldInvalid(fmt.Sprintf("metadata.annotations[%s]", api.MaxTagHistoryAnnotation), value, "must be a positive integer"))
+		}
+	}
 	for tag, tagRef := range stream.Spec.Tags {
 		if len(tagRef.DockerImageReference) > 0 && tagRef.From != nil {
 			result = append(result, fielderrors.NewFieldInvalid(fmt.Sprintf("spec.tags[%s]", tag), "", "only 1 of dockerImageReference or from may be set"))
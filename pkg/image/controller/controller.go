@@ -2,10 +2,14 @@ package controller
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 
 	"github.com/openshift/origin/pkg/client"
@@ -13,126 +17,241 @@ import (
 	"github.com/openshift/origin/pkg/image/api"
 )
 
-const dockerImageRepositoryCheckAnnotation = "openshift.io/image.dockerRepositoryCheck"
-
-// insecureRepositoryAnnotation may be set true on an image stream to allow insecure access to pull content.
-const insecureRepositoryAnnotation = "openshift.io/image.insecureRepository"
-
 type ImportController struct {
 	repositories client.ImageStreamsNamespacer
 	mappings     client.ImageStreamMappingsNamespacer
-	client       dockerregistry.Client
+	// secrets holds the pull secrets used to authenticate to private registries. If
+	// nil, registries are accessed anonymously.
+	secrets kclient.SecretsNamespacer
+	client  dockerregistry.Client
 }
 
-// needsImport returns true if the provided repository should have its tags imported.
-func needsImport(repo *api.ImageStream) bool {
+// needsImport returns true if the provided repository should have its tags imported at now.
+// Repositories are imported once, and again every import interval if one is set.
+func needsImport(repo *api.ImageStream, now time.Time) bool {
 	if len(repo.Spec.DockerImageRepository) == 0 {
 		return false
 	}
-	if repo.Annotations != nil && len(repo.Annotations[dockerImageRepositoryCheckAnnotation]) != 0 {
-		return false
-	}
-	return true
-	/*
-		if len(repo.Spec.Tags) == 0 {
-			return true
+	return importDue(repo.Annotations, importInterval(repo.Annotations), now)
+}
+
+// tagsNeedingImport returns the spec tags of repo imported on their own schedule which
+// are due for import at now. These tags are imported from their DockerImageReference, or
+// from the repository.
+func tagsNeedingImport(repo *api.ImageStream, now time.Time) []string {
+	tags := []string{}
+	for tag, tagRef := range repo.Spec.Tags {
+		if !hasOwnSchedule(tagRef) {
+			continue
 		}
-		emptyTags := 0
-		for _, v := range repo.Spec.Tags {
-			if len(v.DockerImageReference) == 0 {
-				emptyTags++
-			}
+		if len(tagRef.DockerImageReference) == 0 && len(repo.Spec.DockerImageRepository) == 0 {
+			continue
+		}
+		interval := importInterval(tagRef.Annotations)
+		if interval == 0 {
+			interval = importInterval(repo.Annotations)
 		}
-		return emptyTags > 0
-	*/
+		if importDue(tagRef.Annotations, interval, now) {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// hasOwnSchedule returns true if the spec tag is imported on its own schedule, because
+// it points to an external image or has its own import interval.
+func hasOwnSchedule(tagRef api.TagReference) bool {
+	if tagRef.From != nil {
+		return false
+	}
+	return len(tagRef.DockerImageReference) > 0 || importInterval(tagRef.Annotations) > 0
+}
+
+// importInterval returns the import interval set in annotations, or zero.
+func importInterval(annotations map[string]string) time.Duration {
+	value, ok := annotations[api.ImportIntervalAnnotation]
+	if !ok {
+		return 0
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		util.HandleError(fmt.Errorf("invalid %s annotation %q: %v", api.ImportIntervalAnnotation, value, err))
+		return 0
+	}
+	return interval
+}
+
+// importDue returns true if the check recorded in annotations is missing, or is a time
+// more than interval before now. Permanent failures are retried on the same schedule.
+func importDue(annotations map[string]string, interval time.Duration, now time.Time) bool {
+	checked, ok := annotations[api.DockerImageRepositoryCheckAnnotation]
+	if !ok || len(checked) == 0 {
+		return true
+	}
+	if interval == 0 {
+		return false
+	}
+	last, err := time.Parse(time.RFC3339, checked)
+	if err != nil {
+		return true
+	}
+	return !now.Before(last.Add(interval))
 }
 
 // Next processes the given image repository, looking for repos that have DockerImageRepository
-// set but have not yet been marked as "ready". If transient errors occur, err is returned but
-// the image repository is not modified (so it will be tried again later). If a permanent
-// failure occurs the image is marked with an annotation. The tags of the original spec image
-// are left as is (those are updated through status).
+// set but have not yet been marked as "ready", or whose import interval has passed. Spec tags
+// which point to external images or have their own import interval are imported on their own
+// schedule, using the interval of the repo unless they set one. If transient errors occur,
+// err is returned but the image repository is not modified (so it will be tried again later).
+// If a permanent failure occurs the image is marked with an annotation, and the failure is
+// recorded as a condition of the affected tags in status. Registries which require authentication
+// are accessed with the pull secrets of the namespace of the repo. The tags of the original spec
+// image are left as is (those are updated through status).
 func (c *ImportController) Next(repo *api.ImageStream) error {
-	if !needsImport(repo) {
+	now := time.Now()
+	importRepo := needsImport(repo, now)
+	tags := tagsNeedingImport(repo, now)
+	if !importRepo && len(tags) == 0 {
 		return nil
 	}
+
+	credentials, err := c.credentialsFor(repo.Namespace)
+	if err != nil {
+		return err
+	}
+
+	// the result of the import of each tag, nil if it succeeded
+	failures := make(map[string]error)
+	if importRepo {
+		failure, err := c.importRepository(repo, credentials)
+		if err != nil {
+			return err
+		}
+		if repo.Annotations == nil {
+			repo.Annotations = make(map[string]string)
+		}
+		markChecked(repo.Annotations, failure, now)
+		for _, tag := range repositoryTags(repo) {
+			failures[tag] = failure
+		}
+	}
+	for _, tag := range tags {
+		failure, err := c.importTag(repo, tag, credentials)
+		if err != nil {
+			return err
+		}
+		failures[tag] = failure
+		tagRef := repo.Spec.Tags[tag]
+		annotations := make(map[string]string)
+		for k, v := range tagRef.Annotations {
+			annotations[k] = v
+		}
+		markChecked(annotations, failure, now)
+		tagRef.Annotations = annotations
+		repo.Spec.Tags[tag] = tagRef
+	}
+
+	updated, err := c.done(repo)
+	if err != nil || updated == nil {
+		return err
+	}
+	return c.updateConditions(updated, failures, now)
+}
+
+// credentialsFor returns the credentials in the pull secrets of namespace.
+func (c *ImportController) credentialsFor(namespace string) (dockerregistry.Credentials, error) {
+	if c.secrets == nil {
+		return dockerregistry.NoCredentials, nil
+	}
+	secrets, err := c.secrets.Secrets(namespace).List(labels.Everything(), fields.Everything())
+	if err != nil {
+		return nil, err
+	}
+	return dockerregistry.NewCredentialsForSecrets(secrets.Items), nil
+}
+
+// repositoryTags returns the known tags of repo which are imported with its repository,
+// or the default tag if none are known yet.
+func repositoryTags(repo *api.ImageStream) []string {
+	tags := []string{}
+	for tag := range repo.Status.Tags {
+		if tagRef, ok := repo.Spec.Tags[tag]; !ok || !hasOwnSchedule(tagRef) {
+			tags = append(tags, tag)
+		}
+	}
+	for tag, tagRef := range repo.Spec.Tags {
+		if _, ok := repo.Status.Tags[tag]; !ok && tagRef.From == nil && !hasOwnSchedule(tagRef) {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) == 0 {
+		tags = append(tags, api.DefaultImageTag)
+	}
+	return tags
+}
+
+// importRepository imports the tags of the repository of repo, except for the spec tags
+// which are imported on their own schedule. Tags which already point to the upstream image
+// are left alone. A permanent failure is returned as failure.
+func (c *ImportController) importRepository(repo *api.ImageStream, credentials dockerregistry.Credentials) (failure error, err error) {
 	name := repo.Spec.DockerImageRepository
 
 	ref, err := api.ParseDockerImageReference(name)
 	if err != nil {
 		err = fmt.Errorf("invalid docker image repository, cannot import data: %v", err)
 		util.HandleError(err)
-		return c.done(repo, err.Error())
+		return err, nil
 	}
 
-	insecure := repo.Annotations != nil && repo.Annotations[insecureRepositoryAnnotation] == "true"
+	insecure := repo.Annotations != nil && repo.Annotations[api.InsecureRepositoryAnnotation] == "true"
 
-	conn, err := c.client.Connect(ref.Registry, insecure)
+	conn, err := c.client.Connect(ref.Registry, insecure, credentials)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	tags, err := conn.ImageTags(ref.Namespace, ref.Name)
 	switch {
-	case dockerregistry.IsRepositoryNotFound(err), dockerregistry.IsRegistryNotFound(err):
-		return c.done(repo, err.Error())
+	case dockerregistry.IsRepositoryNotFound(err), dockerregistry.IsRegistryNotFound(err), dockerregistry.IsUnauthorized(err):
+		return err, nil
 	case err != nil:
-		return err
+		return nil, err
 	}
 
-	newTags := make(map[string]string) //, len(repo.Spec.Tags))
 	imageToTag := make(map[string][]string)
-	//switch {
-	//case len(repo.Tags) == 0:
-	// copy all tags
-	for tag := range tags {
-		// TODO: switch to image when pull by ID is automatic
-		newTags[tag] = tag
-	}
 	for tag, image := range tags {
+		if tagRef, ok := repo.Spec.Tags[tag]; ok && hasOwnSchedule(tagRef) {
+			continue
+		}
 		imageToTag[image] = append(imageToTag[image], tag)
 	}
-	/*
-		default:
-			for tag, v := range repo.Tags {
-				if len(v) != 0 {
-					newTags[tag] = v
-					continue
-				}
-				image, ok := tags[tag]
-				if !ok {
-					// tag not found, set empty
-					continue
-				}
-				imageToTag[image] = append(imageToTag[image], tag)
-				// TODO: switch to image when pull by ID is automatic
-				newTags[tag] = tag
+
+	for id, tags := range imageToTag {
+		// only images which are new to a tag are retrieved and recorded
+		changed := false
+		for _, tag := range tags {
+			if event, err := api.LatestTaggedImage(repo, tag); err != nil || event.Image != id {
+				changed = true
 			}
 		}
-	*/
-
-	// nothing to tag - no images in the upstream repo, or we're in sync
-	if len(imageToTag) == 0 {
-		return c.done(repo, "")
-	}
+		if !changed {
+			continue
+		}
 
-	for id, tags := range imageToTag {
 		dockerImage, err := conn.ImageByID(ref.Namespace, ref.Name, id)
 		switch {
-		case dockerregistry.IsRepositoryNotFound(err), dockerregistry.IsRegistryNotFound(err):
-			return c.done(repo, err.Error())
+		case dockerregistry.IsRepositoryNotFound(err), dockerregistry.IsRegistryNotFound(err), dockerregistry.IsUnauthorized(err):
+			return err, nil
 		case dockerregistry.IsImageNotFound(err):
-			for _, tag := range tags {
-				delete(newTags, tag)
-			}
 			continue
 		case err != nil:
-			return err
+			return nil, err
 		}
 		var image api.DockerImage
-		if err := kapi.Scheme.Convert(dockerImage, &image); err != nil {
+		if err := kapi.Scheme.Convert(&dockerImage.Image, &image); err != nil {
 			err = fmt.Errorf("could not convert image: %#v", err)
 			util.HandleError(err)
-			return c.done(repo, err.Error())
+			return err, nil
 		}
 
 		idTagPresent := false
@@ -155,49 +274,181 @@ func (c *ImportController) Next(repo *api.ImageStream) error {
 				Name:      ref.Name,
 				Tag:       pullRefTag,
 			}
-
-			mapping := &api.ImageStreamMapping{
-				ObjectMeta: kapi.ObjectMeta{
-					Name:      repo.Name,
-					Namespace: repo.Namespace,
-				},
-				Tag: tag,
-				Image: api.Image{
-					ObjectMeta: kapi.ObjectMeta{
-						Name: id,
-					},
-					DockerImageReference: pullRef.String(),
-					DockerImageMetadata:  image,
-				},
-			}
-			if err := c.mappings.ImageStreamMappings(repo.Namespace).Create(mapping); err != nil {
-				if errors.IsNotFound(err) {
-					return c.done(repo, err.Error())
-				}
-				return err
+			if failure, err := c.createMapping(repo, tag, id, pullRef, image, dockerImage.Manifest); failure != nil || err != nil {
+				return failure, err
 			}
 		}
 	}
 
 	// we've completed our updates
-	return c.done(repo, "")
+	return nil, nil
+}
+
+// importTag imports the upstream image of a single spec tag of repo, from its
+// DockerImageReference or else from the repository of repo. A permanent failure is
+// returned as failure.
+func (c *ImportController) importTag(repo *api.ImageStream, tag string, credentials dockerregistry.Credentials) (failure error, err error) {
+	spec := repo.Spec.Tags[tag].DockerImageReference
+	if len(spec) == 0 {
+		spec = fmt.Sprintf("%s:%s", repo.Spec.DockerImageRepository, tag)
+	}
+	ref, err := api.ParseDockerImageReference(spec)
+	if err != nil {
+		err = fmt.Errorf("invalid docker image reference, cannot import data: %v", err)
+		util.HandleError(err)
+		return err, nil
+	}
+
+	insecure := repo.Annotations != nil && repo.Annotations[api.InsecureRepositoryAnnotation] == "true"
+
+	conn, err := c.client.Connect(ref.Registry, insecure, credentials)
+	if err != nil {
+		return nil, err
+	}
+	dockerImage, err := conn.ImageByTag(ref.Namespace, ref.Name, ref.Tag)
+	switch {
+	case dockerregistry.IsNotFound(err), dockerregistry.IsUnauthorized(err):
+		return err, nil
+	case err != nil:
+		return nil, err
+	}
+
+	if event, err := api.LatestTaggedImage(repo, tag); err == nil && event.Image == dockerImage.ID {
+		return nil, nil
+	}
+
+	var image api.DockerImage
+	if err := kapi.Scheme.Convert(&dockerImage.Image, &image); err != nil {
+		err = fmt.Errorf("could not convert image: %#v", err)
+		util.HandleError(err)
+		return err, nil
+	}
+	return c.createMapping(repo, tag, dockerImage.ID, ref, image, dockerImage.Manifest)
 }
 
-// done marks the repository as being processed due to an error or failure condition
-func (c *ImportController) done(repo *api.ImageStream, reason string) error {
-	if len(reason) == 0 {
-		reason = util.Now().UTC().Format(time.RFC3339)
+// createMapping records the image id, pulled with pullRef, as the current image of tag. The
+// manifest of the image is recorded if it was retrieved from a V2 registry.
+func (c *ImportController) createMapping(repo *api.ImageStream, tag, id string, pullRef api.DockerImageReference, image api.DockerImage, manifest []byte) (failure error, err error) {
+	mapping := &api.ImageStreamMapping{
+		ObjectMeta: kapi.ObjectMeta{
+			Name:      repo.Name,
+			Namespace: repo.Namespace,
+		},
+		Tag: tag,
+		Image: api.Image{
+			ObjectMeta: kapi.ObjectMeta{
+				Name: id,
+			},
+			DockerImageReference: pullRef.String(),
+			DockerImageMetadata:  image,
+			DockerImageManifest:  string(manifest),
+		},
 	}
-	if repo.Annotations == nil {
-		repo.Annotations = make(map[string]string)
+	if err := c.mappings.ImageStreamMappings(repo.Namespace).Create(mapping); err != nil {
+		if errors.IsNotFound(err) {
+			return err, nil
+		}
+		return nil, err
 	}
-	repo.Annotations[dockerImageRepositoryCheckAnnotation] = reason
-	if _, err := c.repositories.ImageStreams(repo.Namespace).Update(repo); err != nil && !errors.IsNotFound(err) {
+	return nil, nil
+}
+
+// markChecked records in annotations that an import finished at now, and why it failed
+// permanently if it did.
+func markChecked(annotations map[string]string, failure error, now time.Time) {
+	annotations[api.DockerImageRepositoryCheckAnnotation] = now.UTC().Format(time.RFC3339)
+	if failure != nil {
+		annotations[api.DockerImageRepositoryCheckErrorAnnotation] = failure.Error()
+	} else {
+		delete(annotations, api.DockerImageRepositoryCheckErrorAnnotation)
+	}
+}
+
+// done saves the import checks recorded on the repository, returning the saved repository
+// or nil if it no longer exists.
+func (c *ImportController) done(repo *api.ImageStream) (*api.ImageStream, error) {
+	updated, err := c.repositories.ImageStreams(repo.Namespace).Update(repo)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return updated, nil
+}
+
+// updateConditions records the failure to import each tag of failures as a false ImportSuccess
+// condition of the tag in the status of repo, and clears the condition of the tags which were
+// imported. The status is only updated if a condition changed.
+func (c *ImportController) updateConditions(repo *api.ImageStream, failures map[string]error, now time.Time) error {
+	changed := false
+	for tag, failure := range failures {
+		list := repo.Status.Tags[tag]
+
+		var previous *api.TagEventCondition
+		conditions := []api.TagEventCondition{}
+		for i := range list.Conditions {
+			if list.Conditions[i].Type == api.ImportSuccess {
+				previous = &list.Conditions[i]
+				continue
+			}
+			conditions = append(conditions, list.Conditions[i])
+		}
+
+		if failure == nil {
+			if previous == nil {
+				continue
+			}
+		} else {
+			condition := api.TagEventCondition{
+				Type:               api.ImportSuccess,
+				Status:             kapi.ConditionFalse,
+				LastTransitionTime: util.NewTime(now),
+				Reason:             importFailureReason(failure),
+				Message:            failure.Error(),
+			}
+			if previous != nil {
+				if previous.Reason == condition.Reason && previous.Message == condition.Message {
+					continue
+				}
+				condition.LastTransitionTime = previous.LastTransitionTime
+			}
+			conditions = append(conditions, condition)
+		}
+
+		changed = true
+		list.Conditions = conditions
+		if len(list.Items) == 0 && len(list.Conditions) == 0 {
+			delete(repo.Status.Tags, tag)
+			continue
+		}
+		if repo.Status.Tags == nil {
+			repo.Status.Tags = make(map[string]api.TagEventList)
+		}
+		repo.Status.Tags[tag] = list
+	}
+	if !changed {
+		return nil
+	}
+
+	if _, err := c.repositories.ImageStreams(repo.Namespace).UpdateStatus(repo); err != nil && !errors.IsNotFound(err) {
 		return err
 	}
 	return nil
 }
 
+// importFailureReason returns a brief machine readable reason for a permanent import failure.
+func importFailureReason(failure error) string {
+	switch {
+	case dockerregistry.IsUnauthorized(failure):
+		return "Unauthorized"
+	case dockerregistry.IsNotFound(failure), errors.IsNotFound(failure):
+		return "NotFound"
+	default:
+		return "InvalidImage"
+	}
+}
+
 func hasTag(tags []string, tag string) bool {
 	for _, s := range tags {
 		if s == tag {
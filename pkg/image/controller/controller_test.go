@@ -2,12 +2,16 @@ package controller
 
 import (
 	"fmt"
+	"io"
+	"net/url"
 	"testing"
 	"time"
 
 	"github.com/fsouza/go-dockerclient"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	ktc "github.com/GoogleCloudPlatform/kubernetes/pkg/client/testclient"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 
 	"github.com/openshift/origin/pkg/client"
 	"github.com/openshift/origin/pkg/dockerregistry"
@@ -18,13 +22,23 @@ type expectedImage struct {
 	Tag   string
 	ID    string
 	Image *docker.Image
-	Err   error
+	// Manifest is set for images from V2 registries
+	Manifest []byte
+	Err      error
+}
+
+func (e expectedImage) registryImage() *dockerregistry.Image {
+	if e.Image == nil {
+		return nil
+	}
+	return &dockerregistry.Image{Image: *e.Image, Manifest: e.Manifest}
 }
 
 type fakeDockerRegistryClient struct {
 	Registry                 string
 	Namespace, Name, Tag, ID string
 	Insecure                 bool
+	Credentials              dockerregistry.Credentials
 
 	Tags map[string]string
 	Err  error
@@ -32,9 +46,10 @@ type fakeDockerRegistryClient struct {
 	Images []expectedImage
 }
 
-func (f *fakeDockerRegistryClient) Connect(registry string, insecure bool) (dockerregistry.Connection, error) {
+func (f *fakeDockerRegistryClient) Connect(registry string, insecure bool, credentials dockerregistry.Credentials) (dockerregistry.Connection, error) {
 	f.Registry = registry
 	f.Insecure = insecure
+	f.Credentials = credentials
 	return f, nil
 }
 
@@ -43,24 +58,29 @@ func (f *fakeDockerRegistryClient) ImageTags(namespace, name string) (map[string
 	return f.Tags, f.Err
 }
 
-func (f *fakeDockerRegistryClient) ImageByTag(namespace, name, tag string) (*docker.Image, error) {
+func (f *fakeDockerRegistryClient) ImageByTag(namespace, name, tag string) (*dockerregistry.Image, error) {
 	if len(tag) == 0 {
 		tag = api.DefaultImageTag
 	}
 	f.Namespace, f.Name, f.Tag = namespace, name, tag
 	for _, t := range f.Images {
 		if t.Tag == tag {
-			return t.Image, t.Err
+			return t.registryImage(), t.Err
 		}
 	}
 	return nil, dockerregistry.NewImageNotFoundError(fmt.Sprintf("%s/%s", namespace, name), tag, tag)
 }
 
-func (f *fakeDockerRegistryClient) ImageByID(namespace, name, id string) (*docker.Image, error) {
+func (f *fakeDockerRegistryClient) ImageLayer(namespace, name, dgst string) (io.ReadCloser, error) {
+	f.Namespace, f.Name = namespace, name
+	return nil, dockerregistry.NewImageNotFoundError(fmt.Sprintf("%s/%s", namespace, name), dgst, "")
+}
+
+func (f *fakeDockerRegistryClient) ImageByID(namespace, name, id string) (*dockerregistry.Image, error) {
 	f.Namespace, f.Name, f.ID = namespace, name, id
 	for _, t := range f.Images {
 		if t.ID == id {
-			return t.Image, t.Err
+			return t.registryImage(), t.Err
 		}
 	}
 	return nil, dockerregistry.NewImageNotFoundError(fmt.Sprintf("%s/%s", namespace, name), id, "")
@@ -297,6 +317,236 @@ func TestControllerWithEmptyTag(t *testing.T) {
 }
 */
 
+func TestControllerReimportsAfterInterval(t *testing.T) {
+	checked := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	newRepo := func(interval string) api.ImageStream {
+		return api.ImageStream{
+			ObjectMeta: kapi.ObjectMeta{
+				Name:      "test",
+				Namespace: "other",
+				Annotations: map[string]string{
+					"openshift.io/image.dockerRepositoryCheck": checked,
+					"openshift.io/image.importInterval":        interval,
+				},
+			},
+			Spec: api.ImageStreamSpec{
+				DockerImageRepository: "foo/bar",
+			},
+			Status: api.ImageStreamStatus{
+				Tags: map[string]api.TagEventList{
+					"latest": {Items: []api.TagEvent{{DockerImageReference: "foo/bar:latest", Image: "old"}}},
+					"stable": {Items: []api.TagEvent{{DockerImageReference: "foo/bar:stable", Image: "stable"}}},
+				},
+			},
+		}
+	}
+	newClient := func() *fakeDockerRegistryClient {
+		return &fakeDockerRegistryClient{
+			Tags: map[string]string{"latest": "new", "stable": "stable"},
+			Images: []expectedImage{
+				{ID: "new", Image: &docker.Image{ID: "new", Config: &docker.Config{}}},
+				{ID: "stable", Image: &docker.Image{ID: "stable", Config: &docker.Config{}}},
+			},
+		}
+	}
+
+	// the interval has not passed
+	cli, fake := newClient(), &client.Fake{}
+	c := ImportController{client: cli, repositories: fake, mappings: fake}
+	repo := newRepo("2h")
+	if err := c.Next(&repo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fake.Actions) != 0 {
+		t.Errorf("expected no actions before the interval passed: %#v", fake.Actions)
+	}
+
+	// only the tag whose upstream image changed is recorded
+	cli, fake = newClient(), &client.Fake{}
+	c = ImportController{client: cli, repositories: fake, mappings: fake}
+	repo = newRepo("30m")
+	if err := c.Next(&repo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fake.Actions) != 2 || fake.Actions[0].Action != "create-imagestream-mapping" || fake.Actions[1].Action != "update-imagestream" {
+		t.Errorf("expected one mapping and an update: %#v", fake.Actions)
+	}
+	if cli.ID != "new" {
+		t.Errorf("expected only the changed image to be retrieved, got %q", cli.ID)
+	}
+	if a := repo.Annotations["openshift.io/image.dockerRepositoryCheck"]; !isRFC3339(a) || a == checked {
+		t.Errorf("expected the check time to be updated: %#v", repo.Annotations)
+	}
+}
+
+func TestControllerImportsTagOnItsOwnSchedule(t *testing.T) {
+	cli, fake := &fakeDockerRegistryClient{
+		Images: []expectedImage{
+			{Tag: "7", Image: &docker.Image{ID: "centos7", Config: &docker.Config{}}},
+		},
+	}, &client.Fake{}
+	c := ImportController{client: cli, repositories: fake, mappings: fake}
+	repo := api.ImageStream{
+		ObjectMeta: kapi.ObjectMeta{Name: "test", Namespace: "other"},
+		Spec: api.ImageStreamSpec{
+			Tags: map[string]api.TagReference{
+				"base": {
+					DockerImageReference: "library/centos:7",
+					Annotations:          map[string]string{"openshift.io/image.importInterval": "1h"},
+				},
+				"alias": {From: &kapi.ObjectReference{Kind: "ImageStreamTag", Name: "test:base"}},
+			},
+		},
+		Status: api.ImageStreamStatus{
+			Tags: map[string]api.TagEventList{
+				"base": {Items: []api.TagEvent{{DockerImageReference: "library/centos:7"}}},
+			},
+		},
+	}
+	if err := c.Next(&repo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cli.Namespace != "library" || cli.Name != "centos" || cli.Tag != "7" {
+		t.Errorf("unexpected image retrieved: %#v", cli)
+	}
+	if len(fake.Actions) != 2 {
+		t.Errorf("expected a mapping and an update: %#v", fake.Actions)
+	}
+	if !isRFC3339(repo.Spec.Tags["base"].Annotations["openshift.io/image.dockerRepositoryCheck"]) {
+		t.Errorf("expected the tag check time to be recorded: %#v", repo.Spec.Tags["base"])
+	}
+	if _, ok := repo.Annotations["openshift.io/image.dockerRepositoryCheck"]; ok {
+		t.Errorf("did not expect the stream check time to be recorded: %#v", repo.Annotations)
+	}
+
+	// a second pass before the interval passes does nothing
+	fake.Actions = nil
+	if err := c.Next(&repo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fake.Actions) != 0 {
+		t.Errorf("expected no actions: %#v", fake.Actions)
+	}
+}
+
+func TestControllerRecordsImportFailureConditions(t *testing.T) {
+	cli := &fakeDockerRegistryClient{Err: dockerregistry.NewUnauthorizedError("registry.example.com")}
+	var status *api.ImageStream
+	fake := &client.Fake{
+		ReactFn: func(action ktc.FakeAction) (runtime.Object, error) {
+			switch action.Action {
+			case "update-imagestream":
+				return action.Value.(*api.ImageStream), nil
+			case "update-status-imagestream":
+				status = action.Value.(*api.ImageStream)
+				return status, nil
+			}
+			return &api.ImageStream{}, nil
+		},
+	}
+	kubeFake := &ktc.Fake{
+		ReactFn: func(action ktc.FakeAction) (runtime.Object, error) {
+			return &kapi.SecretList{Items: []kapi.Secret{{
+				ObjectMeta: kapi.ObjectMeta{Name: "pull", Namespace: "other"},
+				Data: map[string][]byte{
+					".dockercfg": []byte(`{"registry.example.com":{"auth":"dXNlcjpwYXNz","email":"user@example.com"}}`),
+				},
+			}}}, nil
+		},
+	}
+	c := ImportController{client: cli, repositories: fake, mappings: fake, secrets: kubeFake}
+	repo := api.ImageStream{
+		ObjectMeta: kapi.ObjectMeta{Name: "test", Namespace: "other"},
+		Spec: api.ImageStreamSpec{
+			DockerImageRepository: "registry.example.com/foo/bar",
+		},
+	}
+	if err := c.Next(&repo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cli.Credentials == nil {
+		t.Fatalf("expected credentials to be used")
+	}
+	if username, password := cli.Credentials.Basic(&url.URL{Scheme: "https", Host: "registry.example.com"}); username != "user" || password != "pass" {
+		t.Errorf("unexpected credentials: %s %s", username, password)
+	}
+	if !isRFC3339(repo.Annotations["openshift.io/image.dockerRepositoryCheck"]) || len(repo.Annotations["openshift.io/image.dockerRepositoryCheckError"]) == 0 {
+		t.Errorf("expected the check time and the failure to be recorded: %#v", repo.Annotations)
+	}
+	if status == nil {
+		t.Fatalf("expected the status to be updated: %#v", fake.Actions)
+	}
+	conditions := status.Status.Tags["latest"].Conditions
+	if len(conditions) != 1 || conditions[0].Type != api.ImportSuccess || conditions[0].Status != kapi.ConditionFalse || conditions[0].Reason != "Unauthorized" {
+		t.Fatalf("unexpected conditions: %#v", conditions)
+	}
+
+	// a successful import clears the condition
+	cli.Err = nil
+	cli.Tags = map[string]string{"latest": "found"}
+	cli.Images = []expectedImage{{ID: "found", Image: &docker.Image{ID: "found", Config: &docker.Config{}}}}
+	repo = *status
+	delete(repo.Annotations, "openshift.io/image.dockerRepositoryCheck")
+	status = nil
+	if err := c.Next(&repo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status == nil {
+		t.Fatalf("expected the status to be updated: %#v", fake.Actions)
+	}
+	if _, ok := status.Status.Tags["latest"]; ok {
+		t.Errorf("expected the condition to be cleared: %#v", status.Status.Tags)
+	}
+	if _, ok := repo.Annotations["openshift.io/image.dockerRepositoryCheckError"]; ok {
+		t.Errorf("expected the failure to be cleared: %#v", repo.Annotations)
+	}
+}
+
+func TestControllerRetriesFailedImportAfterInterval(t *testing.T) {
+	cli, fake := &fakeDockerRegistryClient{}, &client.Fake{}
+	c := ImportController{client: cli, repositories: fake, mappings: fake}
+	repo := api.ImageStream{
+		ObjectMeta: kapi.ObjectMeta{Name: "test", Namespace: "other"},
+		Spec: api.ImageStreamSpec{
+			Tags: map[string]api.TagReference{
+				"base": {
+					DockerImageReference: "library/centos:7",
+					Annotations:          map[string]string{"openshift.io/image.importInterval": "30m"},
+				},
+			},
+		},
+	}
+	if err := c.Next(&repo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	annotations := repo.Spec.Tags["base"].Annotations
+	if !isRFC3339(annotations["openshift.io/image.dockerRepositoryCheck"]) || len(annotations["openshift.io/image.dockerRepositoryCheckError"]) == 0 {
+		t.Fatalf("expected the check time and the failure to be recorded: %#v", annotations)
+	}
+
+	// the failure is not retried before the interval passed
+	fake.Actions = nil
+	if err := c.Next(&repo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fake.Actions) != 0 {
+		t.Errorf("expected no actions before the interval passed: %#v", fake.Actions)
+	}
+
+	// the failure is retried once the interval passed
+	repo.Spec.Tags["base"].Annotations["openshift.io/image.dockerRepositoryCheck"] = time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	cli.Images = []expectedImage{{Tag: "7", Image: &docker.Image{ID: "centos7", Config: &docker.Config{}}}}
+	if err := c.Next(&repo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fake.Actions) == 0 || fake.Actions[0].Action != "create-imagestream-mapping" {
+		t.Errorf("expected the import to be retried: %#v", fake.Actions)
+	}
+	if _, ok := repo.Spec.Tags["base"].Annotations["openshift.io/image.dockerRepositoryCheckError"]; ok {
+		t.Errorf("expected the failure to be cleared: %#v", repo.Spec.Tags["base"].Annotations)
+	}
+}
+
 func isRFC3339(s string) bool {
 	_, err := time.Parse(time.RFC3339, s)
 	return err == nil
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
@@ -21,6 +22,8 @@ import (
 // ImportControllerFactory can create an ImportController.
 type ImportControllerFactory struct {
 	Client client.Interface
+	// KubeClient is used to read the pull secrets of private registries.
+	KubeClient kclient.Interface
 }
 
 // Create creates an ImportController.
@@ -40,6 +43,7 @@ func (f *ImportControllerFactory) Create() controller.RunnableController {
 		client:       dockerregistry.NewClient(),
 		repositories: f.Client,
 		mappings:     f.Client,
+		secrets:      f.KubeClient,
 	}
 
 	return &controller.RetryController{
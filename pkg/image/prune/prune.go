@@ -0,0 +1,275 @@
+package prune
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/golang/glog"
+
+	buildapi "github.com/openshift/origin/pkg/build/api"
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+)
+
+// Options control which tag history entries and images are pruned.
+type Options struct {
+	// KeepTagRevisions is the number of entries to keep in the history of each
+	// image stream tag. The most recent entry is always kept.
+	KeepTagRevisions int
+	// MaxTagAge is the age after which tag history entries are pruned. Zero
+	// disables pruning tag history by age.
+	MaxTagAge time.Duration
+	// KeepYoungerThan protects images created more recently than this from
+	// being pruned, so that images which are still being pushed are not removed.
+	KeepYoungerThan time.Duration
+	// Registries is the set of registry hosts whose layers may be pruned.
+	Registries util.StringSet
+}
+
+// Resources are the objects which are inspected for image references.
+type Resources struct {
+	Images            *imageapi.ImageList
+	Streams           *imageapi.ImageStreamList
+	Pods              *kapi.PodList
+	Controllers       *kapi.ReplicationControllerList
+	DeploymentConfigs *deployapi.DeploymentConfigList
+	BuildConfigs      *buildapi.BuildConfigList
+}
+
+// Plan describes the changes which prune the given resources.
+type Plan struct {
+	// Streams are the image streams whose tag history was trimmed.
+	Streams []*imageapi.ImageStream
+	// Images are the images which are no longer referenced.
+	Images []*imageapi.Image
+	// Layers maps the digest of each layer which is referenced only by pruned
+	// images to the repositories it was pushed to.
+	Layers map[string][]string
+}
+
+// NewPlan determines which tag history entries, images and registry layers
+// can be pruned from resources. Images are kept if they are recorded in the
+// remaining tag history of any image stream, or if they are used by a pod,
+// replication controller, deployment config or build config.
+func NewPlan(resources *Resources, options Options, now time.Time) *Plan {
+	plan := &Plan{Layers: map[string][]string{}}
+
+	registries := options.Registries
+	if registries == nil {
+		registries = util.NewStringSet()
+	}
+	refs := newReferences()
+	repositories := map[string]util.StringSet{}
+
+	for i := range resources.Streams.Items {
+		stream := &resources.Streams.Items[i]
+		repository := stream.Namespace + "/" + stream.Name
+		trimmed := false
+		tags := map[string]imageapi.TagEventList{}
+		for tag, history := range stream.Status.Tags {
+			kept := []imageapi.TagEvent{}
+			for j, event := range history.Items {
+				if _, ok := repositories[event.Image]; !ok {
+					repositories[event.Image] = util.NewStringSet()
+				}
+				repositories[event.Image].Insert(repository)
+
+				if j > 0 && pruneTagEvent(event, j, options, now) {
+					glog.V(4).Infof("Pruning %s from the history of %s:%s", event.Image, repository, tag)
+					trimmed = true
+					continue
+				}
+				kept = append(kept, event)
+				refs.addImage(event.Image)
+			}
+			tags[tag] = imageapi.TagEventList{Items: kept}
+		}
+		if trimmed {
+			updated := *stream
+			updated.Status.Tags = tags
+			plan.Streams = append(plan.Streams, &updated)
+		}
+	}
+
+	if resources.Pods != nil {
+		for _, pod := range resources.Pods.Items {
+			refs.addPodSpec(&pod.Spec)
+		}
+	}
+	if resources.Controllers != nil {
+		for _, rc := range resources.Controllers.Items {
+			if rc.Spec.Template != nil {
+				refs.addPodSpec(&rc.Spec.Template.Spec)
+			}
+		}
+	}
+	if resources.DeploymentConfigs != nil {
+		for _, config := range resources.DeploymentConfigs.Items {
+			if config.Template.ControllerTemplate.Template != nil {
+				refs.addPodSpec(&config.Template.ControllerTemplate.Template.Spec)
+			}
+			for _, trigger := range config.Triggers {
+				if trigger.ImageChangeParams != nil {
+					refs.addPullSpec(trigger.ImageChangeParams.LastTriggeredImage)
+				}
+			}
+		}
+	}
+	if resources.BuildConfigs != nil {
+		for _, config := range resources.BuildConfigs.Items {
+			strategy := config.Parameters.Strategy
+			if strategy.DockerStrategy != nil {
+				refs.addPullSpec(strategy.DockerStrategy.Image)
+			}
+			if strategy.STIStrategy != nil {
+				refs.addPullSpec(strategy.STIStrategy.Image)
+			}
+			if strategy.CustomStrategy != nil {
+				refs.addPullSpec(strategy.CustomStrategy.Image)
+			}
+			for _, trigger := range config.Triggers {
+				if trigger.ImageChange != nil {
+					refs.addPullSpec(trigger.ImageChange.LastTriggeredImageID)
+				}
+			}
+		}
+	}
+
+	// layers used by images which are kept must not be pruned
+	keptLayers := util.NewStringSet()
+	prunedLayers := map[string]util.StringSet{}
+	for i := range resources.Images.Items {
+		image := &resources.Images.Items[i]
+		layers := imageLayers(image)
+
+		if refs.has(image) || now.Sub(image.CreationTimestamp.Time) < options.KeepYoungerThan {
+			keptLayers.Insert(layers...)
+			continue
+		}
+		plan.Images = append(plan.Images, image)
+
+		ref, err := imageapi.ParseDockerImageReference(image.DockerImageReference)
+		if err != nil || !registries.Has(ref.Registry) {
+			continue
+		}
+		repos := util.NewStringSet()
+		if streams, ok := repositories[image.Name]; ok {
+			repos.Insert(streams.List()...)
+		}
+		if len(ref.Namespace) > 0 {
+			repos.Insert(ref.Namespace + "/" + ref.Name)
+		}
+		for _, layer := range layers {
+			if _, ok := prunedLayers[layer]; !ok {
+				prunedLayers[layer] = util.NewStringSet()
+			}
+			prunedLayers[layer].Insert(repos.List()...)
+		}
+	}
+	for layer, repos := range prunedLayers {
+		if keptLayers.Has(layer) {
+			continue
+		}
+		plan.Layers[layer] = repos.List()
+	}
+
+	sort.Sort(imagesByName(plan.Images))
+	return plan
+}
+
+// StreamRegistries returns the hosts of the registries which the image streams
+// in list are stored in.
+func StreamRegistries(list *imageapi.ImageStreamList) util.StringSet {
+	registries := util.NewStringSet()
+	for i := range list.Items {
+		if ref, err := imageapi.DockerImageReferenceForStream(&list.Items[i]); err == nil && len(ref.Registry) > 0 {
+			registries.Insert(ref.Registry)
+		}
+	}
+	return registries
+}
+
+// pruneTagEvent returns true if event, at position index of a tag history,
+// should be removed from the history.
+func pruneTagEvent(event imageapi.TagEvent, index int, options Options, now time.Time) bool {
+	if index >= options.KeepTagRevisions {
+		return true
+	}
+	return options.MaxTagAge > 0 && !event.Created.IsZero() && now.Sub(event.Created.Time) > options.MaxTagAge
+}
+
+// imageLayers returns the digests of the layers listed in the manifest of image.
+func imageLayers(image *imageapi.Image) []string {
+	if len(image.DockerImageManifest) == 0 {
+		return nil
+	}
+	manifest := imageapi.DockerImageManifest{}
+	if err := json.Unmarshal([]byte(image.DockerImageManifest), &manifest); err != nil {
+		glog.V(2).Infof("Unable to read the manifest of image %s: %v", image.Name, err)
+		return nil
+	}
+	layers := []string{}
+	for _, layer := range manifest.FSLayers {
+		layers = append(layers, layer.DockerBlobSum)
+	}
+	return layers
+}
+
+// hexID matches a tag which is the hex portion of an image digest, which is
+// how pulling by ID is simulated against a v2 registry.
+var hexID = regexp.MustCompile(`^[a-f0-9]{64}$`)
+
+// references records the images used by other resources.
+type references struct {
+	images    util.StringSet
+	pullSpecs util.StringSet
+}
+
+func newReferences() *references {
+	return &references{
+		images:    util.NewStringSet(),
+		pullSpecs: util.NewStringSet(),
+	}
+}
+
+func (r *references) addImage(name string) {
+	if len(name) > 0 {
+		r.images.Insert(name)
+	}
+}
+
+func (r *references) addPodSpec(spec *kapi.PodSpec) {
+	for _, container := range spec.Containers {
+		r.addPullSpec(container.Image)
+	}
+}
+
+func (r *references) addPullSpec(spec string) {
+	if len(spec) == 0 {
+		return
+	}
+	r.pullSpecs.Insert(spec)
+	ref, err := imageapi.ParseDockerImageReference(spec)
+	if err != nil {
+		return
+	}
+	r.addImage(ref.ID)
+	if hexID.MatchString(ref.Tag) {
+		r.addImage(ref.Tag)
+		r.addImage("sha256:" + ref.Tag)
+	}
+}
+
+func (r *references) has(image *imageapi.Image) bool {
+	return r.images.Has(image.Name) || r.pullSpecs.Has(image.DockerImageReference)
+}
+
+type imagesByName []*imageapi.Image
+
+func (s imagesByName) Len() int           { return len(s) }
+func (s imagesByName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s imagesByName) Less(i, j int) bool { return s[i].Name < s[j].Name }
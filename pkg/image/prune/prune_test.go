@@ -0,0 +1,170 @@
+package prune
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+)
+
+const registry = "registry:5000"
+
+var now = time.Date(2015, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+func imageID(n int) string {
+	return fmt.Sprintf("sha256:%064d", n)
+}
+
+func layerID(n int) string {
+	return fmt.Sprintf("sha256:%064d", 1000+n)
+}
+
+func image(n int, age time.Duration, layers ...int) imageapi.Image {
+	manifest := `{"schemaVersion":1,"fsLayers":[`
+	for i, layer := range layers {
+		if i > 0 {
+			manifest += ","
+		}
+		manifest += fmt.Sprintf(`{"blobSum":%q}`, layerID(layer))
+	}
+	manifest += `]}`
+	return imageapi.Image{
+		ObjectMeta: kapi.ObjectMeta{
+			Name:              imageID(n),
+			CreationTimestamp: util.NewTime(now.Add(-age)),
+		},
+		DockerImageReference: fmt.Sprintf("%s/ns/stream@%s", registry, imageID(n)),
+		DockerImageManifest:  manifest,
+	}
+}
+
+func stream(history ...int) imageapi.ImageStream {
+	events := []imageapi.TagEvent{}
+	for i, n := range history {
+		events = append(events, imageapi.TagEvent{
+			Created:              util.NewTime(now.Add(-time.Duration(i) * time.Hour)),
+			DockerImageReference: fmt.Sprintf("%s/ns/stream@%s", registry, imageID(n)),
+			Image:                imageID(n),
+		})
+	}
+	return imageapi.ImageStream{
+		ObjectMeta: kapi.ObjectMeta{Namespace: "ns", Name: "stream"},
+		Status: imageapi.ImageStreamStatus{
+			DockerImageRepository: registry + "/ns/stream",
+			Tags: map[string]imageapi.TagEventList{
+				"latest": {Items: events},
+			},
+		},
+	}
+}
+
+func imageNames(images []*imageapi.Image) []string {
+	names := []string{}
+	for _, image := range images {
+		names = append(names, image.Name)
+	}
+	return names
+}
+
+func TestNewPlan_trimsHistoryAndPrunesUnreferencedImages(t *testing.T) {
+	resources := &Resources{
+		Images: &imageapi.ImageList{Items: []imageapi.Image{
+			image(1, 3*time.Hour, 1, 2),
+			image(2, 3*time.Hour, 1, 3),
+			image(3, 3*time.Hour, 1, 4),
+		}},
+		Streams: &imageapi.ImageStreamList{Items: []imageapi.ImageStream{stream(3, 2, 1)}},
+	}
+	options := Options{KeepTagRevisions: 2, Registries: util.NewStringSet(registry)}
+
+	plan := NewPlan(resources, options, now)
+
+	if len(plan.Streams) != 1 {
+		t.Fatalf("expected one trimmed stream, got %d", len(plan.Streams))
+	}
+	if e, a := 2, len(plan.Streams[0].Status.Tags["latest"].Items); e != a {
+		t.Errorf("expected %d history entries, got %d", e, a)
+	}
+	if e, a := 3, len(resources.Streams.Items[0].Status.Tags["latest"].Items); e != a {
+		t.Errorf("expected the original stream to be unchanged with %d entries, got %d", e, a)
+	}
+
+	if e, a := []string{imageID(1)}, imageNames(plan.Images); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected pruned images %v, got %v", e, a)
+	}
+	// layer 1 is shared with images which are kept
+	expectedLayers := map[string][]string{layerID(2): {"ns/stream"}}
+	if !reflect.DeepEqual(expectedLayers, plan.Layers) {
+		t.Errorf("expected pruned layers %v, got %v", expectedLayers, plan.Layers)
+	}
+}
+
+func TestNewPlan_maxTagAge(t *testing.T) {
+	resources := &Resources{
+		Images:  &imageapi.ImageList{},
+		Streams: &imageapi.ImageStreamList{Items: []imageapi.ImageStream{stream(3, 2, 1)}},
+	}
+	options := Options{KeepTagRevisions: 5, MaxTagAge: 90 * time.Minute}
+
+	plan := NewPlan(resources, options, now)
+
+	if len(plan.Streams) != 1 {
+		t.Fatalf("expected one trimmed stream, got %d", len(plan.Streams))
+	}
+	items := plan.Streams[0].Status.Tags["latest"].Items
+	if len(items) != 2 || items[0].Image != imageID(3) || items[1].Image != imageID(2) {
+		t.Errorf("unexpected history after trimming: %#v", items)
+	}
+}
+
+func TestNewPlan_keepsReferencedImages(t *testing.T) {
+	rc := kapi.ReplicationController{
+		Spec: kapi.ReplicationControllerSpec{
+			Template: &kapi.PodTemplateSpec{
+				Spec: kapi.PodSpec{Containers: []kapi.Container{{Image: fmt.Sprintf("%s/ns/stream:%064d", registry, 2)}}},
+			},
+		},
+	}
+	config := deployapi.DeploymentConfig{
+		Triggers: []deployapi.DeploymentTriggerPolicy{{
+			Type: deployapi.DeploymentTriggerOnImageChange,
+			ImageChangeParams: &deployapi.DeploymentTriggerImageChangeParams{
+				LastTriggeredImage: fmt.Sprintf("%s/ns/stream@%s", registry, imageID(3)),
+			},
+		}},
+	}
+	resources := &Resources{
+		Images: &imageapi.ImageList{Items: []imageapi.Image{
+			image(1, 3*time.Hour),
+			image(2, 3*time.Hour),
+			image(3, 3*time.Hour),
+			image(4, 3*time.Hour),
+			image(5, time.Minute),
+		}},
+		Streams: &imageapi.ImageStreamList{Items: []imageapi.ImageStream{stream(1)}},
+		Pods: &kapi.PodList{Items: []kapi.Pod{{
+			Spec: kapi.PodSpec{Containers: []kapi.Container{{Image: "docker.io/library/other"}}},
+		}}},
+		Controllers:       &kapi.ReplicationControllerList{Items: []kapi.ReplicationController{rc}},
+		DeploymentConfigs: &deployapi.DeploymentConfigList{Items: []deployapi.DeploymentConfig{config}},
+	}
+	options := Options{KeepTagRevisions: 1, KeepYoungerThan: time.Hour}
+
+	plan := NewPlan(resources, options, now)
+
+	if len(plan.Streams) != 0 {
+		t.Errorf("expected no trimmed streams, got %d", len(plan.Streams))
+	}
+	if e, a := []string{imageID(4)}, imageNames(plan.Images); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected pruned images %v, got %v", e, a)
+	}
+	if len(plan.Layers) != 0 {
+		t.Errorf("expected no layers outside of the pruned registries, got %v", plan.Layers)
+	}
+}
@@ -1,6 +1,7 @@
 package image
 
 import (
+	"encoding/json"
 	"fmt"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
@@ -9,6 +10,9 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/generic"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/fielderrors"
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/manifest"
+	"github.com/golang/glog"
 
 	"github.com/openshift/origin/pkg/image/api"
 	"github.com/openshift/origin/pkg/image/api/validation"
@@ -30,7 +34,52 @@ func (imageStrategy) NamespaceScoped() bool {
 }
 
 // PrepareForCreate clears fields that are not allowed to be set by end users on creation.
+// The signers of the image are set to the keys whose signatures of its manifest are valid,
+// if the manifest is the one of the image.
 func (imageStrategy) PrepareForCreate(obj runtime.Object) {
+	image := obj.(*api.Image)
+	signers, err := manifestSigners(image.Name, image.DockerImageManifest)
+	if err != nil {
+		glog.V(4).Infof("Unable to verify the signatures of image %s: %v", image.Name, err)
+	}
+	image.DockerImageSigners = signers
+}
+
+// manifestSigners returns the IDs of the keys which signed the manifest, or nil if the
+// manifest is not signed. An error is returned if a signature is invalid, or if the digest
+// of the manifest is not name, so the signed manifest of an image cannot be attached to
+// another one.
+func manifestSigners(name, data string) ([]string, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	signed := &manifest.SignedManifest{}
+	if err := json.Unmarshal([]byte(data), signed); err != nil {
+		return nil, err
+	}
+	if signatures, err := signed.Signatures(); err != nil || len(signatures) == 0 {
+		return nil, nil
+	}
+	keys, err := manifest.Verify(signed)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := signed.Payload()
+	if err != nil {
+		return nil, err
+	}
+	dgst, err := digest.FromBytes(payload)
+	if err != nil {
+		return nil, err
+	}
+	if dgst.String() != name {
+		return nil, fmt.Errorf("the digest of the manifest is %s", dgst)
+	}
+	signers := []string{}
+	for _, key := range keys {
+		signers = append(signers, key.KeyID())
+	}
+	return signers, nil
 }
 
 // Validate validates a new image.
@@ -0,0 +1,77 @@
+package image
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/manifest"
+	"github.com/docker/libtrust"
+
+	"github.com/openshift/origin/pkg/image/api"
+)
+
+func TestPrepareForCreateSetsSigners(t *testing.T) {
+	key, err := libtrust.GenerateECP256PrivateKey()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	m := &manifest.Manifest{
+		Versioned: manifest.Versioned{SchemaVersion: 1},
+		Name:      "ns/app",
+		Tag:       "latest",
+		FSLayers:  []manifest.FSLayer{{BlobSum: "sha256:a3ed95caeb02ffe68cdd9fd84406680ae93d633cb16422d00e8a7c22955b46d4"}},
+		History:   []manifest.History{{V1Compatibility: `{"id":"layer"}`}},
+	}
+	signed, err := manifest.Sign(m, key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	payload, err := signed.Payload()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dgst, err := digest.FromBytes(payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	image := &api.Image{
+		ObjectMeta:          kapi.ObjectMeta{Name: dgst.String()},
+		DockerImageManifest: string(signed.Raw),
+		DockerImageSigners:  []string{"FORGED"},
+	}
+	Strategy.PrepareForCreate(image)
+	if e, a := []string{key.KeyID()}, image.DockerImageSigners; !reflect.DeepEqual(e, a) {
+		t.Errorf("expected signers %v, got %v", e, a)
+	}
+
+	image = &api.Image{DockerImageSigners: []string{"FORGED"}}
+	Strategy.PrepareForCreate(image)
+	if image.DockerImageSigners != nil {
+		t.Errorf("expected no signers for an image without a manifest, got %v", image.DockerImageSigners)
+	}
+
+	image = &api.Image{
+		ObjectMeta:          kapi.ObjectMeta{Name: "sha256:0000000000000000000000000000000000000000000000000000000000000000"},
+		DockerImageManifest: string(signed.Raw),
+		DockerImageSigners:  []string{"FORGED"},
+	}
+	Strategy.PrepareForCreate(image)
+	if image.DockerImageSigners != nil {
+		t.Errorf("expected no signers for the signed manifest of another image, got %v", image.DockerImageSigners)
+	}
+
+	image = &api.Image{
+		ObjectMeta:          kapi.ObjectMeta{Name: dgst.String()},
+		DockerImageManifest: strings.Replace(string(signed.Raw), `"latest"`, `"forged"`, 1),
+		DockerImageSigners:  []string{"FORGED"},
+	}
+	Strategy.PrepareForCreate(image)
+	if image.DockerImageSigners != nil {
+		t.Errorf("expected no signers for a manifest which was changed after it was signed, got %v", image.DockerImageSigners)
+	}
+}
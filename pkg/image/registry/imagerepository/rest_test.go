@@ -55,7 +55,7 @@ func newTestHelpers(t *testing.T) (*tools.FakeEtcdClient, tools.EtcdHelper, *RES
 	fakeEtcdClient := tools.NewFakeEtcdClient(t)
 	fakeEtcdClient.TestIndex = true
 	helper := tools.NewEtcdHelper(fakeEtcdClient, latest.Codec)
-	imageStreamStorage, imageStreamStatusStorage := imagestreametcd.NewREST(helper, testDefaultRegistry, &fakeSubjectAccessReviewRegistry{})
+	imageStreamStorage, imageStreamStatusStorage := imagestreametcd.NewREST(helper, testDefaultRegistry, &fakeSubjectAccessReviewRegistry{}, 0)
 	imageStreamRegistry := imagestream.NewRegistry(imageStreamStorage, imageStreamStatusStorage)
 	storage, statusStorage := NewREST(imageStreamRegistry)
 	return fakeEtcdClient, helper, storage, statusStorage
@@ -19,8 +19,9 @@ type REST struct {
 	subjectAccessReviewRegistry subjectaccessreview.Registry
 }
 
-// NewREST returns a new REST.
-func NewREST(h tools.EtcdHelper, defaultRegistry imagestream.DefaultRegistry, subjectAccessReviewRegistry subjectaccessreview.Registry) (*REST, *StatusREST) {
+// NewREST returns a new REST. The history of each image stream tag is limited to
+// maxTagHistory images unless the stream sets its own limit; zero keeps every image.
+func NewREST(h tools.EtcdHelper, defaultRegistry imagestream.DefaultRegistry, subjectAccessReviewRegistry subjectaccessreview.Registry, maxTagHistory int) (*REST, *StatusREST) {
 	//TODO change to imageStreams at release time
 	prefix := "/imageRepositories"
 	store := etcdgeneric.Etcd{
@@ -41,7 +42,7 @@ func NewREST(h tools.EtcdHelper, defaultRegistry imagestream.DefaultRegistry, su
 		Helper:              h,
 	}
 
-	strategy := imagestream.NewStrategy(defaultRegistry, subjectAccessReviewRegistry)
+	strategy := imagestream.NewStrategy(defaultRegistry, subjectAccessReviewRegistry, maxTagHistory)
 	rest := &REST{subjectAccessReviewRegistry: subjectAccessReviewRegistry}
 	strategy.ImageStreamGetter = rest
 
@@ -57,7 +57,7 @@ func validNewStream() *api.ImageStream {
 
 func TestCreate(t *testing.T) {
 	_, helper := newHelper(t)
-	storage, _ := NewREST(helper, noDefaultRegistry, &fakeSubjectAccessReviewRegistry{})
+	storage, _ := NewREST(helper, noDefaultRegistry, &fakeSubjectAccessReviewRegistry{}, 0)
 	stream := validNewStream()
 	ctx := kapi.WithUser(kapi.NewDefaultContext(), &fakeUser{})
 	_, err := storage.Create(ctx, stream)
@@ -69,7 +69,7 @@ func TestCreate(t *testing.T) {
 func TestGetImageStreamError(t *testing.T) {
 	fakeEtcdClient, helper := newHelper(t)
 	fakeEtcdClient.Err = fmt.Errorf("foo")
-	storage, _ := NewREST(helper, noDefaultRegistry, &fakeSubjectAccessReviewRegistry{})
+	storage, _ := NewREST(helper, noDefaultRegistry, &fakeSubjectAccessReviewRegistry{}, 0)
 
 	image, err := storage.Get(kapi.NewDefaultContext(), "image1")
 	if image != nil {
@@ -82,7 +82,7 @@ func TestGetImageStreamError(t *testing.T) {
 
 func TestGetImageStreamOK(t *testing.T) {
 	fakeEtcdClient, helper := newHelper(t)
-	storage, _ := NewREST(helper, noDefaultRegistry, &fakeSubjectAccessReviewRegistry{})
+	storage, _ := NewREST(helper, noDefaultRegistry, &fakeSubjectAccessReviewRegistry{}, 0)
 
 	ctx := kapi.NewDefaultContext()
 	repoName := "foo"
@@ -105,7 +105,7 @@ func TestGetImageStreamOK(t *testing.T) {
 func TestListImageStreamsError(t *testing.T) {
 	fakeEtcdClient, helper := newHelper(t)
 	fakeEtcdClient.Err = fmt.Errorf("foo")
-	storage, _ := NewREST(helper, noDefaultRegistry, &fakeSubjectAccessReviewRegistry{})
+	storage, _ := NewREST(helper, noDefaultRegistry, &fakeSubjectAccessReviewRegistry{}, 0)
 
 	imageStreams, err := storage.List(kapi.NewDefaultContext(), nil, nil)
 	if err != fakeEtcdClient.Err {
@@ -124,7 +124,7 @@ func TestListImageStreamsEmptyList(t *testing.T) {
 		R: &etcd.Response{},
 		E: fakeEtcdClient.NewError(tools.EtcdErrorCodeNotFound),
 	}
-	storage, _ := NewREST(helper, noDefaultRegistry, &fakeSubjectAccessReviewRegistry{})
+	storage, _ := NewREST(helper, noDefaultRegistry, &fakeSubjectAccessReviewRegistry{}, 0)
 
 	imageStreams, err := storage.List(kapi.NewDefaultContext(), labels.Everything(), fields.Everything())
 	if err != nil {
@@ -140,7 +140,7 @@ func TestListImageStreamsEmptyList(t *testing.T) {
 
 func TestListImageStreamsPopulatedList(t *testing.T) {
 	fakeEtcdClient, helper := newHelper(t)
-	storage, _ := NewREST(helper, noDefaultRegistry, &fakeSubjectAccessReviewRegistry{})
+	storage, _ := NewREST(helper, noDefaultRegistry, &fakeSubjectAccessReviewRegistry{}, 0)
 
 	fakeEtcdClient.Data["/imageRepositories/default"] = tools.EtcdResponseWithError{
 		R: &etcd.Response{
@@ -184,7 +184,7 @@ func (u *fakeUser) GetGroups() []string {
 
 func TestCreateImageStreamOK(t *testing.T) {
 	_, helper := newHelper(t)
-	storage, _ := NewREST(helper, noDefaultRegistry, &fakeSubjectAccessReviewRegistry{})
+	storage, _ := NewREST(helper, noDefaultRegistry, &fakeSubjectAccessReviewRegistry{}, 0)
 
 	stream := &api.ImageStream{ObjectMeta: kapi.ObjectMeta{Name: "foo"}}
 	ctx := kapi.WithUser(kapi.NewDefaultContext(), &fakeUser{})
@@ -241,7 +241,7 @@ func TestCreateImageStreamSpecTagsFromSet(t *testing.T) {
 		sarRegistry := &fakeSubjectAccessReviewRegistry{
 			allow: test.sarAllowed,
 		}
-		storage, _ := NewREST(helper, noDefaultRegistry, sarRegistry)
+		storage, _ := NewREST(helper, noDefaultRegistry, sarRegistry, 0)
 
 		otherNamespace := test.otherNamespace
 		if len(otherNamespace) == 0 {
@@ -316,7 +316,7 @@ func TestCreateImageStreamSpecTagsFromSet(t *testing.T) {
 func TestCreateRegistryErrorSaving(t *testing.T) {
 	fakeEtcdClient, helper := newHelper(t)
 	fakeEtcdClient.Err = fmt.Errorf("foo")
-	storage, _ := NewREST(helper, noDefaultRegistry, &fakeSubjectAccessReviewRegistry{})
+	storage, _ := NewREST(helper, noDefaultRegistry, &fakeSubjectAccessReviewRegistry{}, 0)
 
 	ctx := kapi.WithUser(kapi.NewDefaultContext(), &fakeUser{})
 	_, err := storage.Create(ctx, &api.ImageStream{ObjectMeta: kapi.ObjectMeta{Name: "foo"}})
@@ -327,7 +327,7 @@ func TestCreateRegistryErrorSaving(t *testing.T) {
 
 func TestUpdateImageStreamMissingID(t *testing.T) {
 	_, helper := newHelper(t)
-	storage, _ := NewREST(helper, noDefaultRegistry, &fakeSubjectAccessReviewRegistry{})
+	storage, _ := NewREST(helper, noDefaultRegistry, &fakeSubjectAccessReviewRegistry{}, 0)
 
 	obj, created, err := storage.Update(kapi.NewDefaultContext(), &api.ImageStream{})
 	if obj != nil || created {
@@ -341,7 +341,7 @@ func TestUpdateImageStreamMissingID(t *testing.T) {
 func TestUpdateRegistryErrorSaving(t *testing.T) {
 	fakeEtcdClient, helper := newHelper(t)
 	fakeEtcdClient.Err = fmt.Errorf("foo")
-	storage, _ := NewREST(helper, noDefaultRegistry, &fakeSubjectAccessReviewRegistry{})
+	storage, _ := NewREST(helper, noDefaultRegistry, &fakeSubjectAccessReviewRegistry{}, 0)
 
 	_, created, err := storage.Update(kapi.NewDefaultContext(), &api.ImageStream{ObjectMeta: kapi.ObjectMeta{Name: "bar"}})
 	if err != fakeEtcdClient.Err || created {
@@ -361,7 +361,7 @@ func TestUpdateImageStreamOK(t *testing.T) {
 			},
 		},
 	}
-	storage, _ := NewREST(helper, noDefaultRegistry, &fakeSubjectAccessReviewRegistry{})
+	storage, _ := NewREST(helper, noDefaultRegistry, &fakeSubjectAccessReviewRegistry{}, 0)
 
 	ctx := kapi.WithUser(kapi.NewDefaultContext(), &fakeUser{})
 	obj, created, err := storage.Update(ctx, &api.ImageStream{ObjectMeta: kapi.ObjectMeta{Name: "bar", ResourceVersion: "1"}})
@@ -411,7 +411,7 @@ func TestUpdateImageStreamSpecTagsFromSet(t *testing.T) {
 		sarRegistry := &fakeSubjectAccessReviewRegistry{
 			allow: test.sarAllowed,
 		}
-		storage, _ := NewREST(helper, noDefaultRegistry, sarRegistry)
+		storage, _ := NewREST(helper, noDefaultRegistry, sarRegistry, 0)
 
 		fakeEtcdClient.Data["/imageRepositories/default/foo"] = tools.EtcdResponseWithError{
 			R: &etcd.Response{
@@ -506,7 +506,7 @@ func TestDeleteImageStream(t *testing.T) {
 			},
 		},
 	}
-	storage, _ := NewREST(helper, noDefaultRegistry, &fakeSubjectAccessReviewRegistry{})
+	storage, _ := NewREST(helper, noDefaultRegistry, &fakeSubjectAccessReviewRegistry{}, 0)
 
 	obj, err := storage.Delete(kapi.NewDefaultContext(), "foo", nil)
 	if err != nil {
@@ -533,7 +533,7 @@ func TestUpdateImageStreamConflictingNamespace(t *testing.T) {
 			},
 		},
 	}
-	storage, _ := NewREST(helper, noDefaultRegistry, &fakeSubjectAccessReviewRegistry{})
+	storage, _ := NewREST(helper, noDefaultRegistry, &fakeSubjectAccessReviewRegistry{}, 0)
 
 	ctx := kapi.WithUser(kapi.WithNamespace(kapi.NewContext(), "legal-name"), &fakeUser{})
 	obj, created, err := storage.Update(ctx, &api.ImageStream{
@@ -1066,9 +1066,9 @@ func (fakeStrategy) PrepareForUpdate(obj, old runtime.Object) {
 
 func TestStrategyPrepareMethods(t *testing.T) {
 	_, helper := newHelper(t)
-	storage, _ := NewREST(helper, testDefaultRegistry, &fakeSubjectAccessReviewRegistry{})
+	storage, _ := NewREST(helper, testDefaultRegistry, &fakeSubjectAccessReviewRegistry{}, 0)
 	stream := validNewStream()
-	strategy := fakeStrategy{imagestream.NewStrategy(testDefaultRegistry, &fakeSubjectAccessReviewRegistry{})}
+	strategy := fakeStrategy{imagestream.NewStrategy(testDefaultRegistry, &fakeSubjectAccessReviewRegistry{}, 0)}
 
 	storage.store.CreateStrategy = strategy
 	storage.store.UpdateStrategy = strategy
@@ -3,6 +3,7 @@ package imagestream
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
@@ -32,17 +33,21 @@ type Strategy struct {
 	kapi.NameGenerator
 	defaultRegistry   DefaultRegistry
 	tagVerifier       *TagVerifier
+	maxTagHistory     int
 	ImageStreamGetter ResourceGetter
 }
 
 // Strategy is the default logic that applies when creating and updating
-// ImageStream objects via the REST API.
-func NewStrategy(defaultRegistry DefaultRegistry, subjectAccessReviewClient subjectaccessreview.Registry) Strategy {
+// ImageStream objects via the REST API. The history of each tag is limited
+// to maxTagHistory images, unless the stream sets its own limit; zero keeps
+// every image.
+func NewStrategy(defaultRegistry DefaultRegistry, subjectAccessReviewClient subjectaccessreview.Registry, maxTagHistory int) Strategy {
 	return Strategy{
 		ObjectTyper:     kapi.Scheme,
 		NameGenerator:   kapi.SimpleNameGenerator,
 		defaultRegistry: defaultRegistry,
 		tagVerifier:     &TagVerifier{subjectAccessReviewClient},
+		maxTagHistory:   maxTagHistory,
 	}
 }
 
@@ -125,7 +130,9 @@ func (s Strategy) tagsChanged(old, stream *api.ImageStream) fielderrors.Validati
 				errs = append(errs, fielderrors.NewFieldInvalid(fmt.Sprintf("spec.tags[%s].dockerImageReference", tag), tagRef.DockerImageReference, err.Error()))
 				continue
 			}
-			api.AddTagEventToImageStream(stream, tag, *event)
+			if api.AddTagEventToImageStream(stream, tag, *event) {
+				api.UpdateTrackingTags(stream, tag, *event)
+			}
 			continue
 		}
 
@@ -164,9 +171,13 @@ func (s Strategy) tagsChanged(old, stream *api.ImageStream) fielderrors.Validati
 			continue
 		}
 
-		api.AddTagEventToImageStream(stream, tag, *event)
+		if api.AddTagEventToImageStream(stream, tag, *event) {
+			api.UpdateTrackingTags(stream, tag, *event)
+		}
 	}
 
+	s.limitTagHistory(stream)
+
 	// use a consistent timestamp on creation
 	if old == nil && !stream.CreationTimestamp.IsZero() {
 		for tag, list := range stream.Status.Tags {
@@ -294,6 +305,20 @@ func (s Strategy) PrepareForUpdate(obj, old runtime.Object) {
 
 	stream.Status = oldStream.Status
 	stream.Status.DockerImageRepository = s.dockerImageRepository(stream)
+	s.limitTagHistory(stream)
+}
+
+// limitTagHistory drops the oldest images from the history of each tag of stream,
+// keeping the number set by the MaxTagHistoryAnnotation of the stream, or else the
+// default of the strategy.
+func (s Strategy) limitTagHistory(stream *api.ImageStream) {
+	max := s.maxTagHistory
+	if value, ok := stream.Annotations[api.MaxTagHistoryAnnotation]; ok {
+		if n, err := strconv.Atoi(value); err == nil && n > 0 {
+			max = n
+		}
+	}
+	api.LimitTagHistory(stream, max)
 }
 
 // ValidateUpdate is the default update validation for an end user.
@@ -331,7 +356,9 @@ func NewStatusStrategy(strategy Strategy) StatusStrategy {
 	return StatusStrategy{strategy}
 }
 
-func (StatusStrategy) PrepareForUpdate(obj, old runtime.Object) {
+// PrepareForUpdate limits the history of each tag of the updated stream.
+func (s StatusStrategy) PrepareForUpdate(obj, old runtime.Object) {
+	s.limitTagHistory(obj.(*api.ImageStream))
 }
 
 func (StatusStrategy) ValidateUpdate(ctx kapi.Context, obj, old runtime.Object) fielderrors.ValidationErrorList {
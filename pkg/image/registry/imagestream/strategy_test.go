@@ -87,7 +87,7 @@ func TestDockerImageRepository(t *testing.T) {
 	}
 
 	for testName, test := range tests {
-		strategy := NewStrategy(&fakeDefaultRegistry{test.defaultRegistry}, &fakeSubjectAccessReviewRegistry{})
+		strategy := NewStrategy(&fakeDefaultRegistry{test.defaultRegistry}, &fakeSubjectAccessReviewRegistry{}, 0)
 		value := strategy.dockerImageRepository(test.stream)
 		if e, a := test.expected, value; e != a {
 			t.Errorf("%s: expected %q, got %q", testName, e, a)
@@ -801,3 +801,70 @@ func TestTagRefChanged(t *testing.T) {
 		}
 	}
 }
+
+func TestLimitTagHistory(t *testing.T) {
+	history := func(n int) api.TagEventList {
+		list := api.TagEventList{}
+		for i := 0; i < n; i++ {
+			list.Items = append(list.Items, api.TagEvent{Image: fmt.Sprintf("image%d", i)})
+		}
+		return list
+	}
+
+	tests := map[string]struct {
+		maxTagHistory int
+		annotations   map[string]string
+		expected      int
+	}{
+		"no limit": {
+			expected: 5,
+		},
+		"default limit": {
+			maxTagHistory: 3,
+			expected:      3,
+		},
+		"annotation overrides the default": {
+			maxTagHistory: 3,
+			annotations:   map[string]string{api.MaxTagHistoryAnnotation: "2"},
+			expected:      2,
+		},
+		"annotation without a default": {
+			annotations: map[string]string{api.MaxTagHistoryAnnotation: "4"},
+			expected:    4,
+		},
+		"invalid annotation uses the default": {
+			maxTagHistory: 3,
+			annotations:   map[string]string{api.MaxTagHistoryAnnotation: "none"},
+			expected:      3,
+		},
+	}
+
+	for name, test := range tests {
+		s := NewStrategy(&fakeDefaultRegistry{}, &fakeSubjectAccessReviewRegistry{}, test.maxTagHistory)
+		for _, strategy := range []interface {
+			PrepareForUpdate(obj, old runtime.Object)
+		}{s, NewStatusStrategy(s)} {
+			status := api.ImageStreamStatus{
+				Tags: map[string]api.TagEventList{"latest": history(5), "short": history(1)},
+			}
+			old := &api.ImageStream{Status: status}
+			stream := &api.ImageStream{Status: status}
+			stream.Annotations = test.annotations
+			strategy.PrepareForUpdate(stream, old)
+
+			if e, a := 5, len(old.Status.Tags["latest"].Items); e != a {
+				t.Errorf("%s: expected the old stream to be unchanged, got %d history items", name, a)
+			}
+
+			if e, a := test.expected, len(stream.Status.Tags["latest"].Items); e != a {
+				t.Errorf("%s: expected %d history items, got %d", name, e, a)
+			}
+			if e, a := "image0", stream.Status.Tags["latest"].Items[0].Image; e != a {
+				t.Errorf("%s: expected the newest image %s to remain, got %s", name, e, a)
+			}
+			if e, a := 1, len(stream.Status.Tags["short"].Items); e != a {
+				t.Errorf("%s: expected %d history items, got %d", name, e, a)
+			}
+		}
+	}
+}
@@ -90,6 +90,7 @@ func (s *REST) Create(ctx kapi.Context, obj runtime.Object) (runtime.Object, err
 		Image:                image.Name,
 	}
 	if api.AddTagEventToImageStream(stream, tag, next) {
+		api.UpdateTrackingTags(stream, tag, next)
 		if _, err := s.imageStreamRegistry.UpdateImageStreamStatus(ctx, stream); err != nil {
 			return nil, err
 		}
@@ -14,7 +14,8 @@ import (
 )
 
 // REST implements the RESTStorage interface for ImageStreamTag
-// It only supports the Get method and is used to simplify retrieving an Image by tag from an ImageStream
+// It is used to simplify retrieving an Image by tag from an ImageStream, and to set
+// a single spec tag of an ImageStream
 type REST struct {
 	imageRegistry       image.Registry
 	imageStreamRegistry imagestream.Registry
@@ -65,6 +66,11 @@ func (r *REST) Get(ctx kapi.Context, id string) (runtime.Object, error) {
 		return nil, errors.NewNotFound("imageStreamTag", tag)
 	}
 
+	// a tag set from an external pull spec has no image until it is imported
+	if len(event.Image) == 0 {
+		return nil, errors.NewNotFound("image", event.DockerImageReference)
+	}
+
 	image, err := r.imageRegistry.GetImage(ctx, event.Image)
 	if err != nil {
 		return nil, err
@@ -90,14 +96,102 @@ func (r *REST) Get(ctx kapi.Context, id string) (runtime.Object, error) {
 	ist := api.ImageStreamTag{
 		Image: *imageWithMetadata,
 	}
+	if tagRef, ok := stream.Spec.Tags[tag]; ok {
+		ist.Tag = &tagRef
+	}
 	ist.Namespace = kapi.NamespaceValue(ctx)
 	ist.Name = id
 	return &ist, nil
 }
 
+// Create sets a new tag on a stream from the tag reference of the ImageStreamTag,
+// creating the stream if it does not exist. It is an error if the tag is already set.
+func (r *REST) Create(ctx kapi.Context, obj runtime.Object) (runtime.Object, error) {
+	ist := obj.(*api.ImageStreamTag)
+	name, tag, err := nameAndTag(ist.Name)
+	if err != nil {
+		return nil, err
+	}
+	if ist.Tag == nil {
+		return nil, errors.NewBadRequest("imageStreamTags must be created with a tag reference")
+	}
+
+	stream, err := r.imageStreamRegistry.GetImageStream(ctx, name)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+		stream = &api.ImageStream{ObjectMeta: kapi.ObjectMeta{Name: name}}
+		stream.Spec.Tags = map[string]api.TagReference{tag: *ist.Tag}
+		if _, err := r.imageStreamRegistry.CreateImageStream(ctx, stream); err != nil {
+			return nil, err
+		}
+		return r.tagged(ctx, ist.Name, ist.Tag)
+	}
+
+	if _, ok := stream.Spec.Tags[tag]; ok {
+		return nil, errors.NewAlreadyExists("imageStreamTag", ist.Name)
+	}
+	if stream.Spec.Tags == nil {
+		stream.Spec.Tags = make(map[string]api.TagReference)
+	}
+	stream.Spec.Tags[tag] = *ist.Tag
+	if _, err := r.imageStreamRegistry.UpdateImageStream(ctx, stream); err != nil {
+		return nil, err
+	}
+	return r.tagged(ctx, ist.Name, ist.Tag)
+}
+
+// Update sets a tag on a stream from the tag reference of the ImageStreamTag. The
+// tag and the stream are created if they do not exist.
+func (r *REST) Update(ctx kapi.Context, obj runtime.Object) (runtime.Object, bool, error) {
+	ist := obj.(*api.ImageStreamTag)
+	name, tag, err := nameAndTag(ist.Name)
+	if err != nil {
+		return nil, false, err
+	}
+	if ist.Tag == nil {
+		return nil, false, errors.NewBadRequest("imageStreamTags must be updated with a tag reference")
+	}
+
+	stream, err := r.imageStreamRegistry.GetImageStream(ctx, name)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, false, err
+		}
+		created, err := r.Create(ctx, ist)
+		return created, true, err
+	}
+
+	_, exists := stream.Spec.Tags[tag]
+	if stream.Spec.Tags == nil {
+		stream.Spec.Tags = make(map[string]api.TagReference)
+	}
+	stream.Spec.Tags[tag] = *ist.Tag
+	if _, err := r.imageStreamRegistry.UpdateImageStream(ctx, stream); err != nil {
+		return nil, false, err
+	}
+	updated, err := r.tagged(ctx, ist.Name, ist.Tag)
+	return updated, !exists, err
+}
+
+// tagged returns the ImageStreamTag id after its tag has been set. A tag which does
+// not resolve to an image known to the server, such as one set from an external pull
+// spec, is returned with only its tag reference.
+func (r *REST) tagged(ctx kapi.Context, id string, tagRef *api.TagReference) (runtime.Object, error) {
+	obj, err := r.Get(ctx, id)
+	if err == nil || !errors.IsNotFound(err) {
+		return obj, err
+	}
+	ist := &api.ImageStreamTag{Tag: tagRef}
+	ist.Namespace = kapi.NamespaceValue(ctx)
+	ist.Name = id
+	return ist, nil
+}
+
 // Delete removes a tag from a stream. `id` is of the format <stream name>:<tag>.
 // The associated image that the tag points to is *not* deleted.
-// The tag history remains intact and is not deleted.
+// Both the spec tag and the tag history are removed.
 func (r *REST) Delete(ctx kapi.Context, id string) (runtime.Object, error) {
 	name, tag, err := nameAndTag(id)
 	if err != nil {
@@ -109,20 +203,26 @@ func (r *REST) Delete(ctx kapi.Context, id string) (runtime.Object, error) {
 		return nil, err
 	}
 
-	if stream.Spec.Tags == nil {
+	_, inSpec := stream.Spec.Tags[tag]
+	_, inStatus := stream.Status.Tags[tag]
+	if !inSpec && !inStatus {
 		return nil, errors.NewNotFound("imageStreamTag", tag)
 	}
 
-	_, ok := stream.Spec.Tags[tag]
-	if !ok {
-		return nil, errors.NewNotFound("imageStreamTag", tag)
+	if inSpec {
+		delete(stream.Spec.Tags, tag)
+		stream, err = r.imageStreamRegistry.UpdateImageStream(ctx, stream)
+		if err != nil {
+			return nil, fmt.Errorf("Error removing tag from image stream: %s", err)
+		}
 	}
 
-	delete(stream.Spec.Tags, tag)
-
-	_, err = r.imageStreamRegistry.UpdateImageStream(ctx, stream)
-	if err != nil {
-		return nil, fmt.Errorf("Error removing tag from image stream: %s", err)
+	// the history of the tag is kept when the spec tag is removed, so it is removed separately
+	if _, ok := stream.Status.Tags[tag]; ok {
+		delete(stream.Status.Tags, tag)
+		if _, err := r.imageStreamRegistry.UpdateImageStreamStatus(ctx, stream); err != nil {
+			return nil, fmt.Errorf("Error removing tag history from image stream: %s", err)
+		}
 	}
 
 	return &kapi.Status{Status: kapi.StatusSuccess}, nil
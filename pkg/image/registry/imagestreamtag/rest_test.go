@@ -55,7 +55,7 @@ func setup(t *testing.T) (*tools.FakeEtcdClient, tools.EtcdHelper, *REST) {
 	helper := tools.NewEtcdHelper(fakeEtcdClient, latest.Codec)
 	imageStorage := imageetcd.NewREST(helper)
 	imageRegistry := image.NewRegistry(imageStorage)
-	imageStreamStorage, imageStreamStatus := imagestreametcd.NewREST(helper, testDefaultRegistry, &fakeSubjectAccessReviewRegistry{})
+	imageStreamStorage, imageStreamStatus := imagestreametcd.NewREST(helper, testDefaultRegistry, &fakeSubjectAccessReviewRegistry{}, 0)
 	imageStreamRegistry := imagestream.NewRegistry(imageStreamStorage, imageStreamStatus)
 	storage := NewREST(imageRegistry, imageStreamRegistry)
 	return fakeEtcdClient, helper, storage
@@ -281,6 +281,44 @@ func TestDeleteImageStreamTag(t *testing.T) {
 			},
 			expectError: true,
 		},
+		"pushed tag without a spec tag": {
+			repo: &api.ImageStream{
+				ObjectMeta: kapi.ObjectMeta{
+					Namespace: "default",
+					Name:      "test",
+				},
+				Spec: api.ImageStreamSpec{
+					Tags: map[string]api.TagReference{
+						"another": {
+							From: &kapi.ObjectReference{
+								Kind: "ImageStreamTag",
+								Name: "test:foo",
+							},
+						},
+					},
+				},
+				Status: api.ImageStreamStatus{
+					Tags: map[string]api.TagEventList{
+						"another": {
+							Items: []api.TagEvent{
+								{
+									DockerImageReference: "registry.default.local/default/test@sha256:381151ac5b7f775e8371e489f3479b84a4c004c90ceddb2ad80b6877215a892f",
+									Image:                "sha256:381151ac5b7f775e8371e489f3479b84a4c004c90ceddb2ad80b6877215a892f",
+								},
+							},
+						},
+						"latest": {
+							Items: []api.TagEvent{
+								{
+									DockerImageReference: "registry.default.local/default/test@sha256:381151ac5b7f775e8371e489f3479b84a4c004c90ceddb2ad80b6877215a892f",
+									Image:                "sha256:381151ac5b7f775e8371e489f3479b84a4c004c90ceddb2ad80b6877215a892f",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		"happy path": {
 			repo: &api.ImageStream{
 				ObjectMeta: kapi.ObjectMeta{
@@ -397,5 +435,107 @@ func TestDeleteImageStreamTag(t *testing.T) {
 		if e, a := expected, updatedRepo.Spec.Tags; !reflect.DeepEqual(e, a) {
 			t.Errorf("%s: tags: expected %v, got %v", name, e, a)
 		}
+		if _, ok := updatedRepo.Status.Tags["latest"]; ok {
+			t.Errorf("%s: expected the tag history to be removed: %v", name, updatedRepo.Status.Tags)
+		}
+		if _, ok := updatedRepo.Status.Tags["another"]; !ok {
+			t.Errorf("%s: expected the history of other tags to remain: %v", name, updatedRepo.Status.Tags)
+		}
+	}
+}
+
+func TestCreateImageStreamTag(t *testing.T) {
+	fakeEtcdClient, helper, storage := setup(t)
+	image := &api.Image{ObjectMeta: kapi.ObjectMeta{Name: "10"}, DockerImageReference: "registry.default.local/default/test@10"}
+	fakeEtcdClient.Data["/images/10"] = tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			Node: &etcd.Node{
+				Value:         runtime.EncodeOrDie(latest.Codec, image),
+				ModifiedIndex: 1,
+			},
+		},
+	}
+	repo := &api.ImageStream{
+		ObjectMeta: kapi.ObjectMeta{Namespace: "default", Name: "test"},
+		Status: api.ImageStreamStatus{
+			DockerImageRepository: "registry.default.local/default/test",
+			Tags: map[string]api.TagEventList{
+				"latest": {Items: []api.TagEvent{{DockerImageReference: "registry.default.local/default/test@10", Image: "10"}}},
+			},
+		},
+	}
+	fakeEtcdClient.Data["/imageRepositories/default/test"] = tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			Node: &etcd.Node{
+				Value:         runtime.EncodeOrDie(latest.Codec, repo),
+				ModifiedIndex: 1,
+			},
+		},
+	}
+
+	ctx := kapi.WithUser(kapi.NewDefaultContext(), &fakeUser{})
+	ist := &api.ImageStreamTag{
+		Image: api.Image{ObjectMeta: kapi.ObjectMeta{Name: "test:stable"}},
+		Tag:   &api.TagReference{From: &kapi.ObjectReference{Kind: "ImageStreamTag", Name: "test:latest"}},
+	}
+	obj, err := storage.Create(ctx, ist)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual := obj.(*api.ImageStreamTag); actual.Name != "test:stable" || actual.DockerImageReference != image.DockerImageReference || actual.Tag == nil {
+		t.Errorf("unexpected image stream tag: %#v", actual)
+	}
+
+	updatedRepo := &api.ImageStream{}
+	if err := helper.ExtractObj("/imageRepositories/default/test", updatedRepo, false); err != nil {
+		t.Fatalf("error retrieving updated repo: %s", err)
+	}
+	if e, a := *ist.Tag, updatedRepo.Spec.Tags["stable"]; !reflect.DeepEqual(e, a) {
+		t.Errorf("expected spec tag %#v, got %#v", e, a)
+	}
+	if event, err := api.LatestTaggedImage(updatedRepo, "stable"); err != nil || event.Image != "10" {
+		t.Errorf("expected the tag to point to image 10, got %#v: %v", event, err)
+	}
+
+	if _, err := storage.Create(ctx, ist); !errors.IsAlreadyExists(err) {
+		t.Errorf("expected an already exists error, got %v", err)
+	}
+}
+
+func TestUpdateImageStreamTagCreatesStream(t *testing.T) {
+	fakeEtcdClient, helper, storage := setup(t)
+	fakeEtcdClient.Data["/imageRepositories/default/test"] = tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			Node: nil,
+		},
+		E: tools.EtcdErrorNotFound,
+	}
+
+	ctx := kapi.WithUser(kapi.NewDefaultContext(), &fakeUser{})
+	ist := &api.ImageStreamTag{
+		Image: api.Image{ObjectMeta: kapi.ObjectMeta{Name: "test:latest"}},
+		Tag:   &api.TagReference{DockerImageReference: "docker.io/library/mysql:5.6"},
+	}
+	obj, created, err := storage.Update(ctx, ist)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !created {
+		t.Errorf("expected the tag to be created")
+	}
+	if actual := obj.(*api.ImageStreamTag); actual.Name != "test:latest" || actual.Tag == nil {
+		t.Errorf("unexpected image stream tag: %#v", actual)
+	}
+
+	updatedRepo := &api.ImageStream{}
+	if err := helper.ExtractObj("/imageRepositories/default/test", updatedRepo, false); err != nil {
+		t.Fatalf("error retrieving created repo: %s", err)
+	}
+	if event, err := api.LatestTaggedImage(updatedRepo, "latest"); err != nil || event.DockerImageReference != "docker.io/library/mysql:5.6" {
+		t.Errorf("expected the tag to point to the pull spec, got %#v: %v", event, err)
+	}
+
+	if _, _, err := storage.Update(ctx, &api.ImageStreamTag{Image: api.Image{ObjectMeta: kapi.ObjectMeta{Name: "test:latest"}}}); !errors.IsBadRequest(err) {
+		t.Errorf("expected a bad request error without a tag reference, got %v", err)
 	}
 }
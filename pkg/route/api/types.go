@@ -2,6 +2,7 @@ package api
 
 import (
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 )
 
 // Route encapsulates the inputs needed to connect an alias to endpoints.
@@ -21,6 +22,15 @@ type Route struct {
 
 	//TLS provides the ability to configure certificates and termination for the route
 	TLS *TLSConfig `json:"tls,omitempty"`
+
+	// WildcardPolicy is the wildcard policy for the route, None if not set. A Subdomain
+	// policy routes every host in the subdomain of Host to the service, if the router
+	// allows wildcard routes.
+	WildcardPolicy WildcardPolicyType `json:"wildcardPolicy,omitempty"`
+
+	// Status describes the routers which have admitted the route, and is nil until a
+	// router reported on it
+	Status *RouteStatus `json:"status,omitempty"`
 }
 
 // RouteList is a collection of Routes.
@@ -30,6 +40,48 @@ type RouteList struct {
 	Items         []Route `json:"items"`
 }
 
+// RouteStatus provides relevant info about the status of a route, including which routers
+// acknowledge it.
+type RouteStatus struct {
+	// Ingress describes the places where the route may be exposed. Each router records
+	// whether it admitted the route under its own name.
+	Ingress []RouteIngress `json:"ingress,omitempty"`
+}
+
+// RouteIngress holds information about the places where a route is exposed.
+type RouteIngress struct {
+	// Host is the host string under which the route is exposed
+	Host string `json:"host,omitempty"`
+	// RouterName is the name the router identifies itself with
+	RouterName string `json:"routerName,omitempty"`
+	// Conditions is the state of the route on the router, may be empty
+	Conditions []RouteIngressCondition `json:"conditions,omitempty"`
+}
+
+// RouteIngressConditionType is a type of condition of a route on a router.
+type RouteIngressConditionType string
+
+// These are the valid conditions of a route on a router.
+const (
+	// RouteAdmitted means the route is able to service requests for the provided Host
+	RouteAdmitted RouteIngressConditionType = "Admitted"
+)
+
+// RouteIngressCondition contains details for the current condition of this route on a
+// particular router.
+type RouteIngressCondition struct {
+	// Type of the condition, currently only Admitted
+	Type RouteIngressConditionType `json:"type"`
+	// Status of the condition, one of True, False, Unknown
+	Status kapi.ConditionStatus `json:"status"`
+	// A brief machine readable explanation for the condition
+	Reason string `json:"reason,omitempty"`
+	// A human readable description of the condition
+	Message string `json:"message,omitempty"`
+	// The last time the condition changed
+	LastTransitionTime util.Time `json:"lastTransitionTime,omitempty"`
+}
+
 // RouterShard has information of a routing shard and is used to
 // generate host names and routing table entries when a routing shard is
 // allocated for a specific route.
@@ -59,6 +111,11 @@ type TLSConfig struct {
 	// DestinationCACertificate provides the contents of the ca certificate of the final destination.  When using reencrypt
 	// termination this file should be provided in order to have routers use it for health checks on the secure connection
 	DestinationCACertificate string `json:"destinationCACertificate,omitempty"`
+
+	// InsecureEdgeTerminationPolicy indicates what the router does with insecure connections to an edge
+	// terminated route: Allow serves them, Redirect redirects them to the secure port, and None, the default,
+	// does not serve them
+	InsecureEdgeTerminationPolicy InsecureEdgeTerminationPolicyType `json:"insecureEdgeTerminationPolicy,omitempty"`
 }
 
 // TLSTerminationType dictates where the secure communication will stop
@@ -72,3 +129,53 @@ const (
 	// TLSTerminationReencrypt terminate encryption at the edge router and re-encrypt it with a new certificate supplied by the destination
 	TLSTerminationReencrypt TLSTerminationType = "reencrypt"
 )
+
+// InsecureEdgeTerminationPolicyType dictates the behavior of insecure connections to an edge terminated route
+type InsecureEdgeTerminationPolicyType string
+
+const (
+	// InsecureEdgeTerminationPolicyNone does not serve insecure connections
+	InsecureEdgeTerminationPolicyNone InsecureEdgeTerminationPolicyType = "None"
+	// InsecureEdgeTerminationPolicyAllow serves insecure connections like secure ones
+	InsecureEdgeTerminationPolicyAllow InsecureEdgeTerminationPolicyType = "Allow"
+	// InsecureEdgeTerminationPolicyRedirect redirects insecure connections to the secure port
+	InsecureEdgeTerminationPolicyRedirect InsecureEdgeTerminationPolicyType = "Redirect"
+)
+
+// WildcardPolicyType indicates the type of wildcard support needed by routes.
+type WildcardPolicyType string
+
+const (
+	// WildcardPolicyNone indicates no wildcard support is needed; the route only serves its host
+	WildcardPolicyNone WildcardPolicyType = "None"
+	// WildcardPolicySubdomain indicates the route serves every host in the subdomain of its host,
+	// i.e. a route for www.apps.example.com serves *.apps.example.com
+	WildcardPolicySubdomain WildcardPolicyType = "Subdomain"
+)
+
+// These annotations of a route tune how routers serve it.
+const (
+	// RouteTimeoutAnnotation is the time the router waits for a response from the endpoints of the
+	// route, as a number followed by one of the units us, ms, s, m, h or d, such as 5s
+	RouteTimeoutAnnotation = "haproxy.router.openshift.io/timeout"
+	// RouteBalanceAnnotation is the algorithm the router balances connections to the endpoints of
+	// the route with, one of the RouteBalance values
+	RouteBalanceAnnotation = "haproxy.router.openshift.io/balance"
+	// RouteStickySessionsAnnotation, when "true", makes the router set a cookie sending the further
+	// requests of a client to the same endpoint. Cookies require the router to terminate TLS, so
+	// they are not supported by passthrough routes.
+	RouteStickySessionsAnnotation = "haproxy.router.openshift.io/sticky-sessions"
+	// RouteHSTSAnnotation is the Strict-Transport-Security header the router adds to the responses
+	// of an edge or reencrypt route, such as max-age=31536000;includeSubDomains;preload
+	RouteHSTSAnnotation = "haproxy.router.openshift.io/hsts_header"
+)
+
+// These are the valid values of RouteBalanceAnnotation.
+const (
+	// RouteBalanceRoundRobin uses each endpoint in turn
+	RouteBalanceRoundRobin = "roundrobin"
+	// RouteBalanceLeastConn uses the endpoint with the fewest connections, the default
+	RouteBalanceLeastConn = "leastconn"
+	// RouteBalanceSource uses the same endpoint for the connections of a client address
+	RouteBalanceSource = "source"
+)
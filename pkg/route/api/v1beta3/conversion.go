@@ -25,6 +25,7 @@ func init() {
 
 			out.Path = in.Spec.Path
 			out.Host = in.Spec.Host
+			out.WildcardPolicy = newer.WildcardPolicyType(in.Spec.WildcardPolicy)
 			if in.Spec.To.Kind == "Service" || len(in.Spec.To.Kind) == 0 {
 				out.ServiceName = in.Spec.To.Name
 			}
@@ -37,6 +38,7 @@ func init() {
 
 			out.Spec.Path = in.Path
 			out.Spec.Host = in.Host
+			out.Spec.WildcardPolicy = WildcardPolicyType(in.WildcardPolicy)
 			out.Spec.To.Kind = "Service"
 			out.Spec.To.Name = in.ServiceName
 			return s.Convert(&in.TLS, &out.Spec.TLS, 0)
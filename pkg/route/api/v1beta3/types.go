@@ -2,6 +2,7 @@ package v1beta3
 
 import (
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 )
 
 // Route encapsulates the inputs needed to connect an alias to endpoints.
@@ -9,8 +10,8 @@ type Route struct {
 	kapi.TypeMeta   `json:",inline"`
 	kapi.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec   RouteSpec   `json:"spec"`
-	Status RouteStatus `json:"status"`
+	Spec   RouteSpec    `json:"spec"`
+	Status *RouteStatus `json:"status,omitempty"`
 }
 
 // RouteList is a collection of Routes.
@@ -38,6 +39,11 @@ type RouteSpec struct {
 
 	// TLS provides the ability to configure certificates and termination for the route
 	TLS *TLSConfig `json:"tls,omitempty"`
+
+	// WildcardPolicy is the wildcard policy for the route, None if not set. A Subdomain
+	// policy routes every host in the subdomain of Host to the service, if the router
+	// allows wildcard routes.
+	WildcardPolicy WildcardPolicyType `json:"wildcardPolicy,omitempty"`
 }
 
 /*
@@ -56,13 +62,52 @@ type RoutePort struct {
 */
 
 // RouteStatus describes the current state of this route.
-type RouteStatus struct{}
+type RouteStatus struct {
+	// Ingress describes the places where the route may be exposed. Each router records
+	// whether it admitted the route under its own name.
+	Ingress []RouteIngress `json:"ingress,omitempty"`
+}
+
+// RouteIngress holds information about the places where a route is exposed.
+type RouteIngress struct {
+	// Host is the host string under which the route is exposed
+	Host string `json:"host,omitempty"`
+	// RouterName is the name the router identifies itself with
+	RouterName string `json:"routerName,omitempty"`
+	// Conditions is the state of the route on the router, may be empty
+	Conditions []RouteIngressCondition `json:"conditions,omitempty"`
+}
+
+// RouteIngressConditionType is a type of condition of a route on a router.
+type RouteIngressConditionType string
+
+// These are the valid conditions of a route on a router.
+const (
+	// RouteAdmitted means the route is able to service requests for the provided Host
+	RouteAdmitted RouteIngressConditionType = "Admitted"
+)
+
+// RouteIngressCondition contains details for the current condition of this route on a
+// particular router.
+type RouteIngressCondition struct {
+	// Type of the condition, currently only Admitted
+	Type RouteIngressConditionType `json:"type"`
+	// Status of the condition, one of True, False, Unknown
+	Status kapi.ConditionStatus `json:"status"`
+	// A brief machine readable explanation for the condition
+	Reason string `json:"reason,omitempty"`
+	// A human readable description of the condition
+	Message string `json:"message,omitempty"`
+	// The last time the condition changed
+	LastTransitionTime util.Time `json:"lastTransitionTime,omitempty"`
+}
 
 // RouterShard has information of a routing shard and is used to
 // generate host names and routing table entries when a routing shard is
 // allocated for a specific route.
 // Caveat: This is WIP and will likely undergo modifications when sharding
-//         support is added.
+//
+//	support is added.
 type RouterShard struct {
 	// Shard name uniquely identifies a router shard in the "set" of
 	// routers used for routing traffic to the services.
@@ -89,6 +134,11 @@ type TLSConfig struct {
 	// DestinationCACertificate provides the contents of the ca certificate of the final destination.  When using reencrypt
 	// termination this file should be provided in order to have routers use it for health checks on the secure connection
 	DestinationCACertificate string `json:"destinationCACertificate,omitempty"`
+
+	// InsecureEdgeTerminationPolicy indicates what the router does with insecure connections to an edge
+	// terminated route: Allow serves them, Redirect redirects them to the secure port, and None, the default,
+	// does not serve them
+	InsecureEdgeTerminationPolicy InsecureEdgeTerminationPolicyType `json:"insecureEdgeTerminationPolicy,omitempty"`
 }
 
 // TLSTerminationType dictates where the secure communication will stop
@@ -102,3 +152,26 @@ const (
 	// TLSTerminationReencrypt terminate encryption at the edge router and re-encrypt it with a new certificate supplied by the destination
 	TLSTerminationReencrypt TLSTerminationType = "reencrypt"
 )
+
+// InsecureEdgeTerminationPolicyType dictates the behavior of insecure connections to an edge terminated route
+type InsecureEdgeTerminationPolicyType string
+
+const (
+	// InsecureEdgeTerminationPolicyNone does not serve insecure connections
+	InsecureEdgeTerminationPolicyNone InsecureEdgeTerminationPolicyType = "None"
+	// InsecureEdgeTerminationPolicyAllow serves insecure connections like secure ones
+	InsecureEdgeTerminationPolicyAllow InsecureEdgeTerminationPolicyType = "Allow"
+	// InsecureEdgeTerminationPolicyRedirect redirects insecure connections to the secure port
+	InsecureEdgeTerminationPolicyRedirect InsecureEdgeTerminationPolicyType = "Redirect"
+)
+
+// WildcardPolicyType indicates the type of wildcard support needed by routes.
+type WildcardPolicyType string
+
+const (
+	// WildcardPolicyNone indicates no wildcard support is needed; the route only serves its host
+	WildcardPolicyNone WildcardPolicyType = "None"
+	// WildcardPolicySubdomain indicates the route serves every host in the subdomain of its host,
+	// i.e. a route for www.apps.example.com serves *.apps.example.com
+	WildcardPolicySubdomain WildcardPolicyType = "Subdomain"
+)
@@ -1,7 +1,13 @@
 package validation
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"regexp"
 	"strings"
+	"unicode"
 
 	kval "github.com/GoogleCloudPlatform/kubernetes/pkg/api/validation"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
@@ -25,8 +31,30 @@ func ValidateRoute(route *routeapi.Route) fielderrors.ValidationErrorList {
 		}
 	}
 
-	if len(route.Path) > 0 && !strings.HasPrefix(route.Path, "/") {
-		result = append(result, fielderrors.NewFieldInvalid("path", route.Path, "Path must begin with /"))
+	if len(route.Path) > 0 {
+		if !strings.HasPrefix(route.Path, "/") {
+			result = append(result, fielderrors.NewFieldInvalid("path", route.Path, "Path must begin with /"))
+		}
+		// router map files separate the route from its backend with whitespace
+		if strings.IndexFunc(route.Path, unicode.IsSpace) != -1 {
+			result = append(result, fielderrors.NewFieldInvalid("path", route.Path, "Path must not contain whitespace"))
+		}
+		// the path of a request is encrypted when the router does not terminate TLS
+		if route.TLS != nil && route.TLS.Termination == routeapi.TLSTerminationPassthrough {
+			result = append(result, fielderrors.NewFieldInvalid("path", route.Path, "passthrough termination does not support paths"))
+		}
+	}
+
+	switch route.WildcardPolicy {
+	case "", routeapi.WildcardPolicyNone:
+	case routeapi.WildcardPolicySubdomain:
+		// the wildcard covers the domain of the host, which must not be a top level domain
+		if parts := strings.SplitN(route.Host, ".", 2); len(parts) != 2 || !strings.Contains(parts[1], ".") {
+			result = append(result, fielderrors.NewFieldInvalid("host", route.Host, "a route with a Subdomain wildcard policy requires a host with a subdomain of at least two labels, such as www.apps.example.com"))
+		}
+	default:
+		msg := fmt.Sprintf("invalid value for wildcardPolicy, acceptable values are %s, %s, or empty", routeapi.WildcardPolicyNone, routeapi.WildcardPolicySubdomain)
+		result = append(result, fielderrors.NewFieldInvalid("wildcardPolicy", route.WildcardPolicy, msg))
 	}
 
 	if len(route.ServiceName) == 0 {
@@ -35,11 +63,83 @@ func ValidateRoute(route *routeapi.Route) fielderrors.ValidationErrorList {
 
 	if errs := validateTLS(route.TLS); len(errs) != 0 {
 		result = append(result, errs.Prefix("tls")...)
+	} else {
+		result = append(result, ValidateRouteCertificates(route)...)
+	}
+
+	result = append(result, validateRouteAnnotations(route)...)
+
+	return result
+}
+
+// timeoutPattern matches the durations of HAProxy: a number with an optional unit, milliseconds by default
+var timeoutPattern = regexp.MustCompile(`^[1-9][0-9]*(us|ms|s|m|h|d)?$`)
+
+// hstsMaxAgePattern matches the max-age directive of a Strict-Transport-Security header
+var hstsMaxAgePattern = regexp.MustCompile(`^(?i:max-age)=[0-9]+$`)
+
+// validateRouteAnnotations tests the annotations tuning how routers serve the route.  Called by
+// ValidateRoute.
+func validateRouteAnnotations(route *routeapi.Route) fielderrors.ValidationErrorList {
+	result := fielderrors.ValidationErrorList{}
+	termination := routeapi.TLSTerminationType("")
+	if route.TLS != nil {
+		termination = route.TLS.Termination
+	}
+
+	if value, ok := route.Annotations[routeapi.RouteTimeoutAnnotation]; ok && !timeoutPattern.MatchString(value) {
+		result = append(result, fielderrors.NewFieldInvalid("annotations", routeapi.RouteTimeoutAnnotation+"="+value, "the timeout must be a positive number with an optional unit of us, ms, s, m, h or d, such as 5s"))
+	}
+
+	if value, ok := route.Annotations[routeapi.RouteBalanceAnnotation]; ok {
+		switch value {
+		case routeapi.RouteBalanceRoundRobin, routeapi.RouteBalanceLeastConn, routeapi.RouteBalanceSource:
+		default:
+			msg := fmt.Sprintf("the balance algorithm must be %s, %s or %s", routeapi.RouteBalanceRoundRobin, routeapi.RouteBalanceLeastConn, routeapi.RouteBalanceSource)
+			result = append(result, fielderrors.NewFieldInvalid("annotations", routeapi.RouteBalanceAnnotation+"="+value, msg))
+		}
+	}
+
+	if value, ok := route.Annotations[routeapi.RouteStickySessionsAnnotation]; ok {
+		switch {
+		case value != "true" && value != "false":
+			result = append(result, fielderrors.NewFieldInvalid("annotations", routeapi.RouteStickySessionsAnnotation+"="+value, "sticky sessions must be true or false"))
+		case value == "true" && termination == routeapi.TLSTerminationPassthrough:
+			result = append(result, fielderrors.NewFieldInvalid("annotations", routeapi.RouteStickySessionsAnnotation+"="+value, "passthrough termination does not support sticky sessions"))
+		}
+	}
+
+	if value, ok := route.Annotations[routeapi.RouteHSTSAnnotation]; ok {
+		if termination != routeapi.TLSTerminationEdge && termination != routeapi.TLSTerminationReencrypt {
+			result = append(result, fielderrors.NewFieldInvalid("annotations", routeapi.RouteHSTSAnnotation+"="+value, "the Strict-Transport-Security header requires edge or reencrypt termination"))
+		} else if err := validateHSTSHeader(value); err != nil {
+			result = append(result, fielderrors.NewFieldInvalid("annotations", routeapi.RouteHSTSAnnotation+"="+value, err.Error()))
+		}
 	}
 
 	return result
 }
 
+// validateHSTSHeader tests the value of a Strict-Transport-Security header has a max-age
+// directive, optionally followed by the includeSubDomains and preload directives.
+func validateHSTSHeader(value string) error {
+	maxAge := false
+	for _, directive := range strings.Split(value, ";") {
+		directive = strings.TrimSpace(directive)
+		switch {
+		case hstsMaxAgePattern.MatchString(directive):
+			maxAge = true
+		case strings.EqualFold(directive, "includeSubDomains"), strings.EqualFold(directive, "preload"):
+		default:
+			return fmt.Errorf("unknown directive %q, the header accepts max-age=<seconds>, includeSubDomains and preload", directive)
+		}
+	}
+	if !maxAge {
+		return fmt.Errorf("the header requires a max-age=<seconds> directive")
+	}
+	return nil
+}
+
 // ValidateTLS tests fields for different types of TLS combinations are set.  Called
 // by ValidateRoute.
 func validateTLS(tls *routeapi.TLSConfig) fielderrors.ValidationErrorList {
@@ -50,6 +150,17 @@ func validateTLS(tls *routeapi.TLSConfig) fielderrors.ValidationErrorList {
 		return nil
 	}
 
+	switch tls.InsecureEdgeTerminationPolicy {
+	case "", routeapi.InsecureEdgeTerminationPolicyNone:
+	case routeapi.InsecureEdgeTerminationPolicyAllow, routeapi.InsecureEdgeTerminationPolicyRedirect:
+		if tls.Termination != routeapi.TLSTerminationEdge {
+			result = append(result, fielderrors.NewFieldInvalid("insecureEdgeTerminationPolicy", tls.InsecureEdgeTerminationPolicy, "only edge termination supports insecure connections"))
+		}
+	default:
+		msg := fmt.Sprintf("invalid value for insecureEdgeTerminationPolicy, acceptable values are %s, %s, %s, or empty", routeapi.InsecureEdgeTerminationPolicyNone, routeapi.InsecureEdgeTerminationPolicyAllow, routeapi.InsecureEdgeTerminationPolicyRedirect)
+		result = append(result, fielderrors.NewFieldInvalid("insecureEdgeTerminationPolicy", tls.InsecureEdgeTerminationPolicy, msg))
+	}
+
 	switch tls.Termination {
 	//reencrypt must specify cert, key, cacert, and destination ca cert
 	case routeapi.TLSTerminationReencrypt:
@@ -108,3 +219,95 @@ func validateTLS(tls *routeapi.TLSConfig) fielderrors.ValidationErrorList {
 	}
 	return result
 }
+
+// ValidateRouteCertificates tests the certificates and key of the route can be loaded by a router:
+// they must be PEM encoded, the key must match the certificate, and the certificate must cover the
+// host of the route.  Called by ValidateRoute, and by routers to skip the routes they cannot serve.
+func ValidateRouteCertificates(route *routeapi.Route) fielderrors.ValidationErrorList {
+	result := fielderrors.ValidationErrorList{}
+	tlsConfig := route.TLS
+	if tlsConfig == nil || tlsConfig.Termination == "" {
+		return nil
+	}
+
+	if len(tlsConfig.Certificate) > 0 {
+		if certs, err := parseCertificates(tlsConfig.Certificate); err != nil {
+			result = append(result, fielderrors.NewFieldInvalid("tls.certificate", "<certificate>", err.Error()))
+		} else {
+			if len(tlsConfig.Key) > 0 {
+				if _, err := tls.X509KeyPair([]byte(tlsConfig.Certificate), []byte(tlsConfig.Key)); err != nil {
+					result = append(result, fielderrors.NewFieldInvalid("tls.key", "<private key>", fmt.Sprintf("the key does not match the certificate: %v", err)))
+				}
+			}
+			if len(route.Host) > 0 && !certificateCoversHost(certs[0], route.Host) {
+				result = append(result, fielderrors.NewFieldInvalid("tls.certificate", "<certificate>", fmt.Sprintf("the certificate does not cover the host %s", route.Host)))
+			}
+		}
+	}
+
+	if len(tlsConfig.CACertificate) > 0 {
+		if _, err := parseCertificates(tlsConfig.CACertificate); err != nil {
+			result = append(result, fielderrors.NewFieldInvalid("tls.caCertificate", "<certificate>", err.Error()))
+		}
+	}
+
+	if len(tlsConfig.DestinationCACertificate) > 0 {
+		if _, err := parseCertificates(tlsConfig.DestinationCACertificate); err != nil {
+			result = append(result, fielderrors.NewFieldInvalid("tls.destinationCACertificate", "<certificate>", err.Error()))
+		}
+	}
+
+	return result
+}
+
+// parseCertificates parses the PEM encoded certificates of data, which must hold at least one.
+func parseCertificates(data string) ([]*x509.Certificate, error) {
+	certs := []*x509.Certificate{}
+	rest := []byte(data)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("expected PEM encoded certificates, found a %s", block.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the certificate: %v", err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("expected PEM encoded certificates")
+	}
+	return certs, nil
+}
+
+// certificateCoversHost returns true if the certificate is valid for host.  Like routers, which
+// select certificates by the server name of the TLS handshake, the common name of the certificate
+// is matched when it has no DNS subject alternative names.
+func certificateCoversHost(cert *x509.Certificate, host string) bool {
+	if cert.VerifyHostname(host) == nil {
+		return true
+	}
+	if len(cert.DNSNames) > 0 {
+		return false
+	}
+	return matchHostname(cert.Subject.CommonName, host)
+}
+
+// matchHostname returns true if host matches pattern, whose first label may be a wildcard matching
+// a single label of host.
+func matchHostname(pattern, host string) bool {
+	pattern, host = strings.ToLower(strings.TrimSuffix(pattern, ".")), strings.ToLower(strings.TrimSuffix(host, "."))
+	if len(pattern) == 0 {
+		return false
+	}
+	if !strings.HasPrefix(pattern, "*.") {
+		return pattern == host
+	}
+	parts := strings.SplitN(host, ".", 2)
+	return len(parts) == 2 && len(parts[0]) > 0 && "."+parts[1] == pattern[1:]
+}
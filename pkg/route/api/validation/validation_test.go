@@ -1,7 +1,15 @@
 package validation
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
 	"testing"
+	"time"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/openshift/origin/pkg/route/api"
@@ -109,6 +117,84 @@ func TestValidateRoute(t *testing.T) {
 			},
 			expectedErrors: 1,
 		},
+		{
+			name: "Path with whitespace",
+			route: &api.Route{
+				ObjectMeta: kapi.ObjectMeta{
+					Name:      "name",
+					Namespace: "foo",
+				},
+				Host:        "www.example.com",
+				Path:        "/test path",
+				ServiceName: "serviceName",
+			},
+			expectedErrors: 1,
+		},
+		{
+			name: "Passthrough route with path",
+			route: &api.Route{
+				ObjectMeta: kapi.ObjectMeta{
+					Name:      "name",
+					Namespace: "foo",
+				},
+				Host:        "www.example.com",
+				Path:        "/test",
+				ServiceName: "serviceName",
+				TLS:         &api.TLSConfig{Termination: api.TLSTerminationPassthrough},
+			},
+			expectedErrors: 1,
+		},
+		{
+			name: "Valid wildcard route",
+			route: &api.Route{
+				ObjectMeta: kapi.ObjectMeta{
+					Name:      "name",
+					Namespace: "foo",
+				},
+				Host:           "www.apps.example.com",
+				ServiceName:    "serviceName",
+				WildcardPolicy: api.WildcardPolicySubdomain,
+			},
+			expectedErrors: 0,
+		},
+		{
+			name: "Wildcard route without host",
+			route: &api.Route{
+				ObjectMeta: kapi.ObjectMeta{
+					Name:      "name",
+					Namespace: "foo",
+				},
+				ServiceName:    "serviceName",
+				WildcardPolicy: api.WildcardPolicySubdomain,
+			},
+			expectedErrors: 1,
+		},
+		{
+			name: "Wildcard route for a top level domain",
+			route: &api.Route{
+				ObjectMeta: kapi.ObjectMeta{
+					Name:      "name",
+					Namespace: "foo",
+				},
+				Host:           "example.com",
+				ServiceName:    "serviceName",
+				WildcardPolicy: api.WildcardPolicySubdomain,
+			},
+			expectedErrors: 1,
+		},
+		{
+			name: "Invalid wildcard policy",
+			route: &api.Route{
+				ObjectMeta: kapi.ObjectMeta{
+					Name:      "name",
+					Namespace: "foo",
+				},
+				Host:           "www.example.com",
+				ServiceName:    "serviceName",
+				WildcardPolicy: "All",
+			},
+			expectedErrors: 1,
+		},
 	}
 
 	for _, tc := range tests {
@@ -238,3 +324,147 @@ func TestValidateTLSInvalidTermination(t *testing.T) {
 		t.Errorf("Unexpected error list encountered: %#v.  Expected 1 errors, got %v", errs, len(errs))
 	}
 }
+
+func TestValidateInsecureEdgeTerminationPolicy(t *testing.T) {
+	testCases := []struct {
+		name           string
+		cfg            api.TLSConfig
+		expectedErrors int
+	}{
+		{"edge allow", api.TLSConfig{Termination: api.TLSTerminationEdge, Certificate: "abc", Key: "abc", CACertificate: "abc", InsecureEdgeTerminationPolicy: api.InsecureEdgeTerminationPolicyAllow}, 0},
+		{"edge redirect", api.TLSConfig{Termination: api.TLSTerminationEdge, Certificate: "abc", Key: "abc", CACertificate: "abc", InsecureEdgeTerminationPolicy: api.InsecureEdgeTerminationPolicyRedirect}, 0},
+		{"passthrough none", api.TLSConfig{Termination: api.TLSTerminationPassthrough, InsecureEdgeTerminationPolicy: api.InsecureEdgeTerminationPolicyNone}, 0},
+		{"passthrough allow", api.TLSConfig{Termination: api.TLSTerminationPassthrough, InsecureEdgeTerminationPolicy: api.InsecureEdgeTerminationPolicyAllow}, 1},
+		{"edge invalid", api.TLSConfig{Termination: api.TLSTerminationEdge, Certificate: "abc", Key: "abc", CACertificate: "abc", InsecureEdgeTerminationPolicy: "Sometimes"}, 1},
+	}
+
+	for _, tc := range testCases {
+		errs := validateTLS(&tc.cfg)
+
+		if len(errs) != tc.expectedErrors {
+			t.Errorf("Test case %s expected %d error(s), got %d. %v", tc.name, tc.expectedErrors, len(errs), errs)
+		}
+	}
+}
+
+func TestValidateRouteAnnotations(t *testing.T) {
+	cert, key := newCertificate(t, "www.example.com")
+	edge := &api.TLSConfig{Termination: api.TLSTerminationEdge, Certificate: cert, Key: key, CACertificate: cert}
+	passthrough := &api.TLSConfig{Termination: api.TLSTerminationPassthrough}
+
+	testCases := []struct {
+		name           string
+		annotations    map[string]string
+		tls            *api.TLSConfig
+		expectedErrors int
+	}{
+		{"valid", map[string]string{
+			api.RouteTimeoutAnnotation:        "90s",
+			api.RouteBalanceAnnotation:        "roundrobin",
+			api.RouteStickySessionsAnnotation: "true",
+			api.RouteHSTSAnnotation:           "max-age=31536000; includeSubDomains;preload",
+		}, edge, 0},
+		{"timeout without unit", map[string]string{api.RouteTimeoutAnnotation: "500"}, nil, 0},
+		{"invalid timeout", map[string]string{api.RouteTimeoutAnnotation: "5 seconds"}, nil, 1},
+		{"zero timeout", map[string]string{api.RouteTimeoutAnnotation: "0s"}, nil, 1},
+		{"invalid balance", map[string]string{api.RouteBalanceAnnotation: "random"}, nil, 1},
+		{"invalid sticky sessions", map[string]string{api.RouteStickySessionsAnnotation: "yes"}, nil, 1},
+		{"passthrough sticky sessions", map[string]string{api.RouteStickySessionsAnnotation: "true"}, passthrough, 1},
+		{"passthrough without sticky sessions", map[string]string{api.RouteStickySessionsAnnotation: "false"}, passthrough, 0},
+		{"insecure hsts", map[string]string{api.RouteHSTSAnnotation: "max-age=100"}, nil, 1},
+		{"hsts without max-age", map[string]string{api.RouteHSTSAnnotation: "includeSubDomains"}, edge, 1},
+		{"hsts with unknown directive", map[string]string{api.RouteHSTSAnnotation: "max-age=100;always"}, edge, 1},
+	}
+
+	for _, tc := range testCases {
+		route := &api.Route{
+			ObjectMeta: kapi.ObjectMeta{
+				Name:        "name",
+				Namespace:   "foo",
+				Annotations: tc.annotations,
+			},
+			Host:        "www.example.com",
+			ServiceName: "serviceName",
+			TLS:         tc.tls,
+		}
+		errs := ValidateRoute(route)
+
+		if len(errs) != tc.expectedErrors {
+			t.Errorf("Test case %s expected %d error(s), got %d. %v", tc.name, tc.expectedErrors, len(errs), errs)
+		}
+	}
+}
+
+// newCertificate returns a self-signed PEM encoded certificate and its key for the common name
+// and DNS subject alternative names.
+func newCertificate(t *testing.T, commonName string, dnsNames ...string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestValidateRouteCertificates(t *testing.T) {
+	cert, key := newCertificate(t, "www.example.com")
+	wildcardCert, wildcardKey := newCertificate(t, "ignored.example.com", "*.apps.example.com")
+	otherCert, otherKey := newCertificate(t, "other.example.com")
+
+	testCases := []struct {
+		name           string
+		host           string
+		tls            api.TLSConfig
+		expectedErrors int
+	}{
+		{"edge", "www.example.com", api.TLSConfig{Termination: api.TLSTerminationEdge, Certificate: cert, Key: key, CACertificate: otherCert}, 0},
+		{"no host", "", api.TLSConfig{Termination: api.TLSTerminationEdge, Certificate: cert, Key: key, CACertificate: cert}, 0},
+		{"wildcard certificate", "www.apps.example.com", api.TLSConfig{Termination: api.TLSTerminationEdge, Certificate: wildcardCert, Key: wildcardKey, CACertificate: cert}, 0},
+		{"chain", "www.example.com", api.TLSConfig{Termination: api.TLSTerminationEdge, Certificate: cert + otherCert, Key: key, CACertificate: cert + otherCert}, 0},
+		{"reencrypt", "www.example.com", api.TLSConfig{Termination: api.TLSTerminationReencrypt, Certificate: cert, Key: key, CACertificate: cert, DestinationCACertificate: otherCert}, 0},
+		{"passthrough", "www.example.com", api.TLSConfig{Termination: api.TLSTerminationPassthrough}, 0},
+
+		{"host not covered", "other.example.com", api.TLSConfig{Termination: api.TLSTerminationEdge, Certificate: cert, Key: key, CACertificate: cert}, 1},
+		{"common name ignored with alternative names", "ignored.example.com", api.TLSConfig{Termination: api.TLSTerminationEdge, Certificate: wildcardCert, Key: wildcardKey, CACertificate: cert}, 1},
+		{"wildcard covers a single label", "a.b.apps.example.com", api.TLSConfig{Termination: api.TLSTerminationEdge, Certificate: wildcardCert, Key: wildcardKey, CACertificate: cert}, 1},
+		{"key mismatch", "www.example.com", api.TLSConfig{Termination: api.TLSTerminationEdge, Certificate: cert, Key: otherKey, CACertificate: cert}, 1},
+		{"invalid key", "www.example.com", api.TLSConfig{Termination: api.TLSTerminationEdge, Certificate: cert, Key: "abc", CACertificate: cert}, 1},
+		{"key as certificate", "www.example.com", api.TLSConfig{Termination: api.TLSTerminationEdge, Certificate: key, Key: key, CACertificate: cert}, 1},
+		{"invalid certificate", "www.example.com", api.TLSConfig{Termination: api.TLSTerminationEdge, Certificate: "abc", Key: key, CACertificate: cert}, 1},
+		{"invalid ca certificate", "www.example.com", api.TLSConfig{Termination: api.TLSTerminationEdge, Certificate: cert, Key: key, CACertificate: "abc"}, 1},
+		{"invalid destination ca certificate", "www.example.com", api.TLSConfig{Termination: api.TLSTerminationReencrypt, Certificate: cert, Key: key, CACertificate: cert, DestinationCACertificate: "abc"}, 1},
+	}
+
+	for _, tc := range testCases {
+		tls := tc.tls
+		route := &api.Route{
+			ObjectMeta: kapi.ObjectMeta{
+				Name:      "name",
+				Namespace: "foo",
+			},
+			Host:        tc.host,
+			ServiceName: "serviceName",
+			TLS:         &tls,
+		}
+		errs := ValidateRoute(route)
+
+		if len(errs) != tc.expectedErrors {
+			t.Errorf("Test case %s expected %d error(s), got %d. %v", tc.name, tc.expectedErrors, len(errs), errs)
+		}
+	}
+}
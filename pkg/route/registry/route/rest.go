@@ -95,6 +95,8 @@ func (rs *REST) Create(ctx kapi.Context, obj runtime.Object) (runtime.Object, er
 	}
 
 	kapi.FillObjectMetaSystemFields(ctx, &route.ObjectMeta)
+	// only routers may set the status of a route
+	route.Status = nil
 
 	escapeNewLines(route.TLS)
 
@@ -122,9 +124,16 @@ func (rs *REST) Update(ctx kapi.Context, obj runtime.Object) (runtime.Object, bo
 		return nil, false, errors.NewInvalid("route", route.Name, errs)
 	}
 
+	// the status of a route is updated through the status subresource
+	old, err := rs.registry.GetRoute(ctx, route.Name)
+	if err != nil {
+		return nil, false, err
+	}
+	route.Status = old.Status
+
 	escapeNewLines(route.TLS)
 
-	err := rs.registry.UpdateRoute(ctx, route)
+	err = rs.registry.UpdateRoute(ctx, route)
 	if err != nil {
 		return nil, false, err
 	}
@@ -132,6 +141,47 @@ func (rs *REST) Update(ctx kapi.Context, obj runtime.Object) (runtime.Object, bo
 	return out, false, err
 }
 
+// StatusREST implements the status subresource of routes, which routers update to
+// record whether they admitted a route.
+type StatusREST struct {
+	registry Registry
+}
+
+// NewStatusREST returns a RESTStorage object for the status of routes.
+func NewStatusREST(registry Registry) *StatusREST {
+	return &StatusREST{registry: registry}
+}
+
+// New returns a new Route
+func (r *StatusREST) New() runtime.Object {
+	return &api.Route{}
+}
+
+// Update replaces the status of a Route. All other changes to the Route are ignored.
+func (r *StatusREST) Update(ctx kapi.Context, obj runtime.Object) (runtime.Object, bool, error) {
+	route, ok := obj.(*api.Route)
+	if !ok {
+		return nil, false, fmt.Errorf("not a route: %#v", obj)
+	}
+	if !kapi.ValidNamespace(ctx, &route.ObjectMeta) {
+		return nil, false, errors.NewConflict("route", route.Namespace, fmt.Errorf("Route.Namespace does not match the provided context"))
+	}
+
+	old, err := r.registry.GetRoute(ctx, route.Name)
+	if err != nil {
+		return nil, false, err
+	}
+	// the resource version of the route guards against concurrent updates
+	old.ResourceVersion = route.ResourceVersion
+	old.Status = route.Status
+
+	if err := r.registry.UpdateRoute(ctx, old); err != nil {
+		return nil, false, err
+	}
+	out, err := r.registry.GetRoute(ctx, route.Name)
+	return out, false, err
+}
+
 // Watch returns Routes events via a watch.Interface.
 // It implements apiserver.ResourceWatcher.
 func (rs *REST) Watch(ctx kapi.Context, label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error) {
@@ -2,6 +2,7 @@ package route
 
 import (
 	"net/http"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -346,3 +347,70 @@ func checkExpectedNamespaceError(t *testing.T, err error) {
 	}
 
 }
+
+func TestUpdateRoutePreservesStatus(t *testing.T) {
+	mockRegistry := test.NewRouteRegistry()
+	status := &api.RouteStatus{
+		Ingress: []api.RouteIngress{{RouterName: "router", Host: "www.frontend.com"}},
+	}
+	mockRegistry.Routes = &api.RouteList{
+		Items: []api.Route{
+			{
+				ObjectMeta:  kapi.ObjectMeta{Name: "bar"},
+				Host:        "www.frontend.com",
+				ServiceName: "rubyservice",
+				Status:      status,
+			},
+		},
+	}
+	storage := REST{
+		registry:  mockRegistry,
+		allocator: ractest.NewTestRouteAllocationController(),
+	}
+
+	obj, _, err := storage.Update(kapi.NewDefaultContext(), &api.Route{
+		ObjectMeta:  kapi.ObjectMeta{Name: "bar"},
+		Host:        "www.frontend.com",
+		ServiceName: "newrubyservice",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if route := obj.(*api.Route); !reflect.DeepEqual(route.Status, status) {
+		t.Errorf("Expected the status to be preserved, got %#v", route.Status)
+	}
+}
+
+func TestUpdateRouteStatus(t *testing.T) {
+	mockRegistry := test.NewRouteRegistry()
+	mockRegistry.Routes = &api.RouteList{
+		Items: []api.Route{
+			{
+				ObjectMeta:  kapi.ObjectMeta{Name: "bar"},
+				Host:        "www.frontend.com",
+				ServiceName: "rubyservice",
+			},
+		},
+	}
+	storage := NewStatusREST(mockRegistry)
+
+	status := &api.RouteStatus{
+		Ingress: []api.RouteIngress{{RouterName: "router", Host: "www.frontend.com"}},
+	}
+	obj, _, err := storage.Update(kapi.NewDefaultContext(), &api.Route{
+		ObjectMeta:  kapi.ObjectMeta{Name: "bar"},
+		Host:        "www.other.com",
+		ServiceName: "newrubyservice",
+		Status:      status,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	route := obj.(*api.Route)
+	if !reflect.DeepEqual(route.Status, status) {
+		t.Errorf("Expected the status to be updated, got %#v", route.Status)
+	}
+	if route.Host != "www.frontend.com" || route.ServiceName != "rubyservice" {
+		t.Errorf("Expected only the status to be updated, got %#v", route)
+	}
+}
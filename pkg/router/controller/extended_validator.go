@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"strings"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/golang/glog"
+
+	routeapi "github.com/openshift/origin/pkg/route/api"
+	"github.com/openshift/origin/pkg/route/api/validation"
+	"github.com/openshift/origin/pkg/router"
+)
+
+// ExtendedValidator implements the router.Plugin interface to skip the routes whose
+// certificates the router could not load, such as routes created before their certificates
+// were validated. Loading a bad certificate would fail the reload of the router for every
+// route, so the invalid routes are removed from the wrapped plugin and recorded as rejected.
+type ExtendedValidator struct {
+	plugin   router.Plugin
+	recorder RouteStatusRecorder
+}
+
+// NewExtendedValidator creates a plugin wrapper which only passes on the routes whose
+// certificates are valid.
+func NewExtendedValidator(plugin router.Plugin, recorder RouteStatusRecorder) *ExtendedValidator {
+	return &ExtendedValidator{plugin: plugin, recorder: recorder}
+}
+
+// HandleEndpoints passes endpoints on to the wrapped plugin.
+func (p *ExtendedValidator) HandleEndpoints(eventType watch.EventType, endpoints *kapi.Endpoints) error {
+	return p.plugin.HandleEndpoints(eventType, endpoints)
+}
+
+// HandleRoute passes the route on to the wrapped plugin if its certificates are valid, and
+// otherwise removes it from the wrapped plugin in case a valid version of it was served.
+func (p *ExtendedValidator) HandleRoute(eventType watch.EventType, route *routeapi.Route) error {
+	if eventType != watch.Deleted {
+		if errs := validation.ValidateRouteCertificates(route); len(errs) > 0 {
+			messages := []string{}
+			for _, err := range errs {
+				messages = append(messages, err.Error())
+			}
+			message := strings.Join(messages, ", ")
+			glog.Errorf("Skipping route %s/%s with invalid certificates: %s", route.Namespace, route.Name, message)
+			p.recorder.RecordRouteRejection(route, "ExtendedValidationFailed", message)
+			return p.plugin.HandleRoute(watch.Deleted, route)
+		}
+	}
+	return p.plugin.HandleRoute(eventType, route)
+}
@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+
+	routeapi "github.com/openshift/origin/pkg/route/api"
+)
+
+// newCertificate returns a self-signed PEM encoded certificate and its key for host.
+func newCertificate(t *testing.T, host string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: host},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestExtendedValidator(t *testing.T) {
+	plugin := &fakePlugin{routes: map[string]*routeapi.Route{}}
+	recorder := &fakeRecorder{admitted: map[string]bool{}, reasons: map[string]string{}}
+	p := NewExtendedValidator(plugin, recorder)
+
+	cert, key := newCertificate(t, "www.example.com")
+	route := newRoute("a", "secure", "www.example.com", 1)
+	route.TLS = &routeapi.TLSConfig{Termination: routeapi.TLSTerminationEdge, Certificate: cert, Key: key}
+	if err := p.HandleRoute(watch.Added, route); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, a := []string{"a/secure"}, served(plugin); !reflect.DeepEqual(e, a) {
+		t.Fatalf("expected routes %v to be served, got %v", e, a)
+	}
+
+	// the served route is removed once its certificate no longer covers its host
+	otherCert, otherKey := newCertificate(t, "other.example.com")
+	invalid := newRoute("a", "secure", "www.example.com", 1)
+	invalid.TLS = &routeapi.TLSConfig{Termination: routeapi.TLSTerminationEdge, Certificate: otherCert, Key: otherKey}
+	if err := p.HandleRoute(watch.Modified, invalid); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(plugin.routes) != 0 {
+		t.Errorf("expected the invalid route to be removed, got %v", served(plugin))
+	}
+	if recorder.reasons["a/secure"] != "ExtendedValidationFailed" {
+		t.Errorf("expected the invalid route to be rejected, got %v", recorder.reasons)
+	}
+
+	// a key which does not match the certificate
+	mismatched := newRoute("a", "mismatched", "www.example.com", 1)
+	mismatched.TLS = &routeapi.TLSConfig{Termination: routeapi.TLSTerminationEdge, Certificate: cert, Key: otherKey}
+	p.HandleRoute(watch.Added, mismatched)
+	if len(plugin.routes) != 0 || recorder.reasons["a/mismatched"] != "ExtendedValidationFailed" {
+		t.Errorf("expected the route with a mismatched key to be rejected, got %v", recorder.reasons)
+	}
+
+	// routes without TLS are passed on
+	p.HandleRoute(watch.Added, newRoute("a", "plain", "plain.example.com", 1))
+	if _, ok := plugin.routes["a/plain"]; !ok {
+		t.Errorf("expected the route without TLS to be served, got %v", served(plugin))
+	}
+}
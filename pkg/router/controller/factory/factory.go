@@ -1,6 +1,7 @@
 package factory
 
 import (
+	"sync"
 	"time"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
@@ -9,6 +10,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	kutil "github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
 	osclient "github.com/openshift/origin/pkg/client"
@@ -21,14 +23,28 @@ import (
 type RouterControllerFactory struct {
 	KClient  kclient.Interface
 	OSClient osclient.Interface
+	// Labels selects the routes the router serves. All routes are served if it is nil.
+	Labels labels.Selector
+	// NamespaceLabels selects the namespaces whose routes and endpoints the router serves.
+	// All namespaces are served if it is nil.
+	NamespaceLabels labels.Selector
 }
 
 func (factory *RouterControllerFactory) Create(plugin router.Plugin) *controller.RouterController {
+	routeLabels := factory.Labels
+	if routeLabels == nil {
+		routeLabels = labels.Everything()
+	}
+	var namespaces *namespaceFilter
+	if factory.NamespaceLabels != nil {
+		namespaces = &namespaceFilter{client: factory.KClient, selector: factory.NamespaceLabels}
+	}
+
 	routeEventQueue := oscache.NewEventQueue(cache.MetaNamespaceKeyFunc)
-	cache.NewReflector(&routeLW{factory.OSClient}, &routeapi.Route{}, routeEventQueue, 2*time.Minute).Run()
+	cache.NewReflector(&routeLW{factory.OSClient, routeLabels, namespaces}, &routeapi.Route{}, routeEventQueue, 2*time.Minute).Run()
 
 	endpointsEventQueue := oscache.NewEventQueue(cache.MetaNamespaceKeyFunc)
-	cache.NewReflector(&endpointsLW{factory.KClient}, &kapi.Endpoints{}, endpointsEventQueue, 2*time.Minute).Run()
+	cache.NewReflector(&endpointsLW{factory.KClient, namespaces}, &kapi.Endpoints{}, endpointsEventQueue, 2*time.Minute).Run()
 
 	return &controller.RouterController{
 		Plugin: plugin,
@@ -50,25 +66,120 @@ func (factory *RouterControllerFactory) Create(plugin router.Plugin) *controller
 }
 
 type routeLW struct {
-	client osclient.Interface
+	client     osclient.Interface
+	labels     labels.Selector
+	namespaces *namespaceFilter
 }
 
 func (lw *routeLW) List() (runtime.Object, error) {
-	return lw.client.Routes(kapi.NamespaceAll).List(labels.Everything(), fields.Everything())
+	if err := lw.namespaces.refresh(); err != nil {
+		return nil, err
+	}
+	routes, err := lw.client.Routes(kapi.NamespaceAll).List(lw.labels, fields.Everything())
+	if err != nil {
+		return nil, err
+	}
+	items := []routeapi.Route{}
+	for _, route := range routes.Items {
+		if lw.namespaces.matches(route.Namespace) {
+			items = append(items, route)
+		}
+	}
+	routes.Items = items
+	return routes, nil
 }
 
 func (lw *routeLW) Watch(resourceVersion string) (watch.Interface, error) {
-	return lw.client.Routes(kapi.NamespaceAll).Watch(labels.Everything(), fields.Everything(), resourceVersion)
+	w, err := lw.client.Routes(kapi.NamespaceAll).Watch(lw.labels, fields.Everything(), resourceVersion)
+	if err != nil {
+		return nil, err
+	}
+	return lw.namespaces.filter(w), nil
 }
 
 type endpointsLW struct {
-	client kclient.Interface
+	client     kclient.Interface
+	namespaces *namespaceFilter
 }
 
 func (lw *endpointsLW) List() (runtime.Object, error) {
-	return lw.client.Endpoints(kapi.NamespaceAll).List(labels.Everything())
+	if err := lw.namespaces.refresh(); err != nil {
+		return nil, err
+	}
+	endpoints, err := lw.client.Endpoints(kapi.NamespaceAll).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	items := []kapi.Endpoints{}
+	for _, ep := range endpoints.Items {
+		if lw.namespaces.matches(ep.Namespace) {
+			items = append(items, ep)
+		}
+	}
+	endpoints.Items = items
+	return endpoints, nil
 }
 
 func (lw *endpointsLW) Watch(resourceVersion string) (watch.Interface, error) {
-	return lw.client.Endpoints(kapi.NamespaceAll).Watch(labels.Everything(), fields.Everything(), resourceVersion)
+	w, err := lw.client.Endpoints(kapi.NamespaceAll).Watch(labels.Everything(), fields.Everything(), resourceVersion)
+	if err != nil {
+		return nil, err
+	}
+	return lw.namespaces.filter(w), nil
+}
+
+// namespaceFilter limits the routes and endpoints served by a router to the namespaces
+// whose labels match a selector. The matching namespaces are refreshed each time the
+// routes or endpoints are listed, which the reflectors do periodically. A nil filter
+// matches all namespaces.
+type namespaceFilter struct {
+	client   kclient.NamespacesInterface
+	selector labels.Selector
+
+	lock       sync.Mutex
+	namespaces kutil.StringSet
+}
+
+// refresh retrieves the namespaces matching the selector.
+func (f *namespaceFilter) refresh() error {
+	if f == nil {
+		return nil
+	}
+	list, err := f.client.Namespaces().List(f.selector, fields.Everything())
+	if err != nil {
+		return err
+	}
+	namespaces := kutil.NewStringSet()
+	for _, namespace := range list.Items {
+		namespaces.Insert(namespace.Name)
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.namespaces = namespaces
+	return nil
+}
+
+// matches returns true if the router serves the namespace.
+func (f *namespaceFilter) matches(namespace string) bool {
+	if f == nil {
+		return true
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.namespaces.Has(namespace)
+}
+
+// filter drops the events of w for objects in namespaces the router does not serve.
+func (f *namespaceFilter) filter(w watch.Interface) watch.Interface {
+	if f == nil {
+		return w
+	}
+	return watch.Filter(w, func(in watch.Event) (watch.Event, bool) {
+		meta, err := kapi.ObjectMetaFor(in.Object)
+		if err != nil {
+			return in, true
+		}
+		return in, f.matches(meta.Namespace)
+	})
 }
@@ -0,0 +1,82 @@
+package factory
+
+import (
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	ktc "github.com/GoogleCloudPlatform/kubernetes/pkg/client/testclient"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	kutil "github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+
+	"github.com/openshift/origin/pkg/client"
+	routeapi "github.com/openshift/origin/pkg/route/api"
+)
+
+func TestRouteListFiltersNamespaces(t *testing.T) {
+	kc := &ktc.Fake{
+		ReactFn: func(action ktc.FakeAction) (runtime.Object, error) {
+			return &kapi.NamespaceList{
+				Items: []kapi.Namespace{{ObjectMeta: kapi.ObjectMeta{Name: "internal"}}},
+			}, nil
+		},
+	}
+	oc := &client.Fake{
+		ReactFn: func(action ktc.FakeAction) (runtime.Object, error) {
+			return &routeapi.RouteList{
+				Items: []routeapi.Route{
+					{ObjectMeta: kapi.ObjectMeta{Namespace: "internal", Name: "a"}},
+					{ObjectMeta: kapi.ObjectMeta{Namespace: "public", Name: "b"}},
+				},
+			}, nil
+		},
+	}
+	selector, err := labels.Parse("network=internal")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lw := &routeLW{oc, labels.Everything(), &namespaceFilter{client: kc, selector: selector}}
+	obj, err := lw.List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	routes := obj.(*routeapi.RouteList).Items
+	if len(routes) != 1 || routes[0].Name != "a" {
+		t.Errorf("expected only the route in the selected namespace, got %#v", routes)
+	}
+	if len(kc.Actions) != 1 || kc.Actions[0].Action != "list-namespaces" {
+		t.Errorf("expected the namespaces to be listed, got %#v", kc.Actions)
+	}
+}
+
+func TestNamespaceFilterWatch(t *testing.T) {
+	f := &namespaceFilter{namespaces: kutil.NewStringSet("internal")}
+
+	fake := watch.NewFake()
+	w := f.filter(fake)
+	go func() {
+		fake.Add(&routeapi.Route{ObjectMeta: kapi.ObjectMeta{Namespace: "public", Name: "b"}})
+		fake.Add(&routeapi.Route{ObjectMeta: kapi.ObjectMeta{Namespace: "internal", Name: "a"}})
+		fake.Stop()
+	}()
+
+	names := []string{}
+	for event := range w.ResultChan() {
+		names = append(names, event.Object.(*routeapi.Route).Name)
+	}
+	if len(names) != 1 || names[0] != "a" {
+		t.Errorf("expected only the event for the route in the selected namespace, got %v", names)
+	}
+}
+
+func TestNilNamespaceFilterMatchesAll(t *testing.T) {
+	var f *namespaceFilter
+	if err := f.refresh(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !f.matches("any") {
+		t.Errorf("expected a nil filter to match all namespaces")
+	}
+}
@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"reflect"
+	"sync"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/golang/glog"
+
+	"github.com/openshift/origin/pkg/client"
+	routeapi "github.com/openshift/origin/pkg/route/api"
+)
+
+// StatusAdmitter implements RouteStatusRecorder by recording in the status of each route
+// whether the router with its name admitted the route. Admissions are held until Flush
+// is called once the router configuration serving the routes was applied, while
+// rejections are recorded right away.
+type StatusAdmitter struct {
+	client     client.RoutesNamespacer
+	routerName string
+
+	// lock protects pending
+	lock sync.Mutex
+	// pending holds the routes admitted since the last flush, by namespace and name
+	pending map[string]*routeapi.Route
+}
+
+// NewStatusAdmitter creates a recorder which updates the status of routes as the router
+// with routerName.
+func NewStatusAdmitter(client client.RoutesNamespacer, routerName string) *StatusAdmitter {
+	return &StatusAdmitter{
+		client:     client,
+		routerName: routerName,
+		pending:    map[string]*routeapi.Route{},
+	}
+}
+
+// RecordRouteAdmission records that the router serves the route once Flush is called.
+func (a *StatusAdmitter) RecordRouteAdmission(route *routeapi.Route) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.pending[routeNameKey(route)] = route
+}
+
+// RecordRouteRejection records why the router does not serve the route.
+func (a *StatusAdmitter) RecordRouteRejection(route *routeapi.Route, reason, message string) {
+	a.lock.Lock()
+	delete(a.pending, routeNameKey(route))
+	a.lock.Unlock()
+
+	a.record(route, routeapi.RouteIngressCondition{
+		Type:    routeapi.RouteAdmitted,
+		Status:  kapi.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+// Flush records the admission of the routes admitted since the last flush. It is called
+// after the router successfully committed its configuration.
+func (a *StatusAdmitter) Flush() {
+	a.lock.Lock()
+	admitted := a.pending
+	a.pending = map[string]*routeapi.Route{}
+	a.lock.Unlock()
+
+	for _, route := range admitted {
+		a.record(route, routeapi.RouteIngressCondition{
+			Type:   routeapi.RouteAdmitted,
+			Status: kapi.ConditionTrue,
+		})
+	}
+}
+
+// record sets the ingress of the router in the status of the route to condition, and
+// updates the route unless its status already matches. Skipping unchanged routes keeps
+// the update from triggering another watch event for the same route.
+func (a *StatusAdmitter) record(route *routeapi.Route, condition routeapi.RouteIngressCondition) {
+	updated := *route
+	updated.Status = &routeapi.RouteStatus{Ingress: []routeapi.RouteIngress{}}
+	var existing *routeapi.RouteIngress
+	if route.Status != nil {
+		for i := range route.Status.Ingress {
+			if route.Status.Ingress[i].RouterName == a.routerName {
+				existing = &route.Status.Ingress[i]
+				continue
+			}
+			updated.Status.Ingress = append(updated.Status.Ingress, route.Status.Ingress[i])
+		}
+	}
+
+	condition.LastTransitionTime = util.Now()
+	if existing != nil && existing.Host == route.Host && len(existing.Conditions) == 1 {
+		old := existing.Conditions[0]
+		condition.LastTransitionTime = old.LastTransitionTime
+		if reflect.DeepEqual(old, condition) {
+			return
+		}
+		if old.Status != condition.Status {
+			condition.LastTransitionTime = util.Now()
+		}
+	}
+
+	updated.Status.Ingress = append(updated.Status.Ingress, routeapi.RouteIngress{
+		Host:       route.Host,
+		RouterName: a.routerName,
+		Conditions: []routeapi.RouteIngressCondition{condition},
+	})
+	if _, err := a.client.Routes(route.Namespace).UpdateStatus(&updated); err != nil {
+		if kerrors.IsNotFound(err) {
+			// the route was deleted before its status was recorded
+			return
+		}
+		glog.Errorf("Unable to update the status of route %s/%s: %v", route.Namespace, route.Name, err)
+	}
+}
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+
+	"github.com/openshift/origin/pkg/client"
+	routeapi "github.com/openshift/origin/pkg/route/api"
+)
+
+func TestStatusAdmitter(t *testing.T) {
+	fake := &client.Fake{}
+	admitter := NewStatusAdmitter(fake, "public")
+
+	route := newRoute("a", "route", "www.example.com", 1)
+	route.Status = &routeapi.RouteStatus{Ingress: []routeapi.RouteIngress{{Host: "www.example.com", RouterName: "other"}}}
+	admitter.RecordRouteRejection(route, "HostAlreadyClaimed", "taken")
+
+	if len(fake.Actions) != 1 || fake.Actions[0].Action != "update-status-route" {
+		t.Fatalf("expected a status update, got %#v", fake.Actions)
+	}
+	updated := fake.Actions[0].Value.(*routeapi.Route)
+	if len(route.Status.Ingress) != 1 {
+		t.Errorf("expected the original route to be unchanged, got %#v", route.Status)
+	}
+	if len(updated.Status.Ingress) != 2 || updated.Status.Ingress[0].RouterName != "other" {
+		t.Fatalf("expected the ingress of other routers to be kept, got %#v", updated.Status)
+	}
+	ingress := updated.Status.Ingress[1]
+	if ingress.RouterName != "public" || ingress.Host != "www.example.com" || len(ingress.Conditions) != 1 {
+		t.Fatalf("unexpected ingress %#v", ingress)
+	}
+	condition := ingress.Conditions[0]
+	if condition.Type != routeapi.RouteAdmitted || condition.Status != kapi.ConditionFalse || condition.Reason != "HostAlreadyClaimed" || condition.Message != "taken" {
+		t.Errorf("unexpected condition %#v", condition)
+	}
+
+	// recording the same status again does not update the route
+	admitter.RecordRouteRejection(updated, "HostAlreadyClaimed", "taken")
+	if len(fake.Actions) != 1 {
+		t.Errorf("expected no update for an unchanged status, got %#v", fake.Actions)
+	}
+
+	admitter.RecordRouteAdmission(updated)
+	if len(fake.Actions) != 1 {
+		t.Fatalf("expected the admission to wait for the router to commit, got %#v", fake.Actions)
+	}
+	admitter.Flush()
+	if len(fake.Actions) != 2 {
+		t.Fatalf("expected a status update, got %#v", fake.Actions)
+	}
+	admitted := fake.Actions[1].Value.(*routeapi.Route).Status.Ingress[1].Conditions[0]
+	if admitted.Status != kapi.ConditionTrue || len(admitted.Reason) != 0 {
+		t.Errorf("unexpected condition %#v", admitted)
+	}
+
+	// a rejection replaces an admission which was not flushed yet
+	admitter.RecordRouteAdmission(route)
+	admitter.RecordRouteRejection(route, "HostAlreadyClaimed", "taken")
+	admitter.Flush()
+	if len(fake.Actions) != 3 {
+		t.Errorf("expected only the rejection to be recorded, got %#v", fake.Actions)
+	}
+}
@@ -0,0 +1,274 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/golang/glog"
+
+	routeapi "github.com/openshift/origin/pkg/route/api"
+	"github.com/openshift/origin/pkg/router"
+)
+
+// RouteStatusRecorder records whether the router admitted a route.
+type RouteStatusRecorder interface {
+	// RecordRouteAdmission records that the router serves the route.
+	RecordRouteAdmission(route *routeapi.Route)
+	// RecordRouteRejection records why the router does not serve the route.
+	RecordRouteRejection(route *routeapi.Route, reason, message string)
+}
+
+// UniqueHost implements the router.Plugin interface to give the namespace of the oldest
+// route claiming a host the ownership of that host. Routes from other namespaces which
+// claim the same host are not passed on to the wrapped plugin, and are recorded as
+// rejected. When the owning namespace releases the host, the oldest remaining claim wins.
+// A route with a Subdomain wildcard policy claims the whole subdomain of its host, and is
+// rejected unless wildcard routes are allowed. A subdomain is not owned by a namespace while
+// an older route from another namespace claims a host in it.
+type UniqueHost struct {
+	plugin   router.Plugin
+	recorder RouteStatusRecorder
+	// disableOwnershipCheck lets routes from any namespace share a host
+	disableOwnershipCheck bool
+	// allowWildcardRoutes lets routes claim every host in a subdomain
+	allowWildcardRoutes bool
+
+	// hosts holds the routes claiming each host, oldest first
+	hosts map[string][]*routeapi.Route
+	// routeHosts holds the host claimed by each route, by namespace and name
+	routeHosts map[string]string
+}
+
+// NewUniqueHost creates a plugin wrapper which only passes on the routes of the namespace
+// owning their host. If disableOwnershipCheck is true, every route is passed on. Wildcard
+// routes are only passed on if allowWildcardRoutes is true.
+func NewUniqueHost(plugin router.Plugin, recorder RouteStatusRecorder, disableOwnershipCheck, allowWildcardRoutes bool) *UniqueHost {
+	return &UniqueHost{
+		plugin:                plugin,
+		recorder:              recorder,
+		disableOwnershipCheck: disableOwnershipCheck,
+		allowWildcardRoutes:   allowWildcardRoutes,
+		hosts:                 map[string][]*routeapi.Route{},
+		routeHosts:            map[string]string{},
+	}
+}
+
+// HandleEndpoints passes endpoints on to the wrapped plugin.
+func (p *UniqueHost) HandleEndpoints(eventType watch.EventType, endpoints *kapi.Endpoints) error {
+	return p.plugin.HandleEndpoints(eventType, endpoints)
+}
+
+// HandleRoute passes the route on to the wrapped plugin if its namespace owns its host,
+// and admits or removes the other routes claiming the host whose ownership changed.
+func (p *UniqueHost) HandleRoute(eventType watch.EventType, route *routeapi.Route) error {
+	key, host := routeNameKey(route), hostKey(route)
+
+	// a modified route may release the host it claimed before
+	if oldHost, ok := p.routeHosts[key]; ok && (oldHost != host || eventType == watch.Deleted) {
+		if err := p.release(oldHost, key); err != nil {
+			return err
+		}
+	}
+	if eventType == watch.Deleted {
+		return nil
+	}
+
+	if len(route.Host) == 0 {
+		p.recorder.RecordRouteRejection(route, "NoHostValue", "no host value was defined for the route")
+		return nil
+	}
+	if route.WildcardPolicy == routeapi.WildcardPolicySubdomain && !p.allowWildcardRoutes {
+		p.recorder.RecordRouteRejection(route, "WildcardPolicyNotAllowed", "wildcard routes are not allowed by the router")
+		return nil
+	}
+	return p.claim(eventType, route)
+}
+
+// claim adds the route to the claims on its host, and passes it on to the wrapped plugin
+// if it was admitted.
+func (p *UniqueHost) claim(eventType watch.EventType, route *routeapi.Route) error {
+	host, key := hostKey(route), routeNameKey(route)
+
+	before := p.admitted(host)
+	subdomain, subdomainBefore := p.admittedInSubdomain(host)
+	claims := []*routeapi.Route{route}
+	for _, claim := range p.hosts[host] {
+		if routeNameKey(claim) != key {
+			claims = append(claims, claim)
+		}
+	}
+	sort.Sort(routeAge(claims))
+	p.hosts[host] = claims
+	p.routeHosts[key] = host
+
+	if err := p.sync(host, before, key); err != nil {
+		return err
+	}
+	if err := p.sync(subdomain, subdomainBefore, key); err != nil {
+		return err
+	}
+
+	if _, ok := p.admitted(host)[key]; !ok {
+		p.reject(route)
+		return nil
+	}
+	// the admission is recorded first, so it is reported once the plugin commits the route
+	p.recorder.RecordRouteAdmission(route)
+	return p.plugin.HandleRoute(eventType, route)
+}
+
+// release removes the route from the claims on host, and removes it from the wrapped
+// plugin if it was admitted.
+func (p *UniqueHost) release(host, key string) error {
+	before := p.admitted(host)
+	subdomain, subdomainBefore := p.admittedInSubdomain(host)
+	claims := []*routeapi.Route{}
+	for _, claim := range p.hosts[host] {
+		if routeNameKey(claim) != key {
+			claims = append(claims, claim)
+		}
+	}
+	if len(claims) == 0 {
+		delete(p.hosts, host)
+	} else {
+		p.hosts[host] = claims
+	}
+	delete(p.routeHosts, key)
+
+	if route, ok := before[key]; ok {
+		if err := p.plugin.HandleRoute(watch.Deleted, route); err != nil {
+			return err
+		}
+	}
+	if err := p.sync(host, before, key); err != nil {
+		return err
+	}
+	return p.sync(subdomain, subdomainBefore, key)
+}
+
+// sync passes the changes in the admitted routes for host, other than the route with
+// key, on to the wrapped plugin.
+func (p *UniqueHost) sync(host string, before map[string]*routeapi.Route, key string) error {
+	after := p.admitted(host)
+	for name, route := range before {
+		if _, ok := after[name]; ok || name == key {
+			continue
+		}
+		glog.V(4).Infof("Route %s no longer owns host %s", name, host)
+		if err := p.plugin.HandleRoute(watch.Deleted, route); err != nil {
+			return err
+		}
+		p.reject(route)
+	}
+	for name, route := range after {
+		if _, ok := before[name]; ok || name == key {
+			continue
+		}
+		glog.V(4).Infof("Route %s now owns host %s", name, host)
+		p.recorder.RecordRouteAdmission(route)
+		if err := p.plugin.HandleRoute(watch.Added, route); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// admitted returns the routes claiming host which are in the namespace owning it, by
+// namespace and name.
+func (p *UniqueHost) admitted(host string) map[string]*routeapi.Route {
+	admitted := map[string]*routeapi.Route{}
+	claims := p.hosts[host]
+	if len(claims) == 0 || p.subdomainBlocker(host) != nil {
+		return admitted
+	}
+	for _, route := range claims {
+		if p.disableOwnershipCheck || route.Namespace == claims[0].Namespace {
+			admitted[routeNameKey(route)] = route
+		}
+	}
+	return admitted
+}
+
+// admittedInSubdomain returns the wildcard host of the subdomain of an exact host, along with
+// the routes it admits. Claims on the exact host may change which namespace owns the
+// subdomain. An empty host is returned for a wildcard host.
+func (p *UniqueHost) admittedInSubdomain(host string) (string, map[string]*routeapi.Route) {
+	if strings.HasPrefix(host, "*.") {
+		return "", nil
+	}
+	parts := strings.SplitN(host, ".", 2)
+	if len(parts) != 2 {
+		return "", nil
+	}
+	subdomain := "*." + parts[1]
+	return subdomain, p.admitted(subdomain)
+}
+
+// subdomainBlocker returns the oldest route from another namespace than the owner of the
+// wildcard host which is older than that owner and claims a host in its subdomain, or nil.
+func (p *UniqueHost) subdomainBlocker(host string) *routeapi.Route {
+	if p.disableOwnershipCheck || !strings.HasPrefix(host, "*.") || len(p.hosts[host]) == 0 {
+		return nil
+	}
+	owner := p.hosts[host][0]
+	var blocker *routeapi.Route
+	for exact, claims := range p.hosts {
+		if exact == host || strings.HasPrefix(exact, "*.") {
+			continue
+		}
+		if parts := strings.SplitN(exact, ".", 2); len(parts) != 2 || "*."+parts[1] != host {
+			continue
+		}
+		claim := claims[0]
+		if claim.Namespace == owner.Namespace || !routeAge([]*routeapi.Route{claim, owner}).Less(0, 1) {
+			continue
+		}
+		if blocker == nil || routeAge([]*routeapi.Route{claim, blocker}).Less(0, 1) {
+			blocker = claim
+		}
+	}
+	return blocker
+}
+
+// reject records that the route was not admitted since another namespace owns its host, or
+// a host in the subdomain of a wildcard route.
+func (p *UniqueHost) reject(route *routeapi.Route) {
+	host := hostKey(route)
+	owner := p.hosts[host][0]
+	message := fmt.Sprintf("route %s in namespace %s is older and already exposes host %s", owner.Name, owner.Namespace, host)
+	if blocker := p.subdomainBlocker(host); blocker != nil {
+		message = fmt.Sprintf("route %s in namespace %s is older and already exposes host %s in the subdomain %s", blocker.Name, blocker.Namespace, blocker.Host, host)
+	}
+	glog.V(4).Infof("Rejecting route %s: %s", routeNameKey(route), message)
+	p.recorder.RecordRouteRejection(route, "HostAlreadyClaimed", message)
+}
+
+// hostKey returns the host the route claims, which is *.<subdomain> for a wildcard route.
+func hostKey(route *routeapi.Route) string {
+	if route.WildcardPolicy == routeapi.WildcardPolicySubdomain {
+		if parts := strings.SplitN(route.Host, ".", 2); len(parts) == 2 {
+			return "*." + parts[1]
+		}
+	}
+	return route.Host
+}
+
+// routeNameKey returns a unique key for the route.
+func routeNameKey(route *routeapi.Route) string {
+	return fmt.Sprintf("%s/%s", route.Namespace, route.Name)
+}
+
+// routeAge sorts routes from the oldest to the newest, then by namespace and name.
+type routeAge []*routeapi.Route
+
+func (r routeAge) Len() int      { return len(r) }
+func (r routeAge) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r routeAge) Less(i, j int) bool {
+	if !r[i].CreationTimestamp.Time.Equal(r[j].CreationTimestamp.Time) {
+		return r[i].CreationTimestamp.Before(r[j].CreationTimestamp)
+	}
+	return routeNameKey(r[i]) < routeNameKey(r[j])
+}
@@ -0,0 +1,216 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+
+	routeapi "github.com/openshift/origin/pkg/route/api"
+)
+
+type fakePlugin struct {
+	routes map[string]*routeapi.Route
+}
+
+func (p *fakePlugin) HandleRoute(eventType watch.EventType, route *routeapi.Route) error {
+	if eventType == watch.Deleted {
+		delete(p.routes, routeNameKey(route))
+	} else {
+		p.routes[routeNameKey(route)] = route
+	}
+	return nil
+}
+
+func (p *fakePlugin) HandleEndpoints(watch.EventType, *kapi.Endpoints) error {
+	return nil
+}
+
+type fakeRecorder struct {
+	admitted map[string]bool
+	reasons  map[string]string
+}
+
+func (r *fakeRecorder) RecordRouteAdmission(route *routeapi.Route) {
+	r.admitted[routeNameKey(route)] = true
+	delete(r.reasons, routeNameKey(route))
+}
+
+func (r *fakeRecorder) RecordRouteRejection(route *routeapi.Route, reason, message string) {
+	r.admitted[routeNameKey(route)] = false
+	r.reasons[routeNameKey(route)] = reason
+}
+
+func newRoute(namespace, name, host string, age int) *routeapi.Route {
+	route := &routeapi.Route{Host: host}
+	route.Namespace = namespace
+	route.Name = name
+	route.CreationTimestamp = util.NewTime(time.Unix(1000, 0).Add(-time.Duration(age) * time.Minute))
+	return route
+}
+
+func newUniqueHost(disableOwnershipCheck, allowWildcardRoutes bool) (*UniqueHost, *fakePlugin, *fakeRecorder) {
+	plugin := &fakePlugin{routes: map[string]*routeapi.Route{}}
+	recorder := &fakeRecorder{admitted: map[string]bool{}, reasons: map[string]string{}}
+	return NewUniqueHost(plugin, recorder, disableOwnershipCheck, allowWildcardRoutes), plugin, recorder
+}
+
+func served(plugin *fakePlugin) []string {
+	names := util.NewStringSet()
+	for name := range plugin.routes {
+		names.Insert(name)
+	}
+	return names.List()
+}
+
+func TestUniqueHostRejectsOtherNamespaces(t *testing.T) {
+	p, plugin, recorder := newUniqueHost(false, false)
+
+	p.HandleRoute(watch.Added, newRoute("a", "first", "www.example.com", 10))
+	p.HandleRoute(watch.Added, newRoute("b", "second", "www.example.com", 5))
+	p.HandleRoute(watch.Added, newRoute("a", "third", "www.example.com", 1))
+
+	if e, a := []string{"a/first", "a/third"}, served(plugin); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected routes %v to be served, got %v", e, a)
+	}
+	if recorder.admitted["b/second"] || recorder.reasons["b/second"] != "HostAlreadyClaimed" {
+		t.Errorf("expected route b/second to be rejected, got %v", recorder.reasons)
+	}
+	if !recorder.admitted["a/first"] || !recorder.admitted["a/third"] {
+		t.Errorf("expected the routes of namespace a to be admitted, got %v", recorder.admitted)
+	}
+}
+
+func TestUniqueHostOlderRouteTakesOwnership(t *testing.T) {
+	p, plugin, recorder := newUniqueHost(false, false)
+
+	// the router may see a newer route before an older one
+	p.HandleRoute(watch.Added, newRoute("b", "newer", "www.example.com", 5))
+	p.HandleRoute(watch.Added, newRoute("a", "older", "www.example.com", 10))
+
+	if e, a := []string{"a/older"}, served(plugin); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected routes %v to be served, got %v", e, a)
+	}
+	if recorder.admitted["b/newer"] {
+		t.Errorf("expected route b/newer to be rejected once the older route was seen")
+	}
+}
+
+func TestUniqueHostPromotesOnRelease(t *testing.T) {
+	p, plugin, recorder := newUniqueHost(false, false)
+
+	owner := newRoute("a", "owner", "www.example.com", 10)
+	p.HandleRoute(watch.Added, owner)
+	p.HandleRoute(watch.Added, newRoute("b", "waiting", "www.example.com", 5))
+	p.HandleRoute(watch.Added, newRoute("c", "last", "www.example.com", 1))
+
+	p.HandleRoute(watch.Deleted, owner)
+	if e, a := []string{"b/waiting"}, served(plugin); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected routes %v to be served, got %v", e, a)
+	}
+	if !recorder.admitted["b/waiting"] || recorder.admitted["c/last"] {
+		t.Errorf("expected only b/waiting to be admitted, got %v", recorder.admitted)
+	}
+
+	// moving the route to another host releases its old host
+	moved := newRoute("b", "waiting", "other.example.com", 5)
+	p.HandleRoute(watch.Modified, moved)
+	if e, a := []string{"b/waiting", "c/last"}, served(plugin); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected routes %v to be served, got %v", e, a)
+	}
+	if plugin.routes["b/waiting"].Host != "other.example.com" {
+		t.Errorf("expected the modified route to be served, got %#v", plugin.routes["b/waiting"])
+	}
+}
+
+func TestUniqueHostDisabledOwnershipCheck(t *testing.T) {
+	p, plugin, recorder := newUniqueHost(true, false)
+
+	p.HandleRoute(watch.Added, newRoute("a", "first", "www.example.com", 10))
+	p.HandleRoute(watch.Added, newRoute("b", "second", "www.example.com", 5))
+
+	if e, a := []string{"a/first", "b/second"}, served(plugin); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected routes %v to be served, got %v", e, a)
+	}
+	if !recorder.admitted["a/first"] || !recorder.admitted["b/second"] {
+		t.Errorf("expected all routes to be admitted, got %v", recorder.admitted)
+	}
+}
+
+func TestUniqueHostRejectsEmptyHost(t *testing.T) {
+	p, plugin, recorder := newUniqueHost(false, false)
+
+	p.HandleRoute(watch.Added, newRoute("a", "nohost", "", 10))
+	if len(plugin.routes) != 0 {
+		t.Errorf("expected no routes to be served, got %v", served(plugin))
+	}
+	if recorder.reasons["a/nohost"] != "NoHostValue" {
+		t.Errorf("expected route a/nohost to be rejected, got %v", recorder.reasons)
+	}
+}
+
+func TestUniqueHostWildcardRoutes(t *testing.T) {
+	wildcard := newRoute("a", "wildcard", "www.apps.example.com", 10)
+	wildcard.WildcardPolicy = routeapi.WildcardPolicySubdomain
+
+	p, plugin, recorder := newUniqueHost(false, false)
+	p.HandleRoute(watch.Added, wildcard)
+	if len(plugin.routes) != 0 || recorder.reasons["a/wildcard"] != "WildcardPolicyNotAllowed" {
+		t.Errorf("expected the wildcard route to be rejected, got %v", recorder.reasons)
+	}
+
+	p, plugin, recorder = newUniqueHost(false, true)
+	other := newRoute("b", "other", "other.apps.example.com", 5)
+	other.WildcardPolicy = routeapi.WildcardPolicySubdomain
+	p.HandleRoute(watch.Added, wildcard)
+	p.HandleRoute(watch.Added, other)
+	// an exact host in the subdomain is not claimed by the wildcard
+	p.HandleRoute(watch.Added, newRoute("c", "exact", "other.apps.example.com", 1))
+
+	if e, a := []string{"a/wildcard", "c/exact"}, served(plugin); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected routes %v to be served, got %v", e, a)
+	}
+	if recorder.reasons["b/other"] != "HostAlreadyClaimed" {
+		t.Errorf("expected the second wildcard route for the subdomain to be rejected, got %v", recorder.reasons)
+	}
+}
+
+func TestUniqueHostWildcardRoutesRespectOlderHostsInSubdomain(t *testing.T) {
+	wildcard := newRoute("a", "wildcard", "www.apps.example.com", 5)
+	wildcard.WildcardPolicy = routeapi.WildcardPolicySubdomain
+
+	// a wildcard route does not capture the subdomain of an older route from another namespace
+	p, plugin, recorder := newUniqueHost(false, true)
+	p.HandleRoute(watch.Added, newRoute("b", "exact", "shop.apps.example.com", 10))
+	p.HandleRoute(watch.Added, wildcard)
+	if e, a := []string{"b/exact"}, served(plugin); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected routes %v to be served, got %v", e, a)
+	}
+	if recorder.reasons["a/wildcard"] != "HostAlreadyClaimed" {
+		t.Errorf("expected the wildcard route to be rejected, got %v", recorder.reasons)
+	}
+
+	// the wildcard route is admitted once the subdomain is released
+	p.HandleRoute(watch.Deleted, newRoute("b", "exact", "shop.apps.example.com", 10))
+	if e, a := []string{"a/wildcard"}, served(plugin); !reflect.DeepEqual(e, a) || !recorder.admitted["a/wildcard"] {
+		t.Errorf("expected routes %v to be served, got %v", e, a)
+	}
+
+	// an older route from another namespace seen later takes the subdomain back
+	p.HandleRoute(watch.Added, newRoute("b", "exact", "shop.apps.example.com", 10))
+	if e, a := []string{"b/exact"}, served(plugin); !reflect.DeepEqual(e, a) || recorder.admitted["a/wildcard"] {
+		t.Errorf("expected routes %v to be served, got %v", e, a)
+	}
+
+	// newer routes from other namespaces and routes from the same namespace do not block the wildcard
+	p, plugin, recorder = newUniqueHost(false, true)
+	p.HandleRoute(watch.Added, newRoute("a", "exact", "shop.apps.example.com", 10))
+	p.HandleRoute(watch.Added, wildcard)
+	p.HandleRoute(watch.Added, newRoute("c", "newer", "blog.apps.example.com", 1))
+	if e, a := []string{"a/exact", "a/wildcard", "c/newer"}, served(plugin); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected routes %v to be served, got %v", e, a)
+	}
+}
@@ -0,0 +1,257 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const haproxyNamespace = "haproxy"
+
+// backendTypes maps the prefixes of the backends the HAProxy template generates for each
+// route to the type of the backend. The rest of the backend name is the route, as
+// <namespace>-<name>.
+var backendTypes = []struct {
+	prefix, name string
+}{
+	{"be_edge_http_", "edge"},
+	{"be_http_", "http"},
+	{"be_tcp_", "passthrough"},
+	{"be_secure_", "reencrypt"},
+}
+
+// responseCodes are the classes of HTTP responses HAProxy counts for each backend, by the
+// stats field holding the count.
+var responseCodes = map[string]string{
+	"hrsp_1xx":   "1xx",
+	"hrsp_2xx":   "2xx",
+	"hrsp_3xx":   "3xx",
+	"hrsp_4xx":   "4xx",
+	"hrsp_5xx":   "5xx",
+	"hrsp_other": "other",
+}
+
+var routeLabels = []string{"route", "type"}
+
+// Exporter collects the statistics HAProxy reports on its stats socket for the backend of
+// each route, and exports them as Prometheus metrics. It also implements a health check
+// which fails if HAProxy does not answer on the socket.
+type Exporter struct {
+	socketPath string
+	timeout    time.Duration
+
+	// lock serializes scrapes
+	lock           sync.Mutex
+	up             prometheus.Gauge
+	scrapeFailures prometheus.Counter
+
+	backendUp        *prometheus.Desc
+	sessionRate      *prometheus.Desc
+	sessionsTotal    *prometheus.Desc
+	connectionErrors *prometheus.Desc
+	responseErrors   *prometheus.Desc
+	responsesTotal   *prometheus.Desc
+	currentSessions  *prometheus.Desc
+	currentQueue     *prometheus.Desc
+}
+
+// NewExporter creates an exporter reading the statistics of HAProxy from the unix socket
+// at socketPath.
+func NewExporter(socketPath string, timeout time.Duration) *Exporter {
+	newDesc := func(name, help string, labels ...string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(haproxyNamespace, "backend", name), help, append(labels, routeLabels...), nil)
+	}
+	return &Exporter{
+		socketPath: socketPath,
+		timeout:    timeout,
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: haproxyNamespace,
+			Name:      "up",
+			Help:      "Whether the last scrape of HAProxy statistics was successful.",
+		}),
+		scrapeFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: haproxyNamespace,
+			Name:      "scrape_failures_total",
+			Help:      "Number of errors while scraping HAProxy statistics.",
+		}),
+		backendUp:        newDesc("up", "Whether the backend of the route is up."),
+		sessionRate:      newDesc("current_session_rate", "Number of sessions per second of the route over the last second."),
+		sessionsTotal:    newDesc("sessions_total", "Total number of sessions of the route."),
+		connectionErrors: newDesc("connection_errors_total", "Total number of errors connecting to the endpoints of the route."),
+		responseErrors:   newDesc("response_errors_total", "Total number of errors in responses from the endpoints of the route."),
+		responsesTotal:   newDesc("http_responses_total", "Total number of HTTP responses of the route, by class of status code.", "code"),
+		currentSessions:  newDesc("current_sessions", "Number of active sessions of the route."),
+		currentQueue:     newDesc("current_queue", "Number of requests of the route waiting for an endpoint."),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.up.Desc()
+	ch <- e.scrapeFailures.Desc()
+	ch <- e.backendUp
+	ch <- e.sessionRate
+	ch <- e.sessionsTotal
+	ch <- e.connectionErrors
+	ch <- e.responseErrors
+	ch <- e.responsesTotal
+	ch <- e.currentSessions
+	ch <- e.currentQueue
+}
+
+// Collect implements prometheus.Collector by scraping the statistics of HAProxy.
+func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	backends, err := e.scrape()
+	if err != nil {
+		glog.V(4).Infof("Unable to scrape HAProxy statistics: %v", err)
+		e.up.Set(0)
+		e.scrapeFailures.Inc()
+	} else {
+		e.up.Set(1)
+	}
+	ch <- e.up
+	ch <- e.scrapeFailures
+
+	for _, backend := range backends {
+		labels := []string{backend.route, backend.backendType}
+		up := 0.0
+		if backend.fields["status"] == "UP" {
+			up = 1
+		}
+		ch <- prometheus.MustNewConstMetric(e.backendUp, prometheus.GaugeValue, up, labels...)
+		backend.export(ch, e.sessionRate, prometheus.GaugeValue, "rate", labels)
+		backend.export(ch, e.sessionsTotal, prometheus.CounterValue, "stot", labels)
+		backend.export(ch, e.connectionErrors, prometheus.CounterValue, "econ", labels)
+		backend.export(ch, e.responseErrors, prometheus.CounterValue, "eresp", labels)
+		backend.export(ch, e.currentSessions, prometheus.GaugeValue, "scur", labels)
+		backend.export(ch, e.currentQueue, prometheus.GaugeValue, "qcur", labels)
+		for field, code := range responseCodes {
+			backend.export(ch, e.responsesTotal, prometheus.CounterValue, field, append([]string{code}, labels...))
+		}
+	}
+}
+
+// Name implements healthz.HealthzChecker.
+func (e *Exporter) Name() string {
+	return "haproxy"
+}
+
+// Check implements healthz.HealthzChecker by verifying HAProxy answers on its stats socket.
+func (e *Exporter) Check(_ *http.Request) error {
+	out, err := e.command("show info")
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(string(out), "Name:") {
+		return fmt.Errorf("unexpected answer from HAProxy: %q", string(out))
+	}
+	return nil
+}
+
+// scrape reads the statistics of the backends of routes from HAProxy.
+func (e *Exporter) scrape() ([]backendStats, error) {
+	out, err := e.command("show stat")
+	if err != nil {
+		return nil, err
+	}
+	return parseStats(bytes.NewReader(out))
+}
+
+// command sends a command to the stats socket of HAProxy and returns the answer.
+func (e *Exporter) command(command string) ([]byte, error) {
+	conn, err := net.DialTimeout("unix", e.socketPath, e.timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(e.timeout))
+
+	if _, err := io.WriteString(conn, command+"\n"); err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(conn)
+}
+
+// backendStats holds the statistics of the backend of a route, by field name.
+type backendStats struct {
+	route       string
+	backendType string
+	fields      map[string]string
+}
+
+// export sends the value of field as a metric, unless HAProxy left the field empty because
+// it does not apply to the backend.
+func (b backendStats) export(ch chan<- prometheus.Metric, desc *prometheus.Desc, valueType prometheus.ValueType, field string, labels []string) {
+	value, err := strconv.ParseFloat(b.fields[field], 64)
+	if err != nil {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(desc, valueType, value, labels...)
+}
+
+// parseStats parses the CSV statistics of HAProxy, and returns those of the backends
+// generated for routes. The first line lists the names of the fields, prefixed with "# ".
+func parseStats(r io.Reader) ([]backendStats, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the HAProxy statistics header: %v", err)
+	}
+	if len(header) == 0 || !strings.HasPrefix(header[0], "# ") {
+		return nil, fmt.Errorf("unexpected HAProxy statistics header: %v", header)
+	}
+	header[0] = strings.TrimPrefix(header[0], "# ")
+
+	backends := []backendStats{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the HAProxy statistics: %v", err)
+		}
+		if len(record) < 2 || record[1] != "BACKEND" {
+			continue
+		}
+		route, backendType, ok := routeBackend(record[0])
+		if !ok {
+			continue
+		}
+		fields := map[string]string{}
+		for i, name := range header {
+			if i < len(record) {
+				fields[name] = record[i]
+			}
+		}
+		backends = append(backends, backendStats{route: route, backendType: backendType, fields: fields})
+	}
+	return backends, nil
+}
+
+// routeBackend returns the route and the type of a backend generated for a route.
+func routeBackend(name string) (string, string, bool) {
+	for _, backendType := range backendTypes {
+		if strings.HasPrefix(name, backendType.prefix) {
+			return strings.TrimPrefix(name, backendType.prefix), backendType.name, true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,145 @@
+package metrics
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+const testStats = `# pxname,svname,qcur,qmax,scur,smax,slim,stot,bin,bout,dreq,dresp,ereq,econ,eresp,wretr,wredis,status,weight,act,bck,chkfail,chkdown,lastchg,downtime,qlimit,pid,iid,sid,throttle,lbtot,tracked,type,rate,rate_lim,rate_max,check_status,check_code,check_duration,hrsp_1xx,hrsp_2xx,hrsp_3xx,hrsp_4xx,hrsp_5xx,hrsp_other,hanafail,req_rate,req_rate_max,req_tot,cli_abrt,srv_abrt,
+public,FRONTEND,,,1,3,2000,27,4096,8192,0,0,0,,,,,OPEN,,,,,,,,,1,2,0,,,,0,1,0,3,,,,0,20,0,7,0,0,,1,3,27,,,
+be_http_ns-app,ns-app,0,0,0,1,,12,1024,2048,,0,,0,0,0,0,UP,1,1,0,0,0,100,0,,1,3,1,,12,,2,0,,1,L4OK,,0,0,10,0,2,0,0,0,,,,0,0,
+be_http_ns-app,BACKEND,0,0,1,1,200,12,1024,2048,0,0,,3,1,0,0,UP,1,1,0,,0,100,0,,1,3,0,,12,,1,2,,1,,,,0,10,0,2,0,0,,,,,0,0,
+be_tcp_ns-secure,BACKEND,0,0,0,1,200,5,512,512,0,0,,0,0,0,0,DOWN,0,0,0,,1,100,50,,1,4,0,,5,,1,0,,1,,,,,,,,,,,,,,0,0,
+openshift_default,BACKEND,0,0,0,0,200,0,0,0,0,0,,0,0,0,0,UP,1,1,0,,0,100,0,,1,5,0,,0,,1,0,,0,,,,0,0,0,0,0,0,,,,,0,0,
+`
+
+func TestParseStats(t *testing.T) {
+	backends, err := parseStats(strings.NewReader(testStats))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(backends) != 2 {
+		t.Fatalf("expected the backends of two routes, got %#v", backends)
+	}
+	if b := backends[0]; b.route != "ns-app" || b.backendType != "http" || b.fields["stot"] != "12" || b.fields["hrsp_4xx"] != "2" {
+		t.Errorf("unexpected backend %#v", b)
+	}
+	if b := backends[1]; b.route != "ns-secure" || b.backendType != "passthrough" || b.fields["status"] != "DOWN" {
+		t.Errorf("unexpected backend %#v", b)
+	}
+
+	if _, err := parseStats(strings.NewReader("not,stats\n")); err == nil {
+		t.Errorf("expected an error for output without a header")
+	}
+}
+
+// serveStats answers the commands sent to a unix socket like the HAProxy stats socket.
+func serveStats(t *testing.T, path string) net.Listener {
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			command, _ := bufio.NewReader(conn).ReadString('\n')
+			switch strings.TrimSpace(command) {
+			case "show stat":
+				conn.Write([]byte(testStats))
+			case "show info":
+				conn.Write([]byte("Name: HAProxy\nVersion: 1.5.4\n"))
+			}
+			conn.Close()
+		}
+	}()
+	return listener
+}
+
+// collect returns the values of the metrics collected by the exporter, by name and labels.
+func collect(e *Exporter) map[string]float64 {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		e.Collect(ch)
+		close(ch)
+	}()
+	values := map[string]float64{}
+	for metric := range ch {
+		m := &dto.Metric{}
+		metric.Write(m)
+		labels := []string{}
+		for _, pair := range m.Label {
+			labels = append(labels, pair.GetName()+"="+pair.GetValue())
+		}
+		var value float64
+		switch {
+		case m.Gauge != nil:
+			value = m.Gauge.GetValue()
+		case m.Counter != nil:
+			value = m.Counter.GetValue()
+		}
+		desc := metric.Desc().String()
+		name := desc[strings.Index(desc, `fqName: "`)+len(`fqName: "`):]
+		name = name[:strings.Index(name, `"`)]
+		values[name+"{"+strings.Join(labels, ",")+"}"] = value
+	}
+	return values
+}
+
+func TestExporter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "haproxy-stats")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "haproxy.sock")
+
+	e := NewExporter(path, time.Second)
+	if err := e.Check(nil); err == nil {
+		t.Errorf("expected the health check to fail without HAProxy")
+	}
+	if values := collect(e); values["haproxy_up{}"] != 0 || values["haproxy_scrape_failures_total{}"] != 1 {
+		t.Errorf("expected a failed scrape without HAProxy, got %v", values)
+	}
+
+	listener := serveStats(t, path)
+	defer listener.Close()
+
+	if err := e.Check(nil); err != nil {
+		t.Errorf("unexpected health check error: %v", err)
+	}
+
+	values := collect(e)
+	expected := map[string]float64{
+		"haproxy_up{}":                                                          1,
+		"haproxy_scrape_failures_total{}":                                       1,
+		"haproxy_backend_up{route=ns-app,type=http}":                            1,
+		"haproxy_backend_up{route=ns-secure,type=passthrough}":                  0,
+		"haproxy_backend_sessions_total{route=ns-app,type=http}":                12,
+		"haproxy_backend_current_session_rate{route=ns-app,type=http}":          2,
+		"haproxy_backend_connection_errors_total{route=ns-app,type=http}":       3,
+		"haproxy_backend_http_responses_total{code=2xx,route=ns-app,type=http}": 10,
+		"haproxy_backend_http_responses_total{code=4xx,route=ns-app,type=http}": 2,
+		"haproxy_backend_current_sessions{route=ns-app,type=http}":              1,
+		"haproxy_backend_sessions_total{route=ns-secure,type=passthrough}":      5,
+	}
+	for name, value := range expected {
+		if actual, ok := values[name]; !ok || actual != value {
+			t.Errorf("expected %s to be %v, got %v (found %t)", name, value, actual, ok)
+		}
+	}
+	if _, ok := values["haproxy_backend_http_responses_total{code=2xx,route=ns-secure,type=passthrough}"]; ok {
+		t.Errorf("expected no HTTP responses for a passthrough route")
+	}
+}
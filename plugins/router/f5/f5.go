@@ -0,0 +1,675 @@
+package f5
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/golang/glog"
+)
+
+const (
+	// insecureRoutesPolicyName is the name of the policy forwarding the requests of
+	// unsecured routes to their pools on the HTTP virtual server.
+	insecureRoutesPolicyName = "openshift_insecure_routes"
+	// secureRoutesPolicyName is the name of the policy forwarding the requests of edge and
+	// reencrypt routes to their pools on the HTTPS virtual server.
+	secureRoutesPolicyName = "openshift_secure_routes"
+
+	// passthroughIRuleName is the name of the iRule forwarding the connections of
+	// passthrough routes to their pools, by the server name of the TLS handshake.
+	passthroughIRuleName = "openshift_passthrough_irule"
+	// passthroughDatagroupName is the data group mapping the hosts of passthrough routes
+	// to their pools.
+	passthroughDatagroupName = "ssl_passthrough_servername_dg"
+
+	// reencryptIRuleName is the name of the iRule selecting the server SSL profile of the
+	// reencrypt route a request is for.
+	reencryptIRuleName = "openshift_reencrypt_irule"
+	// reencryptDatagroupName is the data group mapping the hosts of reencrypt routes to
+	// their server SSL profiles.
+	reencryptDatagroupName = "ssl_reencrypt_servername_dg"
+
+	// uploadPath is the directory of the BIG-IP where uploaded files are stored.
+	uploadPath = "/var/config/rest/downloads"
+)
+
+// passthroughIRule forwards the connections whose TLS ClientHello names the host of a
+// passthrough route to the pool of the route, without terminating TLS. The server name is
+// read from the SNI extension of the handshake.
+const passthroughIRule = `
+when CLIENT_ACCEPTED {
+  TCP::collect
+}
+
+when CLIENT_DATA {
+  # Byte 0 is the content type, bytes 1-2 the TLS version and bytes 3-4 the length.
+  binary scan [TCP::payload] cSS tls_content_type tls_version tls_payload_len
+  # A content type of 22 is a handshake, and a handshake type of 1 a ClientHello.
+  if { $tls_content_type == 22 } {
+    binary scan [TCP::payload] @5c tls_handshake_type
+    if { $tls_handshake_type == 1 } {
+      # Skip the handshake length, the version and the random data, then the session
+      # ID, the cipher suites and the compression methods.
+      set offset 43
+      binary scan [TCP::payload] @${offset}c session_id_len
+      incr offset [expr {1 + $session_id_len}]
+      binary scan [TCP::payload] @${offset}S cipher_suites_len
+      incr offset [expr {2 + $cipher_suites_len}]
+      binary scan [TCP::payload] @${offset}c compression_methods_len
+      incr offset [expr {1 + $compression_methods_len}]
+      binary scan [TCP::payload] @${offset}S extensions_len
+      incr offset 2
+      binary scan [TCP::payload] @${offset}a* extensions
+      for { set start 0 } { $start < $extensions_len } { incr start 4 } {
+        binary scan $extensions @${start}SS extension_type extension_len
+        # Extension type 0 is the server name, of which type 0 is a host name.
+        if { $extension_type == 0 } {
+          binary scan $extensions @[expr {$start + 6}]c sni_type
+          if { $sni_type == 0 } {
+            binary scan $extensions @[expr {$start + 7}]S sni_len
+            binary scan $extensions @[expr {$start + 9}]A${sni_len} servername
+          }
+        }
+        incr start $extension_len
+      }
+      if { [info exists servername] } {
+        set servername [string tolower $servername]
+        if { [class match $servername equals ssl_passthrough_servername_dg] } {
+          pool [class match -value $servername equals ssl_passthrough_servername_dg]
+          SSL::disable
+          HTTP::disable
+        }
+      }
+    }
+  }
+  TCP::release
+}
+`
+
+// reencryptIRule selects the server SSL profile of the reencrypt route a request is for,
+// and disables TLS towards the pools of edge routes.
+const reencryptIRule = `
+when HTTP_REQUEST {
+  set servername [string tolower [getfield [HTTP::host] ":" 1]]
+  if { [class match $servername equals ssl_reencrypt_servername_dg] } {
+    set serverssl_profile [class match -value $servername equals ssl_reencrypt_servername_dg]
+  } else {
+    unset -nocomplain serverssl_profile
+  }
+}
+
+when SERVER_CONNECTED {
+  if { [info exists serverssl_profile] } {
+    SSL::profile $serverssl_profile
+  } else {
+    SSL::disable serverside
+  }
+}
+`
+
+// f5LTMCfg holds the configuration of the connection to the BIG-IP.
+type f5LTMCfg struct {
+	// host is the host, and optional port, of the management interface of the BIG-IP
+	host string
+	// username and password authenticate the requests to the iControl REST API
+	username string
+	password string
+	// httpVserver and httpsVserver are the names of the virtual servers the routes are
+	// served on. They are created by the administrator of the BIG-IP.
+	httpVserver  string
+	httpsVserver string
+	// insecure disables the verification of the certificate of the BIG-IP
+	insecure bool
+}
+
+// f5LTM is a client of the iControl REST API of the Local Traffic Manager of a BIG-IP.
+type f5LTM struct {
+	f5LTMCfg
+	client *http.Client
+}
+
+// F5Error is an error returned by the iControl REST API.
+type F5Error struct {
+	verb           string
+	url            string
+	httpStatusCode int
+
+	// Code and Message are returned by the BIG-IP in the body of the response
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (err F5Error) Error() string {
+	if len(err.Message) > 0 {
+		return fmt.Sprintf("%s %s failed with status %d: %s", err.verb, err.url, err.httpStatusCode, err.Message)
+	}
+	return fmt.Sprintf("%s %s failed with status %d", err.verb, err.url, err.httpStatusCode)
+}
+
+// isNotFound returns true if the BIG-IP reported the resource of a request does not exist.
+func isNotFound(err error) bool {
+	f5err, ok := err.(F5Error)
+	return ok && f5err.httpStatusCode == http.StatusNotFound
+}
+
+// isConflict returns true if the BIG-IP reported the resource of a request already exists.
+func isConflict(err error) bool {
+	f5err, ok := err.(F5Error)
+	return ok && f5err.httpStatusCode == http.StatusConflict
+}
+
+// newF5LTM creates a client for the BIG-IP described by cfg.
+func newF5LTM(cfg f5LTMCfg) *f5LTM {
+	transport := &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: cfg.insecure},
+	}
+	return &f5LTM{f5LTMCfg: cfg, client: &http.Client{Transport: transport}}
+}
+
+// url returns the URL of a path of the iControl REST API.
+func (f5 *f5LTM) url(format string, args ...interface{}) string {
+	return fmt.Sprintf("https://%s/mgmt/%s", f5.host, fmt.Sprintf(format, args...))
+}
+
+// request sends a request to the iControl REST API, and decodes the JSON body of the
+// response into result unless it is nil.
+func (f5 *f5LTM) request(verb, url string, body io.Reader, contentType string, header http.Header, result interface{}) error {
+	req, err := http.NewRequest(verb, url, body)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(f5.username, f5.password)
+	req.Header.Set("Content-Type", contentType)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	glog.V(5).Infof("Sending %s request to %s", verb, url)
+	resp, err := f5.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %v", verb, url, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %v", verb, url, err)
+	}
+	if resp.StatusCode >= 400 {
+		f5err := F5Error{}
+		json.Unmarshal(data, &f5err)
+		f5err.verb, f5err.url, f5err.httpStatusCode = verb, url, resp.StatusCode
+		return f5err
+	}
+	if result != nil {
+		if err := json.Unmarshal(data, result); err != nil {
+			return fmt.Errorf("unable to decode the response of %s %s: %v", verb, url, err)
+		}
+	}
+	return nil
+}
+
+// restRequest sends payload as JSON to the iControl REST API.
+func (f5 *f5LTM) restRequest(verb, url string, payload, result interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	return f5.request(verb, url, body, "application/json", nil, result)
+}
+
+func (f5 *f5LTM) get(url string, result interface{}) error {
+	return f5.restRequest("GET", url, nil, result)
+}
+
+func (f5 *f5LTM) post(url string, payload interface{}) error {
+	return f5.restRequest("POST", url, payload, nil)
+}
+
+func (f5 *f5LTM) patch(url string, payload interface{}) error {
+	return f5.restRequest("PATCH", url, payload, nil)
+}
+
+func (f5 *f5LTM) delete(url string) error {
+	return f5.restRequest("DELETE", url, nil, nil)
+}
+
+// create creates a resource, and succeeds if it already exists.
+func (f5 *f5LTM) create(url string, payload interface{}) error {
+	if err := f5.post(url, payload); err != nil && !isConflict(err) {
+		return err
+	}
+	return nil
+}
+
+// createOrUpdate creates the resource of a collection named name, and updates it with
+// payload if it already exists.
+func (f5 *f5LTM) createOrUpdate(collection, name string, payload interface{}) error {
+	err := f5.post(collection, payload)
+	if isConflict(err) {
+		return f5.patch(collection+"/"+name, payload)
+	}
+	return err
+}
+
+// remove deletes a resource, and succeeds if it does not exist.
+func (f5 *f5LTM) remove(url string) error {
+	if err := f5.delete(url); err != nil && !isNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// f5Item is the name of an item of a collection of the iControl REST API.
+type f5Item struct {
+	Name string `json:"name"`
+}
+
+// f5Collection is a collection of the iControl REST API.
+type f5Collection struct {
+	Items []f5Item `json:"items"`
+}
+
+// names returns the names of the items of the collection at url.
+func (f5 *f5LTM) names(url string) ([]string, error) {
+	collection := f5Collection{}
+	if err := f5.get(url, &collection); err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, item := range collection.Items {
+		names = append(names, item.Name)
+	}
+	return names, nil
+}
+
+// commonName returns the full path of a resource of the Common partition.
+func commonName(name string) string {
+	return "/Common/" + name
+}
+
+// sameName returns true if name designates the resource of the Common partition named
+// expected, with or without its partition.
+func sameName(name, expected string) bool {
+	return name == expected || name == commonName(expected)
+}
+
+// Initialize creates the policies, data groups and iRules routes are configured with, and
+// attaches them to the virtual servers.
+func (f5 *f5LTM) Initialize() error {
+	for _, policy := range []struct{ name, vserver string }{
+		{insecureRoutesPolicyName, f5.httpVserver},
+		{secureRoutesPolicyName, f5.httpsVserver},
+	} {
+		if err := f5.ensurePolicyExists(policy.name); err != nil {
+			return err
+		}
+		if err := f5.ensureVserverHasPolicy(policy.vserver, policy.name); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range []string{passthroughDatagroupName, reencryptDatagroupName} {
+		if err := f5.create(f5.url("tm/ltm/data-group/internal"), map[string]string{"name": name, "type": "string"}); err != nil {
+			return err
+		}
+	}
+
+	for _, rule := range []struct{ name, code string }{
+		{passthroughIRuleName, passthroughIRule},
+		{reencryptIRuleName, reencryptIRule},
+	} {
+		if err := f5.create(f5.url("tm/ltm/rule"), map[string]string{"name": rule.name, "apiAnonymous": rule.code}); err != nil {
+			return err
+		}
+		if err := f5.ensureVserverHasIRule(f5.httpsVserver, rule.name); err != nil {
+			return err
+		}
+	}
+
+	glog.V(4).Infof("Initialized the BIG-IP at %s", f5.host)
+	return nil
+}
+
+// ensurePolicyExists creates a policy forwarding requests to pools, which applies the rule
+// with the most specific conditions matching a request.
+func (f5 *f5LTM) ensurePolicyExists(name string) error {
+	return f5.create(f5.url("tm/ltm/policy"), map[string]interface{}{
+		"name":     name,
+		"controls": []string{"forwarding"},
+		"requires": []string{"http"},
+		"strategy": "/Common/best-match",
+	})
+}
+
+// ensureVserverHasPolicy attaches a policy to a virtual server.
+func (f5 *f5LTM) ensureVserverHasPolicy(vserver, policy string) error {
+	url := f5.url("tm/ltm/virtual/%s/policies", vserver)
+	policies, err := f5.names(url)
+	if err != nil {
+		return err
+	}
+	for _, name := range policies {
+		if sameName(name, policy) {
+			return nil
+		}
+	}
+	return f5.post(url, f5Item{Name: policy})
+}
+
+// f5Vserver holds the iRules of a virtual server.
+type f5Vserver struct {
+	Rules []string `json:"rules"`
+}
+
+// ensureVserverHasIRule attaches an iRule to a virtual server.
+func (f5 *f5LTM) ensureVserverHasIRule(vserver, rule string) error {
+	url := f5.url("tm/ltm/virtual/%s", vserver)
+	current := f5Vserver{}
+	if err := f5.get(url, &current); err != nil {
+		return err
+	}
+	for _, name := range current.Rules {
+		if sameName(name, rule) {
+			return nil
+		}
+	}
+	return f5.patch(url, f5Vserver{Rules: append(current.Rules, commonName(rule))})
+}
+
+// EnsurePoolExists creates a pool, monitored by the default HTTP and HTTPS monitors.
+func (f5 *f5LTM) EnsurePoolExists(pool string) error {
+	return f5.create(f5.url("tm/ltm/pool"), map[string]string{
+		"name":    pool,
+		"monitor": "min 1 of /Common/http /Common/https",
+	})
+}
+
+// DeletePool deletes a pool and its members.
+func (f5 *f5LTM) DeletePool(pool string) error {
+	return f5.remove(f5.url("tm/ltm/pool/%s", pool))
+}
+
+// SyncPoolMembers sets the members of a pool, as "<ip>:<port>".
+func (f5 *f5LTM) SyncPoolMembers(pool string, members []string) error {
+	url := f5.url("tm/ltm/pool/%s/members", pool)
+	current, err := f5.names(url)
+	if err != nil {
+		return err
+	}
+
+	existing := map[string]bool{}
+	for _, member := range current {
+		existing[strings.TrimPrefix(member, commonName(""))] = true
+	}
+	desired := map[string]bool{}
+	for _, member := range members {
+		desired[member] = true
+		if !existing[member] {
+			if err := f5.create(url, f5Item{Name: member}); err != nil {
+				return err
+			}
+		}
+	}
+	for member := range existing {
+		if !desired[member] {
+			if err := f5.remove(fmt.Sprintf("%s/%s", url, member)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// f5PolicyCondition is a condition of a rule of a policy.
+type f5PolicyCondition struct {
+	Name       string   `json:"name"`
+	HTTPHost   bool     `json:"httpHost,omitempty"`
+	Host       bool     `json:"host,omitempty"`
+	HTTPURI    bool     `json:"httpUri,omitempty"`
+	Path       bool     `json:"path,omitempty"`
+	Equals     bool     `json:"equals,omitempty"`
+	EndsWith   bool     `json:"endsWith,omitempty"`
+	StartsWith bool     `json:"startsWith,omitempty"`
+	Request    bool     `json:"request"`
+	Values     []string `json:"values"`
+}
+
+// f5PolicyAction is an action of a rule of a policy.
+type f5PolicyAction struct {
+	Name    string `json:"name"`
+	Forward bool   `json:"forward"`
+	Pool    string `json:"pool"`
+	Request bool   `json:"request"`
+}
+
+// f5PolicyRule is a rule of a policy.
+type f5PolicyRule struct {
+	Name       string              `json:"name"`
+	Conditions []f5PolicyCondition `json:"conditions"`
+	Actions    []f5PolicyAction    `json:"actions"`
+}
+
+// AddRule adds or updates a rule of a policy forwarding the requests for host, or for any
+// host of its subdomain if wildcard is true, and whose path starts with path, to a pool.
+func (f5 *f5LTM) AddRule(policy, rule, pool, host, path string, wildcard bool) error {
+	hostCondition := f5PolicyCondition{Name: "0", HTTPHost: true, Host: true, Request: true}
+	if wildcard {
+		i := strings.Index(host, ".")
+		if i == -1 {
+			return fmt.Errorf("the host %q of a wildcard route has no subdomain", host)
+		}
+		hostCondition.EndsWith = true
+		hostCondition.Values = []string{host[i:]}
+	} else {
+		hostCondition.Equals = true
+		hostCondition.Values = []string{host}
+	}
+	conditions := []f5PolicyCondition{hostCondition}
+	if len(path) > 0 {
+		conditions = append(conditions, f5PolicyCondition{Name: "1", HTTPURI: true, Path: true, StartsWith: true, Request: true, Values: []string{path}})
+	}
+
+	return f5.createOrUpdate(f5.url("tm/ltm/policy/%s/rules", policy), rule, f5PolicyRule{
+		Name:       rule,
+		Conditions: conditions,
+		Actions:    []f5PolicyAction{{Name: "0", Forward: true, Pool: commonName(pool), Request: true}},
+	})
+}
+
+// DeleteRule deletes a rule of a policy.
+func (f5 *f5LTM) DeleteRule(policy, rule string) error {
+	return f5.remove(f5.url("tm/ltm/policy/%s/rules/%s", policy, rule))
+}
+
+// f5DatagroupRecord is a record of a data group.
+type f5DatagroupRecord struct {
+	Name string `json:"name"`
+	Data string `json:"data"`
+}
+
+// f5Datagroup is an internal data group.
+type f5Datagroup struct {
+	Records []f5DatagroupRecord `json:"records"`
+}
+
+// updateDatagroup sets or, if value is empty, removes the record of a data group for key.
+func (f5 *f5LTM) updateDatagroup(datagroup, key, value string) error {
+	url := f5.url("tm/ltm/data-group/internal/%s", datagroup)
+	current := f5Datagroup{}
+	if err := f5.get(url, &current); err != nil {
+		return err
+	}
+
+	records := []f5DatagroupRecord{}
+	for _, record := range current.Records {
+		if record.Name != key {
+			records = append(records, record)
+		}
+	}
+	if len(value) > 0 {
+		records = append(records, f5DatagroupRecord{Name: key, Data: value})
+	}
+	return f5.patch(url, f5Datagroup{Records: records})
+}
+
+// AddPassthroughRoute forwards the TLS connections for host to a pool.
+func (f5 *f5LTM) AddPassthroughRoute(host, pool string) error {
+	return f5.updateDatagroup(passthroughDatagroupName, host, pool)
+}
+
+// DeletePassthroughRoute stops forwarding the TLS connections for host.
+func (f5 *f5LTM) DeletePassthroughRoute(host string) error {
+	return f5.updateDatagroup(passthroughDatagroupName, host, "")
+}
+
+// AddReencryptRoute connects to the pool of the requests for host with a server SSL profile.
+func (f5 *f5LTM) AddReencryptRoute(host, profile string) error {
+	return f5.updateDatagroup(reencryptDatagroupName, host, commonName(profile))
+}
+
+// DeleteReencryptRoute stops connecting to the pool of the requests for host with TLS.
+func (f5 *f5LTM) DeleteReencryptRoute(host string) error {
+	return f5.updateDatagroup(reencryptDatagroupName, host, "")
+}
+
+// uploadFile uploads the content of a file to the upload directory of the BIG-IP.
+func (f5 *f5LTM) uploadFile(name, content string) error {
+	header := http.Header{}
+	header.Set("Content-Range", fmt.Sprintf("0-%d/%d", len(content)-1, len(content)))
+	return f5.request("POST", f5.url("shared/file-transfer/uploads/%s", name), strings.NewReader(content), "application/octet-stream", header, nil)
+}
+
+// installCertificate uploads and installs a PEM encoded certificate.
+func (f5 *f5LTM) installCertificate(name, pem string) error {
+	file := name + ".crt"
+	if err := f5.uploadFile(file, pem); err != nil {
+		return err
+	}
+	return f5.post(f5.url("tm/sys/crypto/cert"), map[string]string{
+		"command":         "install",
+		"name":            file,
+		"from-local-file": uploadPath + "/" + file,
+	})
+}
+
+// installKey uploads and installs a PEM encoded private key.
+func (f5 *f5LTM) installKey(name, pem string) error {
+	file := name + ".key"
+	if err := f5.uploadFile(file, pem); err != nil {
+		return err
+	}
+	return f5.post(f5.url("tm/sys/crypto/key"), map[string]string{
+		"command":         "install",
+		"name":            file,
+		"from-local-file": uploadPath + "/" + file,
+	})
+}
+
+// deleteCertificate deletes an installed certificate.
+func (f5 *f5LTM) deleteCertificate(name string) error {
+	return f5.remove(f5.url("tm/sys/file/ssl-cert/%s.crt", name))
+}
+
+// deleteKey deletes an installed private key.
+func (f5 *f5LTM) deleteKey(name string) error {
+	return f5.remove(f5.url("tm/sys/file/ssl-key/%s.key", name))
+}
+
+// attachProfile attaches a profile to the HTTPS virtual server, on the client or the
+// server side of connections.
+func (f5 *f5LTM) attachProfile(profile, context string) error {
+	return f5.create(f5.url("tm/ltm/virtual/%s/profiles", f5.httpsVserver), map[string]string{
+		"name":    profile,
+		"context": context,
+	})
+}
+
+// AddClientSSLProfile installs the certificate, key and optional CA certificate of a
+// route, and serves them for host on the HTTPS virtual server.
+func (f5 *f5LTM) AddClientSSLProfile(profile, host, certificate, key, caCertificate string) error {
+	if err := f5.installCertificate(profile, certificate); err != nil {
+		return err
+	}
+	if err := f5.installKey(profile, key); err != nil {
+		return err
+	}
+	settings := map[string]interface{}{
+		"name":         profile,
+		"defaultsFrom": "/Common/clientssl",
+		"cert":         commonName(profile + ".crt"),
+		"key":          commonName(profile + ".key"),
+		"serverName":   host,
+		"sniDefault":   false,
+	}
+	if len(caCertificate) > 0 {
+		chain := profile + "-chain"
+		if err := f5.installCertificate(chain, caCertificate); err != nil {
+			return err
+		}
+		settings["chain"] = commonName(chain + ".crt")
+	}
+	if err := f5.createOrUpdate(f5.url("tm/ltm/profile/client-ssl"), profile, settings); err != nil {
+		return err
+	}
+	return f5.attachProfile(profile, "clientside")
+}
+
+// DeleteClientSSLProfile detaches and deletes a client SSL profile, and its certificates
+// and key.
+func (f5 *f5LTM) DeleteClientSSLProfile(profile string) error {
+	if err := f5.remove(f5.url("tm/ltm/virtual/%s/profiles/%s", f5.httpsVserver, profile)); err != nil {
+		return err
+	}
+	if err := f5.remove(f5.url("tm/ltm/profile/client-ssl/%s", profile)); err != nil {
+		return err
+	}
+	for _, err := range []error{f5.deleteCertificate(profile), f5.deleteKey(profile), f5.deleteCertificate(profile + "-chain")} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddServerSSLProfile installs the CA certificate the endpoints of a route are verified
+// with, and creates a server SSL profile verifying them.
+func (f5 *f5LTM) AddServerSSLProfile(profile, destinationCACertificate string) error {
+	settings := map[string]interface{}{
+		"name":         profile,
+		"defaultsFrom": "/Common/serverssl",
+	}
+	if len(destinationCACertificate) > 0 {
+		if err := f5.installCertificate(profile, destinationCACertificate); err != nil {
+			return err
+		}
+		settings["caFile"] = commonName(profile + ".crt")
+		settings["peerCertMode"] = "require"
+	}
+	if err := f5.createOrUpdate(f5.url("tm/ltm/profile/server-ssl"), profile, settings); err != nil {
+		return err
+	}
+	return f5.attachProfile(profile, "serverside")
+}
+
+// DeleteServerSSLProfile detaches and deletes a server SSL profile and its CA certificate.
+func (f5 *f5LTM) DeleteServerSSLProfile(profile string) error {
+	if err := f5.remove(f5.url("tm/ltm/virtual/%s/profiles/%s", f5.httpsVserver, profile)); err != nil {
+		return err
+	}
+	if err := f5.remove(f5.url("tm/ltm/profile/server-ssl/%s", profile)); err != nil {
+		return err
+	}
+	return f5.deleteCertificate(profile)
+}
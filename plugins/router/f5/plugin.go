@@ -0,0 +1,262 @@
+package f5
+
+import (
+	"fmt"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kutil "github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/golang/glog"
+
+	routeapi "github.com/openshift/origin/pkg/route/api"
+)
+
+// F5PluginConfig holds the configuration of an F5Plugin.
+type F5PluginConfig struct {
+	// Host is the host, and optional port, of the management interface of the BIG-IP
+	Host string
+	// Username and Password authenticate the requests to the iControl REST API
+	Username string
+	Password string
+	// HTTPVserver and HTTPSVserver are the names of the virtual servers the routes are
+	// served on. They must be created by the administrator of the BIG-IP.
+	HTTPVserver  string
+	HTTPSVserver string
+	// Insecure disables the verification of the certificate of the BIG-IP
+	Insecure bool
+}
+
+// F5Plugin implements the router.Plugin interface to configure an F5 BIG-IP through its
+// iControl REST API. Each service with routes gets a pool of its endpoints. The requests of
+// unsecured, edge and reencrypt routes are forwarded to the pools by policies of the HTTP
+// and HTTPS virtual servers, and the connections of passthrough routes by an iRule of the
+// HTTPS virtual server.
+type F5Plugin struct {
+	F5Client *f5LTM
+
+	// committed, if not nil, is called each time a change is applied to the BIG-IP
+	committed func()
+
+	// routes holds the routes configured on the BIG-IP, by namespace and name
+	routes map[string]*routeapi.Route
+	// members holds the pool members of the endpoints of each service, by pool name
+	members map[string][]string
+}
+
+// NewF5Plugin creates a new F5Plugin and initializes the policies, data groups and iRules of
+// the BIG-IP. committed, if not nil, is called each time a change was successfully
+// applied to the BIG-IP.
+func NewF5Plugin(cfg F5PluginConfig, committed func()) (*F5Plugin, error) {
+	if len(cfg.Host) == 0 {
+		return nil, fmt.Errorf("the host of the BIG-IP must be specified")
+	}
+	if len(cfg.HTTPVserver) == 0 || len(cfg.HTTPSVserver) == 0 {
+		return nil, fmt.Errorf("the HTTP and HTTPS virtual servers must be specified")
+	}
+
+	client := newF5LTM(f5LTMCfg{
+		host:         cfg.Host,
+		username:     cfg.Username,
+		password:     cfg.Password,
+		httpVserver:  cfg.HTTPVserver,
+		httpsVserver: cfg.HTTPSVserver,
+		insecure:     cfg.Insecure,
+	})
+	if err := client.Initialize(); err != nil {
+		return nil, err
+	}
+
+	return &F5Plugin{
+		F5Client:  client,
+		committed: committed,
+		routes:    map[string]*routeapi.Route{},
+		members:   map[string][]string{},
+	}, nil
+}
+
+// HandleEndpoints processes watch events on the Endpoints resource. The pools of services
+// are only kept on the BIG-IP while routes use them.
+func (p *F5Plugin) HandleEndpoints(eventType watch.EventType, endpoints *kapi.Endpoints) error {
+	pool := poolName(endpoints.Namespace, endpoints.Name)
+
+	switch eventType {
+	case watch.Added, watch.Modified:
+		p.members[pool] = poolMembers(endpoints)
+	case watch.Deleted:
+		delete(p.members, pool)
+	}
+
+	if !p.poolInUse(pool) {
+		return nil
+	}
+	glog.V(4).Infof("Updating the members of pool %s", pool)
+	if err := p.F5Client.SyncPoolMembers(pool, p.members[pool]); err != nil {
+		return err
+	}
+	p.notifyCommitted()
+	return nil
+}
+
+// HandleRoute processes watch events on the Route resource.
+func (p *F5Plugin) HandleRoute(eventType watch.EventType, route *routeapi.Route) error {
+	key := routeKey(route)
+
+	// the configuration of the route is recreated when it is modified
+	if old, ok := p.routes[key]; ok {
+		glog.V(4).Infof("Deleting the configuration of route %s", key)
+		if err := p.deleteRoute(old); err != nil {
+			return err
+		}
+		delete(p.routes, key)
+		if pool := poolName(old.Namespace, old.ServiceName); !p.poolInUse(pool) {
+			if err := p.F5Client.DeletePool(pool); err != nil {
+				return err
+			}
+		}
+	}
+
+	switch eventType {
+	case watch.Added, watch.Modified:
+		glog.V(4).Infof("Adding the configuration of route %s", key)
+		if err := p.addRoute(route); err != nil {
+			// remove what was configured, the route is configured again on its next event
+			p.deleteRoute(route)
+			return err
+		}
+		p.routes[key] = route
+	}
+
+	p.notifyCommitted()
+	return nil
+}
+
+// addRoute configures the BIG-IP to serve a route, and creates the pool of its service.
+func (p *F5Plugin) addRoute(route *routeapi.Route) error {
+	pool := poolName(route.Namespace, route.ServiceName)
+	if err := p.F5Client.EnsurePoolExists(pool); err != nil {
+		return err
+	}
+	if err := p.F5Client.SyncPoolMembers(pool, p.members[pool]); err != nil {
+		return err
+	}
+
+	name := routeName(route)
+	wildcard := route.WildcardPolicy == routeapi.WildcardPolicySubdomain
+	if route.TLS == nil || len(route.TLS.Termination) == 0 {
+		return p.F5Client.AddRule(insecureRoutesPolicyName, name, pool, route.Host, route.Path, wildcard)
+	}
+
+	switch route.TLS.Termination {
+	case routeapi.TLSTerminationPassthrough:
+		// the server names of passthrough routes are matched exactly
+		if wildcard {
+			return fmt.Errorf("wildcard passthrough routes are not supported")
+		}
+		return p.F5Client.AddPassthroughRoute(route.Host, pool)
+
+	case routeapi.TLSTerminationEdge, routeapi.TLSTerminationReencrypt:
+		if len(route.TLS.Certificate) > 0 {
+			if err := p.F5Client.AddClientSSLProfile(clientSSLProfileName(route), route.Host, route.TLS.Certificate, route.TLS.Key, route.TLS.CACertificate); err != nil {
+				return err
+			}
+		}
+		if route.TLS.Termination == routeapi.TLSTerminationReencrypt {
+			profile := serverSSLProfileName(route)
+			if err := p.F5Client.AddServerSSLProfile(profile, route.TLS.DestinationCACertificate); err != nil {
+				return err
+			}
+			if err := p.F5Client.AddReencryptRoute(route.Host, profile); err != nil {
+				return err
+			}
+		}
+		return p.F5Client.AddRule(secureRoutesPolicyName, name, pool, route.Host, route.Path, wildcard)
+	}
+
+	return fmt.Errorf("unsupported TLS termination %q", route.TLS.Termination)
+}
+
+// deleteRoute removes the configuration of a route from the BIG-IP.
+func (p *F5Plugin) deleteRoute(route *routeapi.Route) error {
+	name := routeName(route)
+	if route.TLS == nil || len(route.TLS.Termination) == 0 {
+		return p.F5Client.DeleteRule(insecureRoutesPolicyName, name)
+	}
+
+	switch route.TLS.Termination {
+	case routeapi.TLSTerminationPassthrough:
+		return p.F5Client.DeletePassthroughRoute(route.Host)
+
+	case routeapi.TLSTerminationEdge, routeapi.TLSTerminationReencrypt:
+		if err := p.F5Client.DeleteRule(secureRoutesPolicyName, name); err != nil {
+			return err
+		}
+		if route.TLS.Termination == routeapi.TLSTerminationReencrypt {
+			if err := p.F5Client.DeleteReencryptRoute(route.Host); err != nil {
+				return err
+			}
+			if err := p.F5Client.DeleteServerSSLProfile(serverSSLProfileName(route)); err != nil {
+				return err
+			}
+		}
+		return p.F5Client.DeleteClientSSLProfile(clientSSLProfileName(route))
+	}
+
+	return nil
+}
+
+// poolInUse returns true if a route configured on the BIG-IP uses the pool.
+func (p *F5Plugin) poolInUse(pool string) bool {
+	for _, route := range p.routes {
+		if poolName(route.Namespace, route.ServiceName) == pool {
+			return true
+		}
+	}
+	return false
+}
+
+// notifyCommitted reports a change was applied to the BIG-IP.
+func (p *F5Plugin) notifyCommitted() {
+	if p.committed != nil {
+		p.committed()
+	}
+}
+
+// routeKey returns the key of a route in the routes of the plugin.
+func routeKey(route *routeapi.Route) string {
+	return fmt.Sprintf("%s/%s", route.Namespace, route.Name)
+}
+
+// poolName returns the name of the pool of a service.
+func poolName(namespace, service string) string {
+	return fmt.Sprintf("openshift_%s_%s", namespace, service)
+}
+
+// routeName returns the name of the policy rule of a route.
+func routeName(route *routeapi.Route) string {
+	return fmt.Sprintf("openshift_route_%s_%s", route.Namespace, route.Name)
+}
+
+// clientSSLProfileName returns the name of the client SSL profile serving the certificate
+// of a route.
+func clientSSLProfileName(route *routeapi.Route) string {
+	return routeName(route) + "-https-profile"
+}
+
+// serverSSLProfileName returns the name of the server SSL profile connecting to the
+// endpoints of a reencrypt route.
+func serverSSLProfileName(route *routeapi.Route) string {
+	return routeName(route) + "-server-ssl-profile"
+}
+
+// poolMembers returns the pool members of endpoints, as "<ip>:<port>".
+func poolMembers(endpoints *kapi.Endpoints) []string {
+	members := kutil.NewStringSet()
+	for _, subset := range endpoints.Subsets {
+		for _, address := range subset.Addresses {
+			for _, port := range subset.Ports {
+				members.Insert(fmt.Sprintf("%s:%d", address.IP, port.Port))
+			}
+		}
+	}
+	return members.List()
+}
@@ -0,0 +1,510 @@
+package f5
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+
+	routeapi "github.com/openshift/origin/pkg/route/api"
+)
+
+// topLevelCollections are the collections of the iControl REST API the plugin creates
+// resources in, besides the collections of existing resources such as pool members.
+var topLevelCollections = map[string]bool{
+	"tm/ltm/pool":                true,
+	"tm/ltm/policy":              true,
+	"tm/ltm/rule":                true,
+	"tm/ltm/data-group/internal": true,
+	"tm/ltm/profile/client-ssl":  true,
+	"tm/ltm/profile/server-ssl":  true,
+}
+
+// fakeF5 is an in-memory fake of the iControl REST API of a BIG-IP. Resources are stored
+// as JSON objects by path, and the items of a collection are the resources under its path.
+type fakeF5 struct {
+	lock      sync.Mutex
+	resources map[string]map[string]interface{}
+	uploads   map[string]string
+}
+
+func newFakeF5() *fakeF5 {
+	return &fakeF5{
+		resources: map[string]map[string]interface{}{
+			"tm/ltm/virtual/ose-vserver":       {"name": "ose-vserver"},
+			"tm/ltm/virtual/ose-vserver-https": {"name": "ose-vserver-https"},
+		},
+		uploads: map[string]string{},
+	}
+}
+
+func (f *fakeF5) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if username, password, ok := req.BasicAuth(); !ok || username != "admin" || password != "secret" {
+		f.fail(w, http.StatusUnauthorized, "Authorization failed")
+		return
+	}
+	p := strings.TrimPrefix(req.URL.Path, "/mgmt/")
+	body, _ := ioutil.ReadAll(req.Body)
+
+	if dir, name := path.Split(p); req.Method == "POST" && dir == "shared/file-transfer/uploads/" {
+		f.uploads[name] = string(body)
+		return
+	}
+
+	payload := map[string]interface{}{}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &payload); err != nil {
+			f.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	switch req.Method {
+	case "GET":
+		if resource, ok := f.resources[p]; ok {
+			json.NewEncoder(w).Encode(resource)
+			return
+		}
+		if !f.isCollection(p) {
+			f.fail(w, http.StatusNotFound, "not found")
+			return
+		}
+		items := []map[string]interface{}{}
+		for _, key := range f.children(p) {
+			items = append(items, f.resources[key])
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"items": items})
+
+	case "POST":
+		switch p {
+		case "tm/sys/crypto/cert", "tm/sys/crypto/key":
+			file := path.Base(payload["from-local-file"].(string))
+			if payload["command"] != "install" || len(f.uploads[file]) == 0 {
+				f.fail(w, http.StatusBadRequest, "no uploaded file "+file)
+				return
+			}
+			collection := "tm/sys/file/ssl-cert"
+			if p == "tm/sys/crypto/key" {
+				collection = "tm/sys/file/ssl-key"
+			}
+			f.resources[collection+"/"+payload["name"].(string)] = map[string]interface{}{"name": payload["name"], "content": f.uploads[file]}
+			return
+		}
+		if !f.isCollection(p) {
+			f.fail(w, http.StatusNotFound, "not found")
+			return
+		}
+		key := p + "/" + payload["name"].(string)
+		if _, ok := f.resources[key]; ok {
+			f.fail(w, http.StatusConflict, "already exists")
+			return
+		}
+		if missing := f.missingReferences(payload); len(missing) > 0 {
+			f.fail(w, http.StatusBadRequest, "missing "+missing)
+			return
+		}
+		f.resources[key] = payload
+
+	case "PATCH":
+		resource, ok := f.resources[p]
+		if !ok {
+			f.fail(w, http.StatusNotFound, "not found")
+			return
+		}
+		for k, v := range payload {
+			resource[k] = v
+		}
+
+	case "DELETE":
+		if _, ok := f.resources[p]; !ok {
+			f.fail(w, http.StatusNotFound, "not found")
+			return
+		}
+		if f.referenced(p) {
+			f.fail(w, http.StatusBadRequest, "in use")
+			return
+		}
+		for _, key := range append(f.children(p), p) {
+			delete(f.resources, key)
+		}
+	}
+}
+
+func (f *fakeF5) fail(w http.ResponseWriter, code int, message string) {
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]interface{}{"code": code, "message": message})
+}
+
+func (f *fakeF5) isCollection(p string) bool {
+	_, ok := f.resources[path.Dir(p)]
+	return ok || topLevelCollections[p]
+}
+
+// children returns the paths of the items of a collection, or of the subcollections of
+// a resource.
+func (f *fakeF5) children(p string) []string {
+	keys := []string{}
+	for key := range f.resources {
+		if strings.HasPrefix(key, p+"/") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// references returns the paths of the resources a payload refers to.
+func references(payload map[string]interface{}) []string {
+	refs := []string{}
+	for field, collection := range map[string]string{"cert": "tm/sys/file/ssl-cert", "key": "tm/sys/file/ssl-key", "chain": "tm/sys/file/ssl-cert", "caFile": "tm/sys/file/ssl-cert"} {
+		if name, ok := payload[field].(string); ok {
+			refs = append(refs, collection+"/"+strings.TrimPrefix(name, "/Common/"))
+		}
+	}
+	if actions, ok := payload["actions"].([]interface{}); ok {
+		for _, action := range actions {
+			if pool, ok := action.(map[string]interface{})["pool"].(string); ok {
+				refs = append(refs, "tm/ltm/pool/"+strings.TrimPrefix(pool, "/Common/"))
+			}
+		}
+	}
+	return refs
+}
+
+func (f *fakeF5) missingReferences(payload map[string]interface{}) string {
+	for _, ref := range references(payload) {
+		if _, ok := f.resources[ref]; !ok {
+			return ref
+		}
+	}
+	return ""
+}
+
+func (f *fakeF5) referenced(p string) bool {
+	for _, resource := range f.resources {
+		for _, ref := range references(resource) {
+			if ref == p {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// exists returns true if a resource exists at each path.
+func (f *fakeF5) exists(paths ...string) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	for _, p := range paths {
+		if _, ok := f.resources[p]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// names returns the names of the items of a collection.
+func (f *fakeF5) names(p string) []string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	names := []string{}
+	for _, key := range f.children(p) {
+		if path.Dir(key) == p {
+			names = append(names, path.Base(key))
+		}
+	}
+	return names
+}
+
+// records returns the records of a data group.
+func (f *fakeF5) records(datagroup string) map[string]string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	records := map[string]string{}
+	data, _ := json.Marshal(f.resources["tm/ltm/data-group/internal/"+datagroup])
+	dg := f5Datagroup{}
+	json.Unmarshal(data, &dg)
+	for _, record := range dg.Records {
+		records[record.Name] = record.Data
+	}
+	return records
+}
+
+func newTestPlugin(t *testing.T) (*F5Plugin, *fakeF5, *int, func()) {
+	fake := newFakeF5()
+	server := httptest.NewTLSServer(fake)
+	plugin, commits, err := startTestPlugin(server)
+	if err != nil {
+		server.Close()
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return plugin, fake, commits, server.Close
+}
+
+// startTestPlugin starts a plugin configuring the BIG-IP served by server.
+func startTestPlugin(server *httptest.Server) (*F5Plugin, *int, error) {
+	commits := 0
+	plugin, err := NewF5Plugin(F5PluginConfig{
+		Host:         server.Listener.Addr().String(),
+		Username:     "admin",
+		Password:     "secret",
+		HTTPVserver:  "ose-vserver",
+		HTTPSVserver: "ose-vserver-https",
+		Insecure:     true,
+	}, func() { commits++ })
+	return plugin, &commits, err
+}
+
+func newRoute(name, host, path string, tls *routeapi.TLSConfig) *routeapi.Route {
+	route := &routeapi.Route{Host: host, Path: path, ServiceName: "svc", TLS: tls}
+	route.Namespace = "ns"
+	route.Name = name
+	return route
+}
+
+func newEndpoints(ips ...string) *kapi.Endpoints {
+	endpoints := &kapi.Endpoints{}
+	endpoints.Namespace = "ns"
+	endpoints.Name = "svc"
+	subset := kapi.EndpointSubset{Ports: []kapi.EndpointPort{{Port: 8080}}}
+	for _, ip := range ips {
+		subset.Addresses = append(subset.Addresses, kapi.EndpointAddress{IP: ip})
+	}
+	endpoints.Subsets = []kapi.EndpointSubset{subset}
+	return endpoints
+}
+
+func TestInitialize(t *testing.T) {
+	plugin, fake, _, stop := newTestPlugin(t)
+	defer stop()
+
+	if !fake.exists(
+		"tm/ltm/policy/openshift_insecure_routes",
+		"tm/ltm/policy/openshift_secure_routes",
+		"tm/ltm/virtual/ose-vserver/policies/openshift_insecure_routes",
+		"tm/ltm/virtual/ose-vserver-https/policies/openshift_secure_routes",
+		"tm/ltm/data-group/internal/ssl_passthrough_servername_dg",
+		"tm/ltm/data-group/internal/ssl_reencrypt_servername_dg",
+		"tm/ltm/rule/openshift_passthrough_irule",
+		"tm/ltm/rule/openshift_reencrypt_irule",
+	) {
+		t.Fatalf("expected the policies, data groups and iRules to be created, got %v", fake.names("tm/ltm"))
+	}
+
+	// initializing the BIG-IP again does not change it
+	if err := plugin.F5Client.Initialize(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rules := fake.resources["tm/ltm/virtual/ose-vserver-https"]["rules"]
+	if e := []interface{}{"/Common/openshift_passthrough_irule", "/Common/openshift_reencrypt_irule"}; !reflect.DeepEqual(e, rules) {
+		t.Errorf("expected the iRules %v on the HTTPS virtual server, got %v", e, rules)
+	}
+	if e, a := []string{"openshift_insecure_routes"}, fake.names("tm/ltm/virtual/ose-vserver/policies"); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected the policies %v on the HTTP virtual server, got %v", e, a)
+	}
+}
+
+func TestNewF5PluginErrors(t *testing.T) {
+	server := httptest.NewTLSServer(newFakeF5())
+	defer server.Close()
+
+	cfg := F5PluginConfig{Host: server.Listener.Addr().String(), Username: "admin", Password: "wrong", HTTPVserver: "ose-vserver", HTTPSVserver: "ose-vserver-https", Insecure: true}
+	if _, err := NewF5Plugin(cfg, nil); err == nil || !strings.Contains(err.Error(), "Authorization failed") {
+		t.Errorf("expected an authorization error, got %v", err)
+	}
+
+	cfg.Password, cfg.Insecure = "secret", false
+	if _, err := NewF5Plugin(cfg, nil); err == nil {
+		t.Errorf("expected an error for an unverified certificate")
+	}
+
+	cfg.Insecure, cfg.HTTPSVserver = true, "missing"
+	if _, err := NewF5Plugin(cfg, nil); err == nil {
+		t.Errorf("expected an error for a missing virtual server")
+	}
+}
+
+func TestHandleRouteAndEndpoints(t *testing.T) {
+	plugin, fake, commits, stop := newTestPlugin(t)
+	defer stop()
+
+	// the pools of services are only created for routes
+	if err := plugin.HandleEndpoints(watch.Added, newEndpoints("10.1.0.1", "10.1.0.2")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fake.exists("tm/ltm/pool/openshift_ns_svc") || *commits != 0 {
+		t.Fatalf("expected no pool for a service without routes")
+	}
+
+	route := newRoute("app", "www.example.com", "/foo", nil)
+	if err := plugin.HandleRoute(watch.Added, route); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, a := []string{"10.1.0.1:8080", "10.1.0.2:8080"}, fake.names("tm/ltm/pool/openshift_ns_svc/members"); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected pool members %v, got %v", e, a)
+	}
+	data, _ := json.Marshal(fake.resources["tm/ltm/policy/openshift_insecure_routes/rules/openshift_route_ns_app"])
+	rule := f5PolicyRule{}
+	json.Unmarshal(data, &rule)
+	if len(rule.Conditions) != 2 || rule.Conditions[0].Values[0] != "www.example.com" || !rule.Conditions[0].Equals || rule.Conditions[1].Values[0] != "/foo" || !rule.Conditions[1].StartsWith {
+		t.Errorf("unexpected conditions %#v", rule.Conditions)
+	}
+	if len(rule.Actions) != 1 || rule.Actions[0].Pool != "/Common/openshift_ns_svc" {
+		t.Errorf("unexpected actions %#v", rule.Actions)
+	}
+
+	if err := plugin.HandleEndpoints(watch.Modified, newEndpoints("10.1.0.2", "10.1.0.3")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, a := []string{"10.1.0.2:8080", "10.1.0.3:8080"}, fake.names("tm/ltm/pool/openshift_ns_svc/members"); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected pool members %v, got %v", e, a)
+	}
+
+	// a wildcard route matches the hosts of its subdomain
+	wildcard := newRoute("app", "www.apps.example.com", "", nil)
+	wildcard.WildcardPolicy = routeapi.WildcardPolicySubdomain
+	if err := plugin.HandleRoute(watch.Modified, wildcard); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ = json.Marshal(fake.resources["tm/ltm/policy/openshift_insecure_routes/rules/openshift_route_ns_app"])
+	rule = f5PolicyRule{}
+	json.Unmarshal(data, &rule)
+	if len(rule.Conditions) != 1 || rule.Conditions[0].Values[0] != ".apps.example.com" || !rule.Conditions[0].EndsWith {
+		t.Errorf("unexpected conditions %#v", rule.Conditions)
+	}
+
+	if err := plugin.HandleRoute(watch.Deleted, wildcard); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fake.names("tm/ltm/policy/openshift_insecure_routes/rules")) != 0 || fake.exists("tm/ltm/pool/openshift_ns_svc") {
+		t.Errorf("expected the rule and the pool of the route to be deleted")
+	}
+	if *commits != 4 {
+		t.Errorf("expected 4 commits, got %d", *commits)
+	}
+
+	// the host of a wildcard route needs a subdomain
+	wildcard = newRoute("app", "localhost", "", nil)
+	wildcard.WildcardPolicy = routeapi.WildcardPolicySubdomain
+	if err := plugin.HandleRoute(watch.Added, wildcard); err == nil {
+		t.Errorf("expected an error for a wildcard route without a subdomain")
+	}
+}
+
+func TestRestartOnConfiguredBIGIP(t *testing.T) {
+	fake := newFakeF5()
+	server := httptest.NewTLSServer(fake)
+	defer server.Close()
+
+	routes := []*routeapi.Route{
+		newRoute("app", "www.example.com", "", nil),
+		newRoute("edge", "edge.example.com", "", &routeapi.TLSConfig{Termination: routeapi.TLSTerminationEdge, Certificate: "cert", Key: "key"}),
+		newRoute("reencrypt", "reencrypt.example.com", "", &routeapi.TLSConfig{Termination: routeapi.TLSTerminationReencrypt, Certificate: "cert", Key: "key", DestinationCACertificate: "destca"}),
+	}
+	for i := 0; i < 2; i++ {
+		// the second plugin finds the routes configured by the first one
+		plugin, _, err := startTestPlugin(server)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, route := range routes {
+			if err := plugin.HandleRoute(watch.Added, route); err != nil {
+				t.Fatalf("%d: %s: unexpected error: %v", i, route.Name, err)
+			}
+		}
+		if !fake.exists(
+			"tm/ltm/policy/openshift_insecure_routes/rules/openshift_route_ns_app",
+			"tm/ltm/policy/openshift_secure_routes/rules/openshift_route_ns_edge",
+			"tm/ltm/policy/openshift_secure_routes/rules/openshift_route_ns_reencrypt",
+			"tm/ltm/profile/client-ssl/openshift_route_ns_edge-https-profile",
+			"tm/ltm/profile/server-ssl/openshift_route_ns_reencrypt-server-ssl-profile",
+		) {
+			t.Errorf("%d: expected the rules and profiles of the routes to be kept", i)
+		}
+	}
+}
+
+func TestHandleSecureRoutes(t *testing.T) {
+	plugin, fake, _, stop := newTestPlugin(t)
+	defer stop()
+
+	edge := newRoute("edge", "edge.example.com", "", &routeapi.TLSConfig{Termination: routeapi.TLSTerminationEdge, Certificate: "cert", Key: "key", CACertificate: "ca"})
+	reencrypt := newRoute("reencrypt", "reencrypt.example.com", "", &routeapi.TLSConfig{Termination: routeapi.TLSTerminationReencrypt, Certificate: "cert", Key: "key", DestinationCACertificate: "destca"})
+	passthrough := newRoute("passthrough", "passthrough.example.com", "", &routeapi.TLSConfig{Termination: routeapi.TLSTerminationPassthrough})
+	for _, route := range []*routeapi.Route{edge, reencrypt, passthrough} {
+		if err := plugin.HandleRoute(watch.Added, route); err != nil {
+			t.Fatalf("%s: unexpected error: %v", route.Name, err)
+		}
+	}
+
+	if !fake.exists(
+		"tm/sys/file/ssl-cert/openshift_route_ns_edge-https-profile.crt",
+		"tm/sys/file/ssl-key/openshift_route_ns_edge-https-profile.key",
+		"tm/sys/file/ssl-cert/openshift_route_ns_edge-https-profile-chain.crt",
+		"tm/ltm/profile/client-ssl/openshift_route_ns_edge-https-profile",
+		"tm/ltm/profile/client-ssl/openshift_route_ns_reencrypt-https-profile",
+		"tm/ltm/profile/server-ssl/openshift_route_ns_reencrypt-server-ssl-profile",
+		"tm/sys/file/ssl-cert/openshift_route_ns_reencrypt-server-ssl-profile.crt",
+	) {
+		t.Fatalf("expected the certificates and profiles of the routes to be created")
+	}
+	if content := fake.resources["tm/sys/file/ssl-key/openshift_route_ns_edge-https-profile.key"]["content"]; content != "key" {
+		t.Errorf("expected the key of the route to be installed, got %v", content)
+	}
+	if e, a := []string{"openshift_route_ns_edge", "openshift_route_ns_reencrypt"}, fake.names("tm/ltm/policy/openshift_secure_routes/rules"); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected secure rules %v, got %v", e, a)
+	}
+	profiles := fake.names("tm/ltm/virtual/ose-vserver-https/profiles")
+	if e := []string{"openshift_route_ns_edge-https-profile", "openshift_route_ns_reencrypt-https-profile", "openshift_route_ns_reencrypt-server-ssl-profile"}; !reflect.DeepEqual(e, profiles) {
+		t.Errorf("expected profiles %v on the HTTPS virtual server, got %v", e, profiles)
+	}
+	if e, a := map[string]string{"reencrypt.example.com": "/Common/openshift_route_ns_reencrypt-server-ssl-profile"}, fake.records(reencryptDatagroupName); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected reencrypt records %v, got %v", e, a)
+	}
+	if e, a := map[string]string{"passthrough.example.com": "openshift_ns_svc"}, fake.records(passthroughDatagroupName); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected passthrough records %v, got %v", e, a)
+	}
+
+	// moving a passthrough route to another host removes its old host
+	moved := newRoute("passthrough", "moved.example.com", "", &routeapi.TLSConfig{Termination: routeapi.TLSTerminationPassthrough})
+	if err := plugin.HandleRoute(watch.Modified, moved); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, a := map[string]string{"moved.example.com": "openshift_ns_svc"}, fake.records(passthroughDatagroupName); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected passthrough records %v, got %v", e, a)
+	}
+
+	for _, route := range []*routeapi.Route{edge, reencrypt, moved} {
+		if err := plugin.HandleRoute(watch.Deleted, route); err != nil {
+			t.Fatalf("%s: unexpected error: %v", route.Name, err)
+		}
+	}
+	for _, collection := range []string{
+		"tm/sys/file/ssl-cert",
+		"tm/sys/file/ssl-key",
+		"tm/ltm/profile/client-ssl",
+		"tm/ltm/profile/server-ssl",
+		"tm/ltm/policy/openshift_secure_routes/rules",
+		"tm/ltm/virtual/ose-vserver-https/profiles",
+		"tm/ltm/pool",
+	} {
+		if names := fake.names(collection); len(names) != 0 {
+			t.Errorf("expected %s to be empty, got %v", collection, names)
+		}
+	}
+	if len(fake.records(passthroughDatagroupName)) != 0 || len(fake.records(reencryptDatagroupName)) != 0 {
+		t.Errorf("expected the data groups to be empty")
+	}
+}
@@ -2,18 +2,31 @@ package templaterouter
 
 import (
 	"bytes"
-	"github.com/golang/glog"
 	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/glog"
 
 	routeapi "github.com/openshift/origin/pkg/route/api"
 )
 
 // certManager is responsible for writing out certificates to the disk for the template router plugin.
-type certManager struct{}
+type certManager struct {
+	// certDir holds the <host>.pem files of edge and reencrypt routes
+	certDir string
+	// caCertDir holds the <host>_pod.pem files of reencrypt routes
+	caCertDir string
+	// previous holds, by path, the contents certificate files had before they were written
+	// since the last commit, or nil for the files which did not exist
+	previous map[string][]byte
+}
 
 // writeCertificatesForConfig write certificates for edge and reencrypt termination by appending the key, cert, and ca cert
-// into a single <host>.pem file.  Also write <host>_pod.pem file if it is reencrypt termination
-func (cm *certManager) writeCertificatesForConfig(config *ServiceAliasConfig) error {
+// into a single <host>.pem file.  Also write <host>_pod.pem file if it is reencrypt termination.  It returns true if any
+// certificate file changed.
+func (cm *certManager) writeCertificatesForConfig(config *ServiceAliasConfig) (bool, error) {
+	changed := false
 	if len(config.Certificates) > 0 {
 		if config.TLSTermination == routeapi.TLSTerminationEdge || config.TLSTermination == routeapi.TLSTerminationReencrypt {
 			certObj, ok := config.Certificates[config.Host]
@@ -33,7 +46,11 @@ func (cm *certManager) writeCertificatesForConfig(config *ServiceAliasConfig) er
 					buffer.Write([]byte(caCertObj.Contents))
 				}
 
-				cm.writeCertificate(certDir, config.Host, buffer.Bytes())
+				written, err := cm.writeCertificate(cm.certDir, config.Host, buffer.Bytes())
+				if err != nil {
+					return changed, err
+				}
+				changed = changed || written
 			}
 		}
 
@@ -42,26 +59,66 @@ func (cm *certManager) writeCertificatesForConfig(config *ServiceAliasConfig) er
 			destCert, ok := config.Certificates[destCertKey]
 
 			if ok {
-				cm.writeCertificate(caCertDir, destCertKey, []byte(destCert.Contents))
+				written, err := cm.writeCertificate(cm.caCertDir, destCertKey, []byte(destCert.Contents))
+				if err != nil {
+					return changed, err
+				}
+				changed = changed || written
 			}
 		}
 	}
 
-	return nil
+	return changed, nil
 }
 
 // writeCertificate creates and writes the file identified by <id> in <directory>.  The file extension
-// .pem will be added to id.
-func (cm *certManager) writeCertificate(directory string, id string, cert []byte) error {
-	fileName := directory + id + ".pem"
-	err := ioutil.WriteFile(fileName, cert, 0644)
+// .pem will be added to id.  The file is left untouched if it already holds cert, and false is returned.
+func (cm *certManager) writeCertificate(directory string, id string, cert []byte) (bool, error) {
+	fileName := filepath.Join(directory, id+".pem")
+	existing, err := ioutil.ReadFile(fileName)
+	if err == nil && bytes.Equal(existing, cert) {
+		return false, nil
+	}
+	if err != nil {
+		existing = nil
+	}
+
+	err = writeFileAtomically(fileName, cert, 0644)
 
 	if err != nil {
 		glog.Errorf("Error writing certificate file %v: %v", fileName, err)
-		return err
+		return false, err
 	}
 
-	return nil
+	if cm.previous == nil {
+		cm.previous = map[string][]byte{}
+	}
+	if _, ok := cm.previous[fileName]; !ok {
+		cm.previous[fileName] = existing
+	}
+	return true, nil
+}
+
+// commit forgets the previous contents of the certificate files, once the backend accepted them.
+func (cm *certManager) commit() {
+	cm.previous = nil
+}
+
+// rollback restores the certificate files written since the last commit to their previous
+// contents, and removes the ones which did not exist.
+func (cm *certManager) rollback() {
+	for fileName, existing := range cm.previous {
+		var err error
+		if existing == nil {
+			err = os.Remove(fileName)
+		} else {
+			err = writeFileAtomically(fileName, existing, 0644)
+		}
+		if err != nil && !os.IsNotExist(err) {
+			glog.Errorf("Error restoring certificate file %v: %v", fileName, err)
+		}
+	}
+	cm.previous = nil
 }
 
 // deleteCertificatesForConfig will delete all certificates for the ServiceAliasConfig
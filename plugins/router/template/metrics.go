@@ -0,0 +1,29 @@
+package templaterouter
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const templateRouterSubsystem = "template_router"
+
+var (
+	reloadCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: templateRouterSubsystem,
+			Name:      "reload_total",
+			Help:      "Number of reloads of the router backend, by result: success or failure.",
+		},
+		[]string{"result"},
+	)
+	reloadDuration = prometheus.NewSummary(
+		prometheus.SummaryOpts{
+			Subsystem: templateRouterSubsystem,
+			Name:      "reload_duration_seconds",
+			Help:      "Time in seconds the reload script of the router took to run.",
+		},
+	)
+)
+
+var registerMetrics sync.Once
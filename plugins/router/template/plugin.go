@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"strconv"
 	"text/template"
+	"time"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 	"github.com/golang/glog"
+	"github.com/prometheus/client_golang/prometheus"
 
 	routeapi "github.com/openshift/origin/pkg/route/api"
 )
@@ -42,11 +44,15 @@ type router interface {
 
 	// Commit refreshes the backend and persists the router state.
 	Commit() error
+	// ReloadError returns the error of the last reload of the backend, or nil if it succeeded.
+	ReloadError() error
 }
 
-// NewTemplatePlugin creates a new TemplatePlugin.
-func NewTemplatePlugin(templatePath, reloadScriptPath string) (*TemplatePlugin, error) {
-	masterTemplate := template.Must(template.New("config").ParseFiles(templatePath))
+// NewTemplatePlugin creates a new TemplatePlugin. The router state and certificates are
+// written to workingDir, the router is reloaded at most once per reloadInterval, and committed, if not nil, is called each time the router
+// configuration was successfully applied.
+func NewTemplatePlugin(templatePath, reloadScriptPath, workingDir string, reloadInterval time.Duration, committed func()) (*TemplatePlugin, error) {
+	masterTemplate := template.Must(template.New("config").Funcs(helperFunctions).Funcs(workingDirFunctions(workingDir)).ParseFiles(templatePath))
 	templates := map[string]*template.Template{}
 
 	for _, template := range masterTemplate.Templates() {
@@ -57,10 +63,23 @@ func NewTemplatePlugin(templatePath, reloadScriptPath string) (*TemplatePlugin,
 		templates[template.Name()] = template
 	}
 
-	router, err := newTemplateRouter(templates, reloadScriptPath)
+	registerMetrics.Do(func() {
+		prometheus.MustRegister(reloadCount)
+		prometheus.MustRegister(reloadDuration)
+	})
+
+	router, err := newTemplateRouter(templates, reloadScriptPath, workingDir, reloadInterval, committed)
 	return &TemplatePlugin{router}, err
 }
 
+// Healthz returns an error if the router failed to apply its last configuration.
+func (p *TemplatePlugin) Healthz() error {
+	if err := p.Router.ReloadError(); err != nil {
+		return fmt.Errorf("the last reload of the router failed: %v", err)
+	}
+	return nil
+}
+
 // HandleEndpoints processes watch events on the Endpoints resource.
 func (p *TemplatePlugin) HandleEndpoints(eventType watch.EventType, endpoints *kapi.Endpoints) error {
 	key := endpointsKey(*endpoints)
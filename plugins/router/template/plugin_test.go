@@ -103,6 +103,11 @@ func (r *TestRouter) Commit() error {
 	return r.ErrorOnCommit
 }
 
+// ReloadError returns the error set to be returned by Commit
+func (r *TestRouter) ReloadError() error {
+	return r.ErrorOnCommit
+}
+
 // TestHandleEndpoints test endpoint watch events
 func TestHandleEndpoints(t *testing.T) {
 	testCases := []struct {
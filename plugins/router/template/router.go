@@ -1,12 +1,16 @@
 package templaterouter
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"sync"
 	"text/template"
+	"time"
 
 	"github.com/golang/glog"
 
@@ -20,9 +24,10 @@ const (
 )
 
 const (
-	routeFile = "/var/lib/containers/router/routes.json"
-	certDir   = "/var/lib/containers/router/certs/"
-	caCertDir = "/var/lib/containers/router/cacerts/"
+	// routeFile, certDir and caCertDir are relative to the working directory of the router
+	routeFile = "routes.json"
+	certDir   = "certs"
+	caCertDir = "cacerts"
 
 	caCertPostfix   = "_ca"
 	destCertPostfix = "_pod"
@@ -34,12 +39,49 @@ const (
 type templateRouter struct {
 	templates        map[string]*template.Template
 	reloadScriptPath string
-	state            map[string]ServiceUnit
-	certManager      certManager
+	// dir is the working directory the router state and certificates are written to
+	dir         string
+	certManager certManager
+	// minReloadInterval is the minimum time between two reloads of the backend;
+	// changes committed in between are coalesced into a single reload
+	minReloadInterval time.Duration
+	// committed, if set, is called after changes were successfully applied to the backend
+	committed func()
+
+	// lock protects the state of the router and the fields below it
+	lock  sync.Mutex
+	state map[string]ServiceUnit
+	// lastReload is the time the backend was last reloaded
+	lastReload time.Time
+	// reloadError is the error of the last reload of the backend, if it failed
+	reloadError error
+	// commitPending is true while a delayed commit is scheduled
+	commitPending bool
+	// renderedConfig holds the config files, by path, the backend was last reloaded with
+	renderedConfig map[string][]byte
+	// goodConfig holds the config files, by path, of the last successful reload of the
+	// backend. They are written back when a reload fails.
+	goodConfig map[string][]byte
+	// failedState holds the serialized state the backend failed to reload with, so the
+	// same config is not written and reloaded again until the state changes
+	failedState []byte
 }
 
-func newTemplateRouter(templates map[string]*template.Template, reloadScriptPath string) (*templateRouter, error) {
-	router := &templateRouter{templates, reloadScriptPath, map[string]ServiceUnit{}, certManager{}}
+func newTemplateRouter(templates map[string]*template.Template, reloadScriptPath, dir string, minReloadInterval time.Duration, committed func()) (*templateRouter, error) {
+	router := &templateRouter{
+		templates:         templates,
+		reloadScriptPath:  reloadScriptPath,
+		dir:               dir,
+		certManager:       certManager{certDir: filepath.Join(dir, certDir), caCertDir: filepath.Join(dir, caCertDir)},
+		minReloadInterval: minReloadInterval,
+		committed:         committed,
+		state:             map[string]ServiceUnit{},
+	}
+	for _, path := range []string{router.certManager.certDir, router.certManager.caCertDir} {
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return nil, fmt.Errorf("unable to create the router working directory %s: %v", path, err)
+		}
+	}
 	if err := router.readState(); err != nil {
 		return nil, err
 	}
@@ -50,7 +92,7 @@ func newTemplateRouter(templates map[string]*template.Template, reloadScriptPath
 }
 
 func (r *templateRouter) readState() error {
-	dat, err := ioutil.ReadFile(routeFile)
+	dat, err := ioutil.ReadFile(filepath.Join(r.dir, routeFile))
 	// XXX: rework
 	if err != nil {
 		r.state = make(map[string]ServiceUnit)
@@ -60,78 +102,211 @@ func (r *templateRouter) readState() error {
 	return json.Unmarshal(dat, &r.state)
 }
 
-// Commit refreshes the backend and persists the router state.
+// Commit refreshes the backend and persists the router state. If the backend was
+// reloaded less than the minimum reload interval ago, the refresh is delayed until
+// the interval has passed, and the changes committed meanwhile are applied together.
 func (r *templateRouter) Commit() error {
-	glog.V(4).Info("Commiting router changes")
+	r.lock.Lock()
+	if r.commitPending {
+		r.lock.Unlock()
+		return nil
+	}
+	if wait := r.minReloadInterval - time.Since(r.lastReload); wait > 0 {
+		glog.V(4).Infof("Delaying router changes for %v", wait)
+		r.commitPending = true
+		time.AfterFunc(wait, r.delayedCommit)
+		r.lock.Unlock()
+		return nil
+	}
+	err := r.commit()
+	r.lock.Unlock()
 
-	if err := r.writeState(); err != nil {
+	if err != nil {
 		return err
 	}
+	r.notifyCommitted()
+	return nil
+}
+
+// delayedCommit applies the changes which were committed while the backend could not
+// be reloaded.
+func (r *templateRouter) delayedCommit() {
+	r.lock.Lock()
+	r.commitPending = false
+	err := r.commit()
+	r.lock.Unlock()
 
-	if err := r.writeConfig(); err != nil {
+	if err != nil {
+		glog.Errorf("Error commiting router changes: %v", err)
+		return
+	}
+	r.notifyCommitted()
+}
+
+// notifyCommitted calls the committed callback, if any. It is called without holding
+// the lock, so the callback may take its time.
+func (r *templateRouter) notifyCommitted() {
+	if r.committed != nil {
+		r.committed()
+	}
+}
+
+// commit persists the router state, writes the config and reloads the backend if the
+// config changed. The caller must hold the lock.
+func (r *templateRouter) commit() error {
+	glog.V(4).Info("Commiting router changes")
+
+	state, err := r.writeState()
+	if err != nil {
 		return err
 	}
+	if r.reloadError != nil && bytes.Equal(state, r.failedState) {
+		glog.V(4).Info("Router state is unchanged since the last failed reload, skipping reload")
+		return r.reloadError
+	}
 
-	if err := r.reloadRouter(); err != nil {
+	rendered, changed, err := r.writeConfig()
+	if err != nil {
 		return err
 	}
+	if !changed {
+		glog.V(4).Info("Router config is unchanged, skipping reload")
+		return nil
+	}
+
+	r.lastReload = time.Now()
+	r.reloadError = r.reloadRouter()
+	if r.reloadError != nil {
+		// reload on the next change of the state even if the config does not change, and leave
+		// the last config the backend accepted on disk so a restart of the backend picks it up
+		r.renderedConfig = nil
+		r.failedState = state
+		r.rollbackConfig()
+		return r.reloadError
+	}
+	r.renderedConfig = rendered
+	r.goodConfig = rendered
+	r.failedState = nil
+	r.certManager.commit()
 
 	return nil
 }
 
-// writeState writes the state of this router to disk.
-func (r *templateRouter) writeState() error {
+// writeState writes the state of this router to disk, and returns the written state.
+func (r *templateRouter) writeState() ([]byte, error) {
 	dat, err := json.MarshalIndent(r.state, "", "  ")
 	if err != nil {
 		glog.Errorf("Failed to marshal route table: %v", err)
-		return err
+		return nil, err
 	}
-	err = ioutil.WriteFile(routeFile, dat, 0644)
+	err = writeFileAtomically(filepath.Join(r.dir, routeFile), dat, 0644)
 	if err != nil {
 		glog.Errorf("Failed to write route table: %v", err)
-		return err
+		return nil, err
 	}
 
-	return nil
+	return dat, nil
 }
 
-// writeConfig writes the config to disk
-func (r *templateRouter) writeConfig() error {
-	//write out any certificate files that don't exist
-	//TODO: better way so this doesn't need to create lots of files every time state is written, probably too expensive
+// writeConfig writes the certificates and the config files which changed since the last
+// reload to disk. It returns the rendered config files, and whether the backend must be
+// reloaded for any change to take effect.
+func (r *templateRouter) writeConfig() (map[string][]byte, bool, error) {
+	changed := false
 	for _, serviceUnit := range r.state {
 		for _, cfg := range serviceUnit.ServiceAliasConfigs {
-			r.certManager.writeCertificatesForConfig(&cfg)
+			if written, _ := r.certManager.writeCertificatesForConfig(&cfg); written {
+				changed = true
+			}
 		}
 	}
 
+	rendered := map[string][]byte{}
 	for path, template := range r.templates {
-		file, err := os.Create(path)
-		if err != nil {
-			glog.Errorf("Error creating config file %v: %v", path, err)
-			return err
+		buffer := &bytes.Buffer{}
+		if err := template.Execute(buffer, r.state); err != nil {
+			glog.Errorf("Error executing template for file %v: %v", path, err)
+			return nil, false, err
 		}
+		rendered[path] = buffer.Bytes()
 
-		err = template.Execute(file, r.state)
-		if err != nil {
-			glog.Errorf("Error executing template for file %v: %v", path, err)
-			return err
+		if previous, ok := r.renderedConfig[path]; ok && bytes.Equal(previous, rendered[path]) {
+			continue
 		}
+		changed = true
+		if err := writeFileAtomically(path, rendered[path], 0644); err != nil {
+			glog.Errorf("Error writing config file %v: %v", path, err)
+			return nil, false, err
+		}
+	}
+	if r.renderedConfig == nil {
+		changed = true
+	}
+
+	return rendered, changed, nil
+}
+
+// rollbackConfig writes back the config files of the last successful reload of the backend,
+// and restores the certificate files written since. Config files are left as they are if the
+// backend was never reloaded successfully.
+func (r *templateRouter) rollbackConfig() {
+	r.certManager.rollback()
 
-		file.Close()
+	for path, data := range r.goodConfig {
+		glog.V(4).Infof("Restoring the last known good config file %v", path)
+		if err := writeFileAtomically(path, data, 0644); err != nil {
+			glog.Errorf("Error restoring config file %v: %v", path, err)
+		}
 	}
+}
 
-	return nil
+// writeFileAtomically writes data to a temporary file in the directory of path and renames
+// it to path, so readers of path never see a partially written file.
+func writeFileAtomically(path string, data []byte, perm os.FileMode) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp, perm)
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+	}
+	return err
 }
 
 // reloadRouter executes the router's reload script.
 func (r *templateRouter) reloadRouter() error {
+	start := time.Now()
 	cmd := exec.Command(r.reloadScriptPath)
 	out, err := cmd.CombinedOutput()
+	reloadDuration.Observe(time.Since(start).Seconds())
 	if err != nil {
+		reloadCount.WithLabelValues("failure").Inc()
 		glog.Errorf("Error reloading router: %v\n Reload output: %v", err, string(out))
+		return err
 	}
-	return err
+	reloadCount.WithLabelValues("success").Inc()
+	return nil
+}
+
+// ReloadError returns the error of the last reload of the backend, or nil if it succeeded.
+func (r *templateRouter) ReloadError() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.reloadError
 }
 
 // CreateServiceUnit creates a new service named with the given id.
@@ -142,23 +317,31 @@ func (r *templateRouter) CreateServiceUnit(id string) {
 		EndpointTable:       make(map[string]Endpoint),
 	}
 
+	r.lock.Lock()
+	defer r.lock.Unlock()
 	r.state[id] = service
 }
 
 // FindServiceUnit finds the service with the given id.
 func (r *templateRouter) FindServiceUnit(id string) (v ServiceUnit, ok bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
 	v, ok = r.state[id]
 	return
 }
 
 // DeleteServiceUnit deletes the service with the given id.
 func (r *templateRouter) DeleteServiceUnit(id string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
 	delete(r.state, id)
 }
 
 // DeleteEndpoints deletes the endpoints for the service with the given id.
 func (r *templateRouter) DeleteEndpoints(id string) {
-	service, ok := r.FindServiceUnit(id)
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	service, ok := r.state[id]
 	if !ok {
 		return
 	}
@@ -176,17 +359,25 @@ func (r *templateRouter) routeKey(route *routeapi.Route) string {
 
 // AddRoute adds a route for the given id
 func (r *templateRouter) AddRoute(id string, route *routeapi.Route) {
-	frontend, _ := r.FindServiceUnit(id)
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	frontend := r.state[id]
 
 	backendKey := r.routeKey(route)
 
 	config := ServiceAliasConfig{
-		Host: route.Host,
-		Path: route.Path,
+		Host:           route.Host,
+		Path:           route.Path,
+		IsWildcard:     route.WildcardPolicy == routeapi.WildcardPolicySubdomain,
+		Timeout:        route.Annotations[routeapi.RouteTimeoutAnnotation],
+		Balance:        route.Annotations[routeapi.RouteBalanceAnnotation],
+		StickySessions: route.Annotations[routeapi.RouteStickySessionsAnnotation] == "true",
+		HSTSHeader:     route.Annotations[routeapi.RouteHSTSAnnotation],
 	}
 
 	if route.TLS != nil && len(route.TLS.Termination) > 0 {
 		config.TLSTermination = route.TLS.Termination
+		config.InsecureEdgeTerminationPolicy = route.TLS.InsecureEdgeTerminationPolicy
 
 		if route.TLS.Termination != routeapi.TLSTerminationPassthrough {
 			if config.Certificates == nil {
@@ -228,6 +419,8 @@ func (r *templateRouter) AddRoute(id string, route *routeapi.Route) {
 
 // RemoveRoute removes the given route for the given id.
 func (r *templateRouter) RemoveRoute(id string, route *routeapi.Route) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
 	_, ok := r.state[id]
 
 	if !ok {
@@ -239,7 +432,9 @@ func (r *templateRouter) RemoveRoute(id string, route *routeapi.Route) {
 
 // AddEndpoints adds new Endpoints for the given id.
 func (r *templateRouter) AddEndpoints(id string, endpoints []Endpoint) {
-	frontend, _ := r.FindServiceUnit(id)
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	frontend := r.state[id]
 
 	//only add if it doesn't already exist
 	for _, ep := range endpoints {
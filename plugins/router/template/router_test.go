@@ -1,9 +1,15 @@
 package templaterouter
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+	"time"
+
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	routeapi "github.com/openshift/origin/pkg/route/api"
-	"testing"
 )
 
 // emptyRouter creates a new, empty template router
@@ -143,15 +149,22 @@ func TestAddRoute(t *testing.T) {
 		ObjectMeta: kapi.ObjectMeta{
 			Namespace: "foo",
 			Name:      "bar",
+			Annotations: map[string]string{
+				routeapi.RouteTimeoutAnnotation:        "90s",
+				routeapi.RouteBalanceAnnotation:        "source",
+				routeapi.RouteStickySessionsAnnotation: "true",
+				routeapi.RouteHSTSAnnotation:           "max-age=100",
+			},
 		},
 		Host: "host",
 		Path: "path",
 		TLS: &routeapi.TLSConfig{
-			Termination:              routeapi.TLSTerminationEdge,
-			Certificate:              "abc",
-			Key:                      "def",
-			CACertificate:            "ghi",
-			DestinationCACertificate: "jkl",
+			Termination:                   routeapi.TLSTerminationEdge,
+			Certificate:                   "abc",
+			Key:                           "def",
+			CACertificate:                 "ghi",
+			DestinationCACertificate:      "jkl",
+			InsecureEdgeTerminationPolicy: routeapi.InsecureEdgeTerminationPolicyRedirect,
 		},
 	}
 	suKey := "test"
@@ -173,6 +186,9 @@ func TestAddRoute(t *testing.T) {
 			if saCfg.Host != route.Host || saCfg.Path != route.Path || !compareTLS(route, saCfg, t) {
 				t.Errorf("Route %v did not match serivce alias config %v", route, saCfg)
 			}
+			if saCfg.Timeout != "90s" || saCfg.Balance != "source" || !saCfg.StickySessions || saCfg.HSTSHeader != "max-age=100" || saCfg.InsecureEdgeTerminationPolicy != routeapi.InsecureEdgeTerminationPolicyRedirect {
+				t.Errorf("Route %v settings did not match serivce alias config %v", route, saCfg)
+			}
 		}
 	}
 }
@@ -256,3 +272,272 @@ func TestRemoveRoute(t *testing.T) {
 		t.Errorf("Route %v was expected to exist but was not found", route2)
 	}
 }
+
+// TestCommitCoalescesChanges tests that commits within the minimum reload interval are delayed
+func TestCommitCoalescesChanges(t *testing.T) {
+	router := emptyRouter()
+	router.minReloadInterval = time.Hour
+	router.lastReload = time.Now()
+	committed := false
+	router.committed = func() { committed = true }
+
+	if err := router.Commit(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !router.commitPending {
+		t.Errorf("Expected a commit within the reload interval to be delayed")
+	}
+	if err := router.Commit(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if committed {
+		t.Errorf("Expected delayed changes not to be reported as committed")
+	}
+}
+
+// TestWriteConfigOnlyWhenChanged tests that config files are written and the router reloaded only when
+// the rendered config changes
+func TestWriteConfigOnlyWhenChanged(t *testing.T) {
+	dir, err := ioutil.TempDir("", "router")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config")
+	router := emptyRouter()
+	router.templates = map[string]*template.Template{
+		path: template.Must(template.New("config").Parse(`{{range $id, $unit := .}}{{$id}} {{end}}`)),
+	}
+	router.CreateServiceUnit("test")
+
+	rendered, changed, err := router.writeConfig()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !changed {
+		t.Errorf("Expected the first config to require a reload")
+	}
+	if data, _ := ioutil.ReadFile(path); string(data) != "test " {
+		t.Errorf("Unexpected config %q", string(data))
+	}
+	router.renderedConfig = rendered
+
+	os.Remove(path)
+	if _, changed, err := router.writeConfig(); err != nil || changed {
+		t.Errorf("Expected an unchanged config not to require a reload, got %t, %v", changed, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected an unchanged config not to be written")
+	}
+
+	router.CreateServiceUnit("other")
+	if _, changed, err := router.writeConfig(); err != nil || !changed {
+		t.Errorf("Expected a changed config to require a reload, got %t, %v", changed, err)
+	}
+	if data, _ := ioutil.ReadFile(path); string(data) != "other test " {
+		t.Errorf("Unexpected config %q", string(data))
+	}
+}
+
+// TestWriteCertificateOnlyWhenChanged tests that certificate files are only written when their contents change
+func TestWriteCertificateOnlyWhenChanged(t *testing.T) {
+	dir, err := ioutil.TempDir("", "router")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	cm := certManager{}
+	for i, test := range []struct {
+		contents string
+		changed  bool
+	}{
+		{"cert", true},
+		{"cert", false},
+		{"new cert", true},
+	} {
+		changed, err := cm.writeCertificate(dir+"/", "host", []byte(test.contents))
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %v", i, err)
+		}
+		if changed != test.changed {
+			t.Errorf("%d: expected changed to be %t", i, test.changed)
+		}
+	}
+}
+
+// TestCommitRollsBackFailedConfig tests that the config of the last successful reload is written back
+// when the reload script fails
+func TestCommitRollsBackFailedConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "router")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config")
+	router := emptyRouter()
+	router.dir = dir
+	router.templates = map[string]*template.Template{
+		path: template.Must(template.New("config").Parse(`{{range $id, $unit := .}}{{$id}} {{end}}`)),
+	}
+	router.reloadScriptPath = "/bin/true"
+	router.CreateServiceUnit("test")
+	if err := router.Commit(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	router.reloadScriptPath = "/bin/false"
+	router.CreateServiceUnit("other")
+	if err := router.Commit(); err == nil {
+		t.Fatalf("Expected the failed reload to be reported")
+	}
+	if data, _ := ioutil.ReadFile(path); string(data) != "test " {
+		t.Errorf("Expected the last good config to be restored, got %q", string(data))
+	}
+	if router.ReloadError() == nil {
+		t.Errorf("Expected the reload error to be recorded")
+	}
+
+	router.reloadScriptPath = "/bin/true"
+	router.CreateServiceUnit("third")
+	if err := router.Commit(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if data, _ := ioutil.ReadFile(path); string(data) != "other test third " {
+		t.Errorf("Expected the new config to be written once the reload succeeds, got %q", string(data))
+	}
+	if _, err := os.Stat(filepath.Join(dir, routeFile)); err != nil {
+		t.Errorf("Expected the router state to be written to the working directory: %v", err)
+	}
+}
+
+// TestCommitSkipsReloadOfFailedState tests that the backend is not reloaded again with the
+// state it failed to reload with, until the state changes
+func TestCommitSkipsReloadOfFailedState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "router")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// the reload script records each reload and fails
+	reloads := filepath.Join(dir, "reloads")
+	script := filepath.Join(dir, "reload.sh")
+	if err := ioutil.WriteFile(script, []byte("#!/bin/sh\necho >> "+reloads+"\nexit 1\n"), 0755); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	countReloads := func() int {
+		data, _ := ioutil.ReadFile(reloads)
+		return len(data)
+	}
+
+	path := filepath.Join(dir, "config")
+	router := emptyRouter()
+	router.dir = dir
+	router.templates = map[string]*template.Template{
+		path: template.Must(template.New("config").Parse(`{{range $id, $unit := .}}{{$id}} {{end}}`)),
+	}
+	router.reloadScriptPath = script
+	router.CreateServiceUnit("test")
+	for i := 0; i < 3; i++ {
+		if err := router.Commit(); err == nil {
+			t.Fatalf("%d: Expected the failed reload to be reported", i)
+		}
+	}
+	if n := countReloads(); n != 1 {
+		t.Errorf("Expected the unchanged state to be reloaded once, got %d reloads", n)
+	}
+
+	router.CreateServiceUnit("other")
+	if err := router.Commit(); err == nil {
+		t.Fatalf("Expected the failed reload to be reported")
+	}
+	if n := countReloads(); n != 2 {
+		t.Errorf("Expected a changed state to be reloaded, got %d reloads", n)
+	}
+}
+
+// TestWriteFileAtomically tests that files are replaced without leaving temporary files behind
+func TestWriteFileAtomically(t *testing.T) {
+	dir, err := ioutil.TempDir("", "router")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "file")
+	for _, contents := range []string{"first", "second"} {
+		if err := writeFileAtomically(path, []byte(contents), 0640); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if data, _ := ioutil.ReadFile(path); string(data) != contents {
+			t.Errorf("Expected %q, got %q", contents, string(data))
+		}
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0640 {
+		t.Errorf("Unexpected file mode: %v, %v", info, err)
+	}
+	if files, _ := ioutil.ReadDir(dir); len(files) != 1 {
+		t.Errorf("Expected no temporary files to be left, got %d files", len(files))
+	}
+
+	if err := writeFileAtomically(filepath.Join(dir, "missing", "file"), []byte("data"), 0644); err == nil {
+		t.Errorf("Expected an error writing to a missing directory")
+	}
+}
+
+// TestCommitRollsBackFailedCertificates tests that the certificates written for a failed reload are
+// restored, so the certificate directory matches the last good config
+func TestCommitRollsBackFailedCertificates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "router")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	router := emptyRouter()
+	router.dir = dir
+	router.certManager = certManager{certDir: dir, caCertDir: dir}
+	router.templates = map[string]*template.Template{}
+	edgeConfig := func(host, cert string) ServiceAliasConfig {
+		return ServiceAliasConfig{
+			Host:           host,
+			TLSTermination: routeapi.TLSTerminationEdge,
+			Certificates:   map[string]Certificate{host: {ID: host, Contents: cert, PrivateKey: "key"}},
+		}
+	}
+
+	router.reloadScriptPath = "/bin/true"
+	router.state["ns/a"] = ServiceUnit{Name: "ns/a", ServiceAliasConfigs: map[string]ServiceAliasConfig{
+		"ns-a": edgeConfig("a.example.com", "good"),
+	}}
+	if err := router.Commit(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	router.reloadScriptPath = "/bin/false"
+	router.state["ns/a"] = ServiceUnit{Name: "ns/a", ServiceAliasConfigs: map[string]ServiceAliasConfig{
+		"ns-a": edgeConfig("a.example.com", "bad"),
+		"ns-b": edgeConfig("b.example.com", "bad"),
+	}}
+	if err := router.Commit(); err == nil {
+		t.Fatalf("Expected the failed reload to be reported")
+	}
+	if data, _ := ioutil.ReadFile(filepath.Join(dir, "a.example.com.pem")); string(data) != "key\ngood" {
+		t.Errorf("Expected the last good certificate to be restored, got %q", string(data))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "b.example.com.pem")); !os.IsNotExist(err) {
+		t.Errorf("Expected the certificate written for the failed reload to be removed, got %v", err)
+	}
+
+	router.reloadScriptPath = "/bin/true"
+	router.state["ns/c"] = ServiceUnit{Name: "ns/c"}
+	if err := router.Commit(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if data, _ := ioutil.ReadFile(filepath.Join(dir, "b.example.com.pem")); string(data) != "key\nbad" {
+		t.Errorf("Expected the certificate to be written once the reload succeeds, got %q", string(data))
+	}
+}
@@ -0,0 +1,65 @@
+package templaterouter
+
+import (
+	"path/filepath"
+	"sort"
+	"text/template"
+)
+
+// helperFunctions are the functions router templates may call in addition to the
+// template builtins.
+var helperFunctions = template.FuncMap{
+	"orderedServiceAliasConfigs": orderedServiceAliasConfigs,
+}
+
+// workingDirFunctions returns the functions router templates may call to find the
+// certificates the router writes to its working directory dir.
+func workingDirFunctions(dir string) template.FuncMap {
+	return template.FuncMap{
+		// certDir returns the directory of the <host>.pem files of edge and reencrypt routes
+		"certDir": func() string { return filepath.Join(dir, certDir) },
+		// caCertDir returns the directory of the <host>_pod.pem files of reencrypt routes
+		"caCertDir": func() string { return filepath.Join(dir, caCertDir) },
+	}
+}
+
+// ServiceAliasConfigEntry is a ServiceAliasConfig along with the key it is stored under,
+// which templates use to name its backend.
+type ServiceAliasConfigEntry struct {
+	Key    string
+	Config ServiceAliasConfig
+}
+
+// orderedServiceAliasConfigs returns the configs of all service units in the order a
+// router must try them, since HAProxy uses the first entry of a map which matches a
+// request. Exact hosts come before wildcard hosts, and longer paths before shorter ones,
+// so the most specific route for a request wins.
+func orderedServiceAliasConfigs(state map[string]ServiceUnit) []ServiceAliasConfigEntry {
+	entries := []ServiceAliasConfigEntry{}
+	for _, serviceUnit := range state {
+		for key, cfg := range serviceUnit.ServiceAliasConfigs {
+			entries = append(entries, ServiceAliasConfigEntry{Key: key, Config: cfg})
+		}
+	}
+	sort.Sort(byPrecedence(entries))
+	return entries
+}
+
+// byPrecedence sorts entries from the most to the least specific route.
+type byPrecedence []ServiceAliasConfigEntry
+
+func (e byPrecedence) Len() int      { return len(e) }
+func (e byPrecedence) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+func (e byPrecedence) Less(i, j int) bool {
+	a, b := e[i].Config, e[j].Config
+	if a.IsWildcard != b.IsWildcard {
+		return !a.IsWildcard
+	}
+	if len(a.Path) != len(b.Path) {
+		return len(a.Path) > len(b.Path)
+	}
+	if a.Host != b.Host {
+		return a.Host < b.Host
+	}
+	return e[i].Key < e[j].Key
+}
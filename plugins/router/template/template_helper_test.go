@@ -0,0 +1,252 @@
+package templaterouter
+
+import (
+	"bytes"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+// TestRouteRegexp tests the requests matched by the regular expressions generated for configs
+func TestRouteRegexp(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       ServiceAliasConfig
+		matches   []string
+		unmatched []string
+	}{
+		{
+			name:      "host",
+			cfg:       ServiceAliasConfig{Host: "www.example.com"},
+			matches:   []string{"www.example.com", "www.example.com/", "www.example.com:80/foo"},
+			unmatched: []string{"wwwxexample.com/", "www.example.com.org/", "foo.www.example.com/"},
+		},
+		{
+			name:      "path",
+			cfg:       ServiceAliasConfig{Host: "www.example.com", Path: "/foo"},
+			matches:   []string{"www.example.com/foo", "www.example.com/foo/", "www.example.com:8080/foo/bar"},
+			unmatched: []string{"www.example.com/", "www.example.com/foobar", "www.example.com/bar/foo"},
+		},
+		{
+			name:      "path with trailing slash",
+			cfg:       ServiceAliasConfig{Host: "www.example.com", Path: "/foo/"},
+			matches:   []string{"www.example.com/foo", "www.example.com/foo/bar"},
+			unmatched: []string{"www.example.com/foobar"},
+		},
+		{
+			name:      "path with special characters",
+			cfg:       ServiceAliasConfig{Host: "www.example.com", Path: "/a.b+c"},
+			matches:   []string{"www.example.com/a.b+c/d"},
+			unmatched: []string{"www.example.com/axbbc", "www.example.com/a.bbc"},
+		},
+		{
+			name:      "wildcard",
+			cfg:       ServiceAliasConfig{Host: "www.apps.example.com", IsWildcard: true},
+			matches:   []string{"www.apps.example.com/", "other.apps.example.com:443/foo"},
+			unmatched: []string{"apps.example.com/", ".apps.example.com/", "a.b.apps.example.com/", "www.apps.example.org/"},
+		},
+		{
+			name:      "wildcard with path",
+			cfg:       ServiceAliasConfig{Host: "www.apps.example.com", Path: "/foo", IsWildcard: true},
+			matches:   []string{"other.apps.example.com/foo/bar"},
+			unmatched: []string{"other.apps.example.com/bar"},
+		},
+	}
+
+	for _, test := range tests {
+		re := regexp.MustCompile(test.cfg.RouteRegexp())
+		for _, s := range test.matches {
+			if !re.MatchString(s) {
+				t.Errorf("%s: expected %s to match %s", test.name, re, s)
+			}
+		}
+		for _, s := range test.unmatched {
+			if re.MatchString(s) {
+				t.Errorf("%s: expected %s not to match %s", test.name, re, s)
+			}
+		}
+	}
+
+	host := regexp.MustCompile(ServiceAliasConfig{Host: "www.apps.example.com", IsWildcard: true}.HostRegexp())
+	if !host.MatchString("other.apps.example.com") || !host.MatchString("other.apps.example.com:443") || host.MatchString("other.apps.example.com/") {
+		t.Errorf("unexpected host regexp %s", host)
+	}
+}
+
+// TestOrderedServiceAliasConfigs tests that more specific routes are listed first
+func TestOrderedServiceAliasConfigs(t *testing.T) {
+	state := map[string]ServiceUnit{
+		"ns/a": {
+			ServiceAliasConfigs: map[string]ServiceAliasConfig{
+				"ns-root":     {Host: "www.example.com"},
+				"ns-wildcard": {Host: "www.example.com", Path: "/foo/bar", IsWildcard: true},
+			},
+		},
+		"ns/b": {
+			ServiceAliasConfigs: map[string]ServiceAliasConfig{
+				"ns-foo":    {Host: "www.example.com", Path: "/foo"},
+				"ns-foobar": {Host: "www.example.com", Path: "/foo/bar"},
+				"ns-other":  {Host: "other.example.com", Path: "/foo"},
+			},
+		},
+	}
+
+	keys := []string{}
+	for _, entry := range orderedServiceAliasConfigs(state) {
+		keys = append(keys, entry.Key)
+	}
+	if e := []string{"ns-foobar", "ns-other", "ns-foo", "ns-root", "ns-wildcard"}; !reflect.DeepEqual(e, keys) {
+		t.Errorf("expected configs in order %v, got %v", e, keys)
+	}
+}
+
+// haproxyTemplates parses the HAProxy template shipped with the router image
+func haproxyTemplates() *template.Template {
+	return template.Must(template.New("config").Funcs(helperFunctions).Funcs(workingDirFunctions("/router")).ParseFiles("../../../images/router/haproxy/conf/haproxy-config.template"))
+}
+
+// render executes a template and returns the lines of its output which are not blank
+func render(t *testing.T, templates *template.Template, name string, state map[string]ServiceUnit) []string {
+	buffer := &bytes.Buffer{}
+	if err := templates.ExecuteTemplate(buffer, name, state); err != nil {
+		t.Fatalf("%s: unexpected error: %v", name, err)
+	}
+	lines := []string{}
+	for _, line := range strings.Split(buffer.String(), "\n") {
+		if line = strings.TrimSpace(line); len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// TestHAProxyMaps renders the maps of the HAProxy template shipped with the router image
+func TestHAProxyMaps(t *testing.T) {
+	templates := haproxyTemplates()
+	state := map[string]ServiceUnit{
+		"ns/a": {
+			ServiceAliasConfigs: map[string]ServiceAliasConfig{
+				"ns-root":     {Host: "www.example.com"},
+				"ns-wildcard": {Host: "www.apps.example.com", IsWildcard: true},
+				"ns-edge":     {Host: "secure.example.com", TLSTermination: "edge"},
+				"ns-allow":    {Host: "allow.example.com", TLSTermination: "edge", InsecureEdgeTerminationPolicy: "Allow"},
+				"ns-redirect": {Host: "redirect.example.com", TLSTermination: "edge", InsecureEdgeTerminationPolicy: "Redirect"},
+				"ns-pass":     {Host: "pass.example.com", TLSTermination: "passthrough"},
+			},
+		},
+		"ns/b": {
+			ServiceAliasConfigs: map[string]ServiceAliasConfig{
+				"ns-foo": {Host: "www.example.com", Path: "/foo"},
+			},
+		},
+	}
+
+	tests := map[string][]string{
+		"/var/lib/haproxy/conf/os_http_be.map": {
+			`^www\.example\.com(:[0-9]+)?/foo(/.*)?$ be_http_ns-foo`,
+			`^allow\.example\.com(:[0-9]+)?(/.*)?$ be_edge_http_ns-allow`,
+			`^redirect\.example\.com(:[0-9]+)?(/.*)?$ openshift_https_redirect`,
+			`^www\.example\.com(:[0-9]+)?(/.*)?$ be_http_ns-root`,
+			`^[^.]+\.apps\.example\.com(:[0-9]+)?(/.*)?$ be_http_ns-wildcard`,
+		},
+		"/var/lib/haproxy/conf/os_edge_http_be.map": {
+			`^allow\.example\.com(:[0-9]+)?(/.*)?$ ns-allow`,
+			`^redirect\.example\.com(:[0-9]+)?(/.*)?$ ns-redirect`,
+			`^secure\.example\.com(:[0-9]+)?(/.*)?$ ns-edge`,
+		},
+		"/var/lib/haproxy/conf/os_tcp_be.map": {
+			`^pass\.example\.com(:[0-9]+)?$ ns-pass`,
+		},
+		"/var/lib/haproxy/conf/os_sni_passthrough.map": {
+			`^pass\.example\.com(:[0-9]+)?$ 1`,
+		},
+	}
+
+	for name, expected := range tests {
+		lines := render(t, templates, name, state)
+		if !reflect.DeepEqual(expected, lines) {
+			t.Errorf("%s: expected entries\n%s\ngot\n%s", name, strings.Join(expected, "\n"), strings.Join(lines, "\n"))
+		}
+	}
+}
+
+// TestHAProxyBackends renders the backends of routes with custom settings
+func TestHAProxyBackends(t *testing.T) {
+	endpoints := map[string]Endpoint{"10.1.0.1:8080": {ID: "10.1.0.1:8080", IP: "10.1.0.1", Port: "8080"}}
+	state := map[string]ServiceUnit{
+		"ns/a": {
+			Name:          "ns/a",
+			EndpointTable: endpoints,
+			ServiceAliasConfigs: map[string]ServiceAliasConfig{
+				"ns-default":   {Host: "www.example.com"},
+				"ns-custom":    {Host: "custom.example.com", Timeout: "90s", Balance: "source", StickySessions: true},
+				"ns-edge":      {Host: "edge.example.com", TLSTermination: "edge", StickySessions: true, HSTSHeader: "max-age=100; preload"},
+				"ns-pass":      {Host: "pass.example.com", TLSTermination: "passthrough", Balance: "roundrobin"},
+				"ns-reencrypt": {Host: "reencrypt.example.com", TLSTermination: "reencrypt"},
+			},
+		},
+	}
+	lines := render(t, haproxyTemplates(), "/var/lib/haproxy/conf/haproxy.config", state)
+
+	// backends returns the settings of each backend, by name
+	backends := map[string][]string{}
+	name := ""
+	for _, line := range lines {
+		if strings.HasPrefix(line, "backend ") {
+			name = strings.TrimPrefix(line, "backend ")
+			continue
+		}
+		if len(name) > 0 {
+			backends[name] = append(backends[name], line)
+		}
+	}
+
+	tests := map[string][]string{
+		"be_http_ns-default": {
+			"balance leastconn",
+			"server ns-a 10.1.0.1:8080 check inter 5000ms",
+		},
+		"be_http_ns-custom": {
+			"balance source",
+			"timeout server 90s",
+			"cookie OPENSHIFT_ns-custom_SERVERID insert indirect nocache httponly",
+			"server ns-a 10.1.0.1:8080 check inter 5000ms cookie 10.1.0.1:8080",
+		},
+		"be_edge_http_ns-edge": {
+			"cookie OPENSHIFT_ns-edge_SERVERID insert indirect nocache httponly secure",
+			`http-response set-header Strict-Transport-Security "max-age=100; preload" if { ssl_fc }`,
+		},
+		"be_tcp_ns-pass": {
+			"balance roundrobin",
+		},
+		"be_secure_ns-reencrypt": {
+			"server ns-a 10.1.0.1:8080 ssl check inter 5000ms verify required ca-file /router/cacerts/reencrypt.example.com_pod.pem",
+		},
+		"be_sni": {
+			"bind 127.0.0.1:10444 ssl crt /router/certs accept-proxy",
+		},
+	}
+	for name, expected := range tests {
+		settings, ok := backends[name]
+		if !ok {
+			t.Errorf("expected backend %s, got %v", name, backends)
+			continue
+		}
+		for _, setting := range expected {
+			found := false
+			for _, line := range settings {
+				found = found || line == setting
+			}
+			if !found {
+				t.Errorf("%s: expected %q in\n%s", name, setting, strings.Join(settings, "\n"))
+			}
+		}
+	}
+	for _, line := range backends["be_http_ns-default"] {
+		if strings.HasPrefix(line, "timeout server") || strings.HasPrefix(line, "cookie") {
+			t.Errorf("expected the default settings for be_http_ns-default, got %q", line)
+		}
+	}
+}
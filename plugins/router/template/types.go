@@ -1,8 +1,10 @@
 package templaterouter
 
 import (
-	routeapi "github.com/openshift/origin/pkg/route/api"
+	"regexp"
 	"strings"
+
+	routeapi "github.com/openshift/origin/pkg/route/api"
 )
 
 // ServiceUnit is an encapsulation of a service, the endpoints that back that service, and the routes
@@ -23,10 +25,22 @@ type ServiceAliasConfig struct {
 	Host string
 	// Path is an optional path ie. www.example.com/myservice where "myservice" is the path
 	Path string
+	// IsWildcard indicates the config serves every host in the subdomain of Host
+	IsWildcard bool
 	// TLSTermination is the termination policy for this backend and drives the mapping files and router configuration
 	TLSTermination routeapi.TLSTerminationType
 	// Certificates used for securing this backend.  Keyed by the cert id
 	Certificates map[string]Certificate
+	// InsecureEdgeTerminationPolicy is what to do with insecure connections to an edge terminated backend
+	InsecureEdgeTerminationPolicy routeapi.InsecureEdgeTerminationPolicyType
+	// Timeout is the time to wait for a response from the endpoints, the router default if empty
+	Timeout string
+	// Balance is the algorithm balancing connections to the endpoints, the router default if empty
+	Balance string
+	// StickySessions sets a cookie sending the further requests of a client to the same endpoint
+	StickySessions bool
+	// HSTSHeader is the Strict-Transport-Security header added to secure responses, none if empty
+	HSTSHeader string
 }
 
 // Certificate represents a pub/private key pair.  It is identified by ID which is set to indicate if this is
@@ -49,3 +63,31 @@ type Endpoint struct {
 func (s ServiceUnit) TemplateSafeName() string {
 	return strings.Replace(s.Name, "/", "-", -1)
 }
+
+// RouteRegexp returns a regular expression matching the requests served by the config, as
+// seen by HAProxy in the host header followed by the path. A path matches itself and any
+// path below it, so /foo matches /foo and /foo/bar but not /foobar.
+func (c ServiceAliasConfig) RouteRegexp() string {
+	path := `(/.*)?`
+	if trimmed := strings.TrimRight(c.Path, "/"); len(trimmed) > 0 {
+		path = regexp.QuoteMeta(trimmed) + path
+	}
+	return "^" + c.hostPattern() + `(:[0-9]+)?` + path + "$"
+}
+
+// HostRegexp returns a regular expression matching the hosts served by the config, with
+// an optional port as found in the host header.
+func (c ServiceAliasConfig) HostRegexp() string {
+	return "^" + c.hostPattern() + `(:[0-9]+)?$`
+}
+
+// hostPattern returns the unanchored pattern for the hosts served by the config. A
+// wildcard config serves any single label in place of the first label of its host.
+func (c ServiceAliasConfig) hostPattern() string {
+	if c.IsWildcard {
+		if parts := strings.SplitN(c.Host, ".", 2); len(parts) == 2 {
+			return `[^.]+\.` + regexp.QuoteMeta(parts[1])
+		}
+	}
+	return regexp.QuoteMeta(c.Host)
+}
@@ -210,6 +210,7 @@ func NewTestBuildOpenshift(t *testing.T) *testBuildOpenshift {
 			return "registry:3000", true
 		}),
 		&fakeSubjectAccessReviewRegistry{},
+		0,
 	)
 	imageStreamRegistry := imagestream.NewRegistry(imageStreamStorage, imageStreamStatus)
 